@@ -1,45 +1,118 @@
 package main
 
 import (
-	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
-	"strings"
 
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/i18n"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
 	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
+	"github.com/Adhiana46/go-restapi-template/pkg/reporter"
 	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
-	"github.com/go-playground/validator/v10"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/gofiber/fiber/v2"
 )
 
-func handleError(c *fiber.Ctx, err error) error {
-	var statusCode int = 500
-	var message string = ""
+func errorHandler(translator i18n.Translator) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		return handleError(c, translator, err)
+	}
+}
+
+// Locals set while handling a request for its error report
+const (
+	errorLocalsKey    = "error"
+	panickedLocalsKey = "panicked"
+)
+
+func handleError(c *fiber.Ctx, translator i18n.Translator, err error) error {
+	c.Locals(errorLocalsKey, err)
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return renderError(c, fiberErr.Code, "", fiberErr.Message, nil)
+	}
+
+	appErr := apperror.From(err)
+	if appErr.Kind == apperror.KindInternal {
+		logging.FromFiber(c).Errorf("%s %s: %s", c.Method(), c.Path(), err)
+	}
+
+	trans := requestTranslator(c, translator)
+
 	var errorsData any = nil
+	if validationErrs := appErr.ValidationErrors(); validationErrs != nil {
+		errorsData = parserPkg.ValidationErrors(validationErrs, &trans)
+	}
 
-	if err == sql.ErrNoRows {
-		statusCode = 404
-	} else if strings.Contains(strings.ToLower(err.Error()), "query parameter") {
-		statusCode = 400
-		message = err.Error()
-	} else {
-		switch err.(type) {
-		case validator.ValidationErrors:
-			errs := err.(validator.ValidationErrors)
+	return renderError(c, appErr.Kind.HttpStatus(), string(appErr.Code), translator.Error(trans, appErr), errorsData)
+}
+
+// requestTranslator picks the message language from Accept-Language
+func requestTranslator(c *fiber.Ctx, translator i18n.Translator) ut.Translator {
+	trans := translator.FromAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+	c.Set(fiber.HeaderContentLanguage, trans.Locale())
 
-			statusCode = 400
-			errorsData = parserPkg.ValidationErrors(errs, &validateTrans)
-		default:
-			statusCode = 500
-		}
+	return trans
+}
+
+// renderError answers with application/problem+json when the client prefers it, the JsonError envelope otherwise
+func renderError(c *fiber.Ctx, statusCode int, code string, message string, errorsData any) error {
+	if c.Accepts(fiber.MIMEApplicationJSON, responsePkg.ProblemContentType) == responsePkg.ProblemContentType {
+		err := c.Status(statusCode).JSON(responsePkg.JsonProblem(statusCode, code, message, c.Path(), errorsData))
+		c.Set(fiber.HeaderContentType, responsePkg.ProblemContentType)
+		return err
 	}
 
-	return c.Status(statusCode).JSON(responsePkg.JsonError(statusCode, message, errorsData))
+	response := responsePkg.JsonError(statusCode, message, errorsData)
+	response.Code = code
+
+	return c.Status(statusCode).JSON(response)
 }
 
-func handlePanic(c *fiber.Ctx) {
+func handlePanic(c *fiber.Ctx, translator i18n.Translator, errReporter reporter.Reporter) {
 	if r := recover(); r != nil {
-		response := responsePkg.JsonError(http.StatusInternalServerError, "", nil)
+		report := reporter.Panic(fmt.Sprintf("panic in %s %s", c.Method(), c.Route().Path), r, requestFields(c))
+		logging.FromFiber(c).Errorf("panic in %s %s: %v\n%s", c.Method(), c.Path(), r, report.Stack)
+		errReporter.Report(report)
+		c.Locals(panickedLocalsKey, true)
+
+		appErr := apperror.New(apperror.KindInternal, apperror.CodeInternal, "")
+		renderError(c, http.StatusInternalServerError, string(appErr.Code), translator.Error(requestTranslator(c, translator), appErr), nil)
+	}
+}
 
-		c.Status(http.StatusInternalServerError).JSON(response)
+// reportResponse reports the responses with a status of at least minStatus, panics are reported
+// with their stack by handlePanic already
+func reportResponse(c *fiber.Ctx, errReporter reporter.Reporter, minStatus int) {
+	status := c.Response().StatusCode()
+	if status < minStatus || c.Locals(panickedLocalsKey) != nil {
+		return
 	}
+
+	message := http.StatusText(status)
+	if err, ok := c.Locals(errorLocalsKey).(error); ok {
+		message = err.Error()
+	}
+
+	errReporter.Report(reporter.Report{
+		Title:   fmt.Sprintf("%s %s responded %d", c.Method(), c.Route().Path, status),
+		Message: message,
+		Fields:  requestFields(c),
+	})
+}
+
+// requestFields are the log fields of the request, such as request_id, user or route parameters
+func requestFields(c *fiber.Ctx) map[string]any {
+	fields := map[string]any{
+		"method": c.Method(),
+		"path":   c.Path(),
+	}
+	for key, value := range logging.FromFiber(c).Data {
+		fields[key] = value
+	}
+
+	return fields
 }
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/i18n"
+	"github.com/Adhiana46/go-restapi-template/pkg/reporter"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// recordingReporter keeps the reports instead of sending them
+type recordingReporter struct {
+	reporter.Reporter
+
+	mu      sync.Mutex
+	reports []reporter.Report
+}
+
+func (r *recordingReporter) Report(report reporter.Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, report)
+}
+
+// errorRoutes answers every route with an error, the way handlers return them
+func errorRoutes(t *testing.T) *fiber.App {
+	return errorRoutesReporting(t, reporter.Nop())
+}
+
+func errorRoutesReporting(t *testing.T, errReporter reporter.Reporter) *fiber.App {
+	t.Helper()
+
+	validate := validator.New()
+	translator, err := i18n.NewTranslator(validate, "en")
+	if err != nil {
+		t.Fatalf("building translator: %s", err)
+	}
+
+	r := fiber.New(fiber.Config{ErrorHandler: errorHandler(translator)})
+	r.Get("/missing", func(c *fiber.Ctx) error {
+		return apperror.NotFound(apperror.CodeActivityGroupNotFound, "activity group not found")
+	})
+	r.Get("/invalid", func(c *fiber.Ctx) error {
+		type request struct {
+			Name string `json:"name" validate:"required"`
+		}
+		return apperror.Validation(validate.Struct(request{}))
+	})
+	r.Get("/panic", func(c *fiber.Ctx) error {
+		defer handlePanic(c, translator, errReporter)
+		panic("handler bug")
+	})
+
+	return r
+}
+
+func get(t *testing.T, r *fiber.App, path string, header map[string]string, v any) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	resp, err := r.Test(req)
+	if err != nil {
+		t.Fatalf("requesting %s: %s", path, err)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("decoding %s: %s", path, err)
+	}
+
+	return resp
+}
+
+func TestErrorEnvelope(t *testing.T) {
+	r := errorRoutes(t)
+
+	var body responsePkg.JsonResponse
+	resp := get(t, r, "/missing", nil, &body)
+	if resp.StatusCode != http.StatusNotFound || resp.Header.Get("Content-Type") != fiber.MIMEApplicationJSON {
+		t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if body.Status != http.StatusNotFound || body.Code != string(apperror.CodeActivityGroupNotFound) || body.Message != "Activity group not found" {
+		t.Fatalf("body %+v", body)
+	}
+
+	resp = get(t, r, "/panic", nil, &body)
+	if resp.StatusCode != http.StatusInternalServerError || body.Code != string(apperror.CodeInternal) {
+		t.Fatalf("panic answered %d %+v", resp.StatusCode, body)
+	}
+}
+
+func TestErrorProblem(t *testing.T) {
+	r := errorRoutes(t)
+
+	var problem responsePkg.Problem
+	resp := get(t, r, "/invalid", map[string]string{"Accept": responsePkg.ProblemContentType, "Accept-Language": "id"}, &problem)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != responsePkg.ProblemContentType {
+		t.Fatalf("content type %q", ct)
+	}
+	if resp.Header.Get("Content-Language") != "id" {
+		t.Fatalf("content language %q", resp.Header.Get("Content-Language"))
+	}
+	if problem.Type != "about:blank" || problem.Status != resp.StatusCode || problem.Title != http.StatusText(resp.StatusCode) ||
+		problem.Instance != "/invalid" || problem.Code != string(apperror.CodeValidationFailed) {
+		t.Fatalf("problem %+v", problem)
+	}
+
+	errs, _ := problem.Errors.(map[string]any)
+	if messages, _ := errs["Name"].([]any); len(messages) != 1 || messages[0] != "Name wajib diisi" {
+		t.Fatalf("errors %v, want the translated message of name", problem.Errors)
+	}
+
+	// application/json is preferred when both are accepted equally
+	var body responsePkg.JsonResponse
+	resp = get(t, r, "/missing", map[string]string{"Accept": "application/json, " + responsePkg.ProblemContentType}, &body)
+	if resp.Header.Get("Content-Type") != fiber.MIMEApplicationJSON || body.Code != string(apperror.CodeActivityGroupNotFound) {
+		t.Fatalf("content type %q, body %+v", resp.Header.Get("Content-Type"), body)
+	}
+}
+
+// TestErrorOmitsQuery makes sure the query string, which may carry a bearer token, stays out of reports and problems
+func TestErrorOmitsQuery(t *testing.T) {
+	errReporter := &recordingReporter{}
+	r := errorRoutesReporting(t, errReporter)
+
+	var problem responsePkg.Problem
+	get(t, r, "/panic?token=secret", map[string]string{"Accept": responsePkg.ProblemContentType}, &problem)
+	if problem.Instance != "/panic" {
+		t.Fatalf("instance %q, want the path alone", problem.Instance)
+	}
+	if len(errReporter.reports) != 1 || errReporter.reports[0].Fields["path"] != "/panic" {
+		t.Fatalf("reported %+v, want the path alone", errReporter.reports)
+	}
+}
@@ -1,95 +1,69 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
 	"time"
 
-	"github.com/Adhiana46/go-restapi-template/config"
-	"github.com/Adhiana46/go-restapi-template/internal/repository"
-	"github.com/Adhiana46/go-restapi-template/internal/service"
-	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
-	"github.com/go-playground/locales/id"
-	ut "github.com/go-playground/universal-translator"
-	"github.com/go-playground/validator/v10"
-	id_translations "github.com/go-playground/validator/v10/translations/id"
-	"github.com/ilyakaznacheev/cleanenv"
-	_ "github.com/jackc/pgx/stdlib"
-	"github.com/jmoiron/sqlx"
+	"github.com/Adhiana46/go-restapi-template/internal/app"
+	"github.com/gofiber/fiber/v2"
 	log "github.com/sirupsen/logrus"
-	easy "github.com/t-tomalak/logrus-easy-formatter"
 )
 
-var (
-	db *sqlx.DB
-
-	// utils
-	validate      *validator.Validate
-	validateTrans ut.Translator
-
-	// Repository
-	repoActivityGroup repository.ActivityGroupRepository
-	repoTodoItem      repository.TodoItemRepository
-
-	// Services
-	svcActivityGroup service.ActivityGroupService
-	svcTodoItem      service.TodoItemService
-)
-
-var cfg *config.Config
-
 func main() {
-	boot()
-	defer db.Close()
-
-	r := httpRoutes()
-
-	if err := r.Listen(fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)); err != nil {
-		log.Panicf("Can't start the server, error: %s", err)
-	}
-}
-
-func boot() {
-	log.SetReportCaller(true)
-	log.SetFormatter(&easy.Formatter{
-		TimestampFormat: time.RFC3339,
-		LogFormat:       "[%lvl%][%time%]: %msg%\n",
-	})
-	log.SetOutput(os.Stdout)
-
-	// Load environment variables
-	cfg = &config.Config{}
-	log.Infoln("load environment variables")
-	var err error
-	if _, err := os.Stat(".env"); err == nil {
-		err = cleanenv.ReadConfig(".env", cfg)
-	} else {
-		err = cleanenv.ReadEnv(cfg)
+	cfg, err := app.LoadConfig()
+	if err != nil {
+		log.Fatalf("Can't load configuration: %s", err)
 	}
-
+	app.SetupLogging(cfg.Log)
+
+	a, err := app.New(cfg,
+		app.WithTracing("api"),
+		app.WithReporter(),
+		app.WithDatabase(),
+		app.WithServices(),
+		app.WithEventHub(),
+	)
 	if err != nil {
-		log.Panicf("Can't read environment variable: %s", err)
+		log.Panicf("Can't boot the application: %s", err)
 	}
 
-	// validation & validation trans
-	log.Infoln("setup validation")
-	id := id.New()
-	uni := ut.New(id, id)
-	validateTrans, _ = uni.GetTranslator("id")
-	validate = validator.New()
-	id_translations.RegisterDefaultTranslations(validate, validateTrans)
+	r := httpRoutes(a)
+
+	err = a.Run(func(ctx context.Context) error {
+		stopped := make(chan error, 1)
+		go func() {
+			<-ctx.Done()
+			// SSE and WebSocket streams only end with their subscription, close them before waiting for requests
+			a.EventHub.Shutdown()
+			stopped <- shutdownWithTimeout(r, cfg.Server.ShutdownTimeout)
+		}()
+
+		if err := r.Listen(fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)); err != nil {
+			return err
+		}
 
-	log.Infoln("Connecting to database...")
-	db, err = sqldb.OpenConn(cfg)
+		// Listen returns as soon as the listener closes, the app is only stopped once the requests in flight are done
+		return <-stopped
+	})
 	if err != nil {
-		log.Panicf("Can't open database connection: %s", err)
+		log.Panicf("Can't start the server, error: %s", err)
 	}
+}
 
-	// repositories
-	repoActivityGroup = repository.NewPostgresActivityGroupRepository(db)
-	repoTodoItem = repository.NewPostgresTodoItemRepository(db)
-
-	// services
-	svcActivityGroup = service.NewActivityGroupService(validate, repoActivityGroup)
-	svcTodoItem = service.NewTodoItemService(validate, repoTodoItem, repoActivityGroup)
+// shutdownWithTimeout stops accepting connections and waits up to timeout for the requests in flight,
+// fiber v2.40 doesn't have ShutdownWithTimeout yet
+func shutdownWithTimeout(r *fiber.App, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Shutdown()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return errors.New("requests still running after the shutdown timeout")
+	}
 }
@@ -1,43 +1,108 @@
 package main
 
 import (
-	"time"
-
+	"github.com/Adhiana46/go-restapi-template/internal/app"
+	"github.com/Adhiana46/go-restapi-template/pkg/asyncapi"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	"github.com/Adhiana46/go-restapi-template/pkg/tracing"
+	graphqlTransport "github.com/Adhiana46/go-restapi-template/transport/graphql"
 	httpTransport "github.com/Adhiana46/go-restapi-template/transport/http"
+	queueTransport "github.com/Adhiana46/go-restapi-template/transport/queue"
+	wsTransport "github.com/Adhiana46/go-restapi-template/transport/ws"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
+	log "github.com/sirupsen/logrus"
 )
 
-func httpRoutes() *fiber.App {
+func httpRoutes(a *app.App) *fiber.App {
 	r := fiber.New(fiber.Config{
-		ErrorHandler: handleError,
+		ErrorHandler: errorHandler(a.Translator),
+		ReadTimeout:  a.Config.Server.ReadTimeout,
+		IdleTimeout:  a.Config.Server.IdleTimeout,
 	})
 
-	// Logger
-	r.Use(logger.New(logger.Config{
-		TimeFormat: time.RFC3339,
-		Done: func(c *fiber.Ctx, logString []byte) {
-			if c.Response().StatusCode() != fiber.StatusOK {
-				// reporter.SendToSlack(logString)
-			}
-		},
+	// Tracing, first so the span covers the whole request
+	r.Use(tracing.FiberMiddleware())
+
+	// Request ID and access log, inside the span so log lines carry its trace id
+	r.Use(logging.FiberMiddleware(func(c *fiber.Ctx) {
+		reportResponse(c, a.Reporter, a.Config.Reporter.MinStatus)
 	}))
 
+	// Database session, so with read-your-writes a request reads what it wrote
+	r.Use(sqldb.FiberMiddleware())
+
+	// Metrics, errors are rendered before recording so the status is the one the client gets
+	r.Use(metrics.FiberMiddleware(a.Metrics))
+
 	// Handle Panic
 	r.Use(func(c *fiber.Ctx) error {
-		defer handlePanic(c)
+		defer handlePanic(c, a.Translator, a.Reporter)
 		return c.Next()
 	})
 
 	api := r.Group("/api/v1")
+	spec := openapi.NewDocument("Todo API", "1.0.0")
+
+	// Probes
+	spec.AddOperations("/", httpTransport.
+		NewHealthHandler(a.Liveness, a.Readiness).
+		RegisterRoutes(r).
+		Docs()...)
 
 	// Register Handlers
-	httpTransport.
-		NewActivityGroupHandler(svcActivityGroup).
-		RegisterRoutes(api.Group("/activity-group"))
-	httpTransport.
-		NewTodoItemHandler(svcTodoItem).
-		RegisterRoutes(api.Group("/activity-group/:activity_uuid/todo-items"))
+	spec.AddOperations("/api/v1/activity-group", httpTransport.
+		NewActivityGroupHandler(a.Services.ActivityGroup).
+		RegisterRoutes(api.Group("/activity-group")).
+		Docs()...)
+	spec.AddOperations("/api/v1/activity-group", httpTransport.
+		NewActivityEventHandler(a.Config.Stream.SseHeartbeatInterval, a.EventHub, a.Services.ActivityGroup, a.Services.ActivityEvent).
+		RegisterRoutes(api.Group("/activity-group")).
+		Docs()...)
+	spec.AddOperations("/api/v1/activity-group/:activity_uuid/todo-items", httpTransport.
+		NewTodoItemHandler(a.Services.TodoItem).
+		RegisterRoutes(api.Group("/activity-group/:activity_uuid/todo-items")).
+		Docs()...)
+	spec.AddOperations("/api/v1/activity-group/:activity_uuid/todo-items/:todo_item_uuid/reminders", httpTransport.
+		NewTodoReminderHandler(a.Authenticator, a.Services.Notification).
+		RegisterRoutes(api.Group("/activity-group/:activity_uuid/todo-items/:todo_item_uuid/reminders")).
+		Docs()...)
+	spec.AddOperations("/api/v1/activity-group/:activity_uuid/webhooks", httpTransport.
+		NewWebhookHandler(a.Services.Webhook).
+		RegisterRoutes(api.Group("/activity-group/:activity_uuid/webhooks")).
+		Docs()...)
+	spec.AddOperations("/api/v1/scheduled-actions", httpTransport.
+		NewScheduledActionHandler(a.Services.ScheduledAction).
+		RegisterRoutes(api.Group("/scheduled-actions")).
+		Docs()...)
+	spec.AddOperations("/api/v1/notifications", httpTransport.
+		NewNotificationHandler(a.Authenticator, a.Services.Notification).
+		RegisterRoutes(api.Group("/notifications")).
+		Docs()...)
+	spec.AddOperations("/api/v1/diagnostics", httpTransport.
+		NewDiagnosticsHandler(a.DB).
+		RegisterRoutes(api.Group("/diagnostics")).
+		Docs()...)
+	spec.AddOperations("/api/v1/ws", wsTransport.
+		NewHandler(a.Authenticator, a.EventHub, a.Services.ActivityGroup, a.Services.TodoItem).
+		RegisterRoutes(api.Group("/ws")).
+		Docs()...)
+
+	graphqlHandler, err := graphqlTransport.NewHandler(a.Services.ActivityGroup, a.Services.TodoItem)
+	if err != nil {
+		log.Panicf("Can't build GraphQL schema: %s", err)
+	}
+	spec.AddOperations("/graphql", graphqlHandler.
+		RegisterRoutes(r.Group("/graphql")).
+		Docs()...)
+
+	// API documentation
+	r.Get("/api/openapi.json", openapi.FiberSpecHandler(spec))
+	r.Get("/api/docs", openapi.FiberUIHandler("/api/openapi.json"))
+	r.Get("/metrics", metrics.FiberHandler(a.Metrics))
+	r.Get("/api/asyncapi.json", asyncapi.FiberSpecHandler(queueTransport.NewAsyncAPIDocument(a.Config.Amqp.Queues.ActivityGroup, a.Config.Amqp.Queues.TodoItem, a.Config.Amqp.Queues.Notification)))
 
 	return r
 }
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Adhiana46/go-restapi-template/config"
+	"github.com/Adhiana46/go-restapi-template/internal/app"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+)
+
+var specParamPattern = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// TestOpenAPISpecMatchesRoutes fails when a route is registered without documentation or documented without a route
+func TestOpenAPISpecMatchesRoutes(t *testing.T) {
+	a, err := app.New(&config.Config{DefaultLocale: "id"})
+	if err != nil {
+		t.Fatalf("building app: %s", err)
+	}
+	r := httpRoutes(a)
+
+	resp, err := r.Test(httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	if err != nil {
+		t.Fatalf("fetching spec: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("fetching spec: status %d", resp.StatusCode)
+	}
+
+	spec := openapi.Document{}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatalf("decoding spec: %s", err)
+	}
+
+	documented := map[string]bool{}
+	for _, route := range spec.Routes() {
+		documented[specParamPattern.ReplaceAllString(route, ":$1")] = true
+	}
+
+	registered := map[string]bool{}
+	for _, route := range r.GetRoutes(true) {
+		if route.Method == http.MethodHead || strings.HasPrefix(route.Path, "/api/openapi.json") || strings.HasPrefix(route.Path, "/api/docs") || strings.HasPrefix(route.Path, "/api/asyncapi.json") || route.Path == "/metrics" {
+			continue
+		}
+		registered[route.Method+" "+openapi.JoinPath(route.Path, "")] = true
+	}
+
+	for _, route := range sortedKeys(registered) {
+		if !documented[route] {
+			t.Errorf("route %s is missing from the OpenAPI spec", route)
+		}
+	}
+	for _, route := range sortedKeys(documented) {
+		if !registered[route] {
+			t.Errorf("OpenAPI spec documents %s but no such route is registered", route)
+		}
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/app"
+	grpcTransport "github.com/Adhiana46/go-restapi-template/transport/grpc"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	cfg, err := app.LoadConfig()
+	if err != nil {
+		log.Fatalf("Can't load configuration: %s", err)
+	}
+	app.SetupLogging(cfg.Log)
+
+	a, err := app.New(cfg,
+		app.WithTracing("grpc"),
+		app.WithDatabase(),
+		app.WithServices(),
+		app.WithEventHub(),
+	)
+	if err != nil {
+		log.Panicf("Can't boot the application: %s", err)
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.GrpcPort))
+	if err != nil {
+		log.Panicf("Can't listen on gRPC port, error: %s", err)
+	}
+
+	s := grpcTransport.NewServer()
+	grpcTransport.RegisterActivityGroupServer(s, a.EventHub, a.Services.ActivityGroup, a.Services.ActivityEvent)
+	grpcTransport.RegisterTodoItemServer(s, a.EventHub, a.Services.ActivityGroup, a.Services.TodoItem, a.Services.ActivityEvent)
+
+	err = a.Run(func(ctx context.Context) error {
+		go func() {
+			<-ctx.Done()
+			// Watch streams only end with their subscription, close them before waiting for the calls in flight
+			a.EventHub.Shutdown()
+
+			stopped := make(chan struct{})
+			go func() {
+				s.GracefulStop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+			case <-time.After(cfg.Server.ShutdownTimeout):
+				log.Warnf("gRPC calls still running after %s, closing them", cfg.Server.ShutdownTimeout)
+				s.Stop()
+			}
+		}()
+
+		log.Infof("gRPC server listening on %s", lis.Addr())
+		return s.Serve(lis)
+	})
+	if err != nil {
+		log.Panicf("Can't start the server, error: %s", err)
+	}
+}
@@ -1,37 +1,116 @@
 package main
 
 import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/app"
+	"github.com/Adhiana46/go-restapi-template/internal/job"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
 	"github.com/Adhiana46/go-restapi-template/transport/queue"
-	amqp "github.com/rabbitmq/amqp091-go"
 	log "github.com/sirupsen/logrus"
 )
 
 type consumer struct {
 	workers []queue.QueueWorker
+	jobs    []job.Job
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
-func newConsumer(conn *amqp.Connection) (*consumer, error) {
+func newConsumer(a *app.App) (*consumer, error) {
+	cfg := a.Config
+	queueMetrics := metrics.NewQueueMetrics(a.Metrics)
+	replies := queue.NewReplies(a.Publisher, cfg.Amqp.Publisher.DurableReplies)
+
 	c := &consumer{
 		workers: []queue.QueueWorker{
-			queue.NewActivityGroupWorker(conn, "activity-group", svcActivityGroup),
-			queue.NewTodoItemWorker(conn, "todo-item", svcTodoItem),
+			queue.NewActivityGroupWorker(a.RabbitConn, replies, cfg.Amqp.Queues.ActivityGroup, cfg.Workers.Worker(cfg.Workers.ActivityGroup), a.Translator, queueMetrics, a.Reporter, a.Services.ActivityGroup, a.Services.ScheduledAction),
+			queue.NewTodoItemWorker(a.RabbitConn, replies, cfg.Amqp.Queues.TodoItem, cfg.Workers.Worker(cfg.Workers.TodoItem), a.Translator, queueMetrics, a.Reporter, a.Services.TodoItem, a.Services.ScheduledAction),
+		},
+		jobs: []job.Job{
+			job.NewWebhookDeliveryJob(cfg.Webhook.PollInterval, batchTimeout(service.WebhookDeliveryBatchSize, cfg.Webhook.Timeout, cfg.Database.Timeouts.Write), a.Services.Webhook),
+			job.NewActivityEventPruneJob(cfg.Stream.PruneInterval, cfg.Stream.EventRetention, a.Services.ActivityEvent),
+			job.NewTodoSeriesJob(cfg.Schedule.RecurrenceInterval, cfg.Database.Timeouts.Write, a.Services.TodoItem),
+			job.NewReminderJob(cfg.Schedule.ReminderInterval, cfg.Database.Timeouts.Write, a.Services.Notification),
+			job.NewNotificationDeliveryJob(cfg.Notification.PollInterval, batchTimeout(service.NotificationBatchSize, max(cfg.Webhook.Timeout, cfg.Notification.Smtp.Timeout), cfg.Database.Timeouts.Write), a.Services.Notification),
+			job.NewScheduledActionJob(cfg.Schedule.PollInterval, batchTimeout(service.ScheduledActionBatchSize, cfg.Amqp.Publisher.ConfirmTimeout, cfg.Database.Timeouts.Write), a.Services.ScheduledAction, queue.DispatchScheduled(a.Publisher)),
 		},
 	}
 
+	// appended after the connections and services, so it is stopped before them
+	a.Append(app.Hook{
+		Name:    "queue workers",
+		OnStart: c.start,
+		OnStop:  c.stop,
+	})
+
 	return c, nil
 }
 
-func (c *consumer) listen() error {
-	var forever chan struct{}
+// batchTimeout bounds a job run over a batch of size items sent one after the other, each taking up to send
+// and a write to record it
+func batchTimeout(size int, send time.Duration, write time.Duration) time.Duration {
+	return time.Duration(size) * (send + write)
+}
+
+// start runs the workers and jobs until stop
+func (c *consumer) start(_ context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
 
 	log.Infoln("Registering queue workers:")
 	for _, worker := range c.workers {
-		go worker.Listen()
+		c.wg.Add(1)
+		go func(worker queue.QueueWorker) {
+			defer c.wg.Done()
+			if err := worker.Listen(ctx); err != nil {
+				log.Errorf("[%s] stopped consuming: %s", worker.GetWorkerName(), err)
+			}
+		}(worker)
 		log.Infoln(" *", worker.GetWorkerName())
 	}
 
+	log.Infoln("Starting background jobs:")
+	for _, j := range c.jobs {
+		c.wg.Add(1)
+		go func(j job.Job) {
+			defer c.wg.Done()
+			if err := j.Run(ctx); err != nil && ctx.Err() == nil {
+				log.Errorf("[%s] stopped: %s", j.GetJobName(), err)
+			}
+		}(j)
+		log.Infoln(" *", j.GetJobName())
+	}
+
+	return nil
+}
+
+// stop cancels the consumers and the jobs and waits for the messages and ticks in flight
+func (c *consumer) stop(ctx context.Context) error {
+	c.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// listen blocks until ctx is cancelled, the workers are stopped with the app
+func (c *consumer) listen(ctx context.Context) error {
 	log.Infof("Waiting for messages. To exit press CTRL+C")
-	<-forever
+	<-ctx.Done()
 
 	return nil
 }
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Adhiana46/go-restapi-template/internal/app"
+	"github.com/Adhiana46/go-restapi-template/pkg/health"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
+)
+
+// withHealthServer serves /healthz and /readyz for the orchestrator, readiness includes
+// the consumer status of every worker
+func withHealthServer(a *app.App, c *consumer) {
+	for _, worker := range c.workers {
+		a.Readiness.Add("worker:"+worker.GetWorkerName(), func(ctx context.Context) error {
+			return worker.Status()
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", health.Handler(a.Liveness))
+	mux.Handle("/readyz", health.Handler(a.Readiness))
+
+	listenHTTP(a, "health listener", a.Config.Health.Port, mux)
+}
+
+// withMetricsServer serves /metrics for Prometheus
+func withMetricsServer(a *app.App) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(a.Metrics))
+
+	listenHTTP(a, "metrics listener", a.Config.Metrics.Port, mux)
+}
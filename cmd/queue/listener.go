@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Adhiana46/go-restapi-template/internal/app"
+	log "github.com/sirupsen/logrus"
+)
+
+// listenHTTP serves handler on port while the app is running
+func listenHTTP(a *app.App, name string, port string, handler http.Handler) {
+	srv := &http.Server{
+		Addr:    fmt.Sprintf("%s:%s", a.Config.Server.Host, port),
+		Handler: handler,
+	}
+
+	a.Append(app.Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			go func() {
+				log.Infof("%s listening on %s", name, srv.Addr)
+				if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Errorf("%s stopped: %s", name, err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return srv.Shutdown(ctx)
+		},
+	})
+}
@@ -1,21 +1,228 @@
 package config
 
+import "time"
+
+// Config is loaded from a YAML, TOML or .env file and overridden by environment variables,
+// fields tagged secret are redacted when printed
 type Config struct {
-	Host string `env:"HOST" env-default:""`
-	Port string `env:"PORT" env-default:"8000"`
-
-	// Database
-	DbHost    string `env:"DB_HOST" env-default:"localhost"`
-	DbPort    string `env:"DB_PORT" env-default:"5432"`
-	DbUser    string `env:"DB_USER" env-default:"user"`
-	DbPass    string `env:"DB_PASS" env-default:"secret"`
-	DbName    string `env:"DB_NAME" env-default:"todoapp"`
-	DbSSL     string `env:"DB_SSL" env-default:"disable"`
-	DbDialect string `env:"DB_DIALECT" env-default:"pgx"`
-
-	// RabbitMQ
-	AmqpHost string `env:"AMQP_HOST" env-default:"localhost"`
-	AmqpPort string `env:"AMQP_PORT" env-default:"5672"`
-	AmqpUser string `env:"AMQP_USER" env-default:"guest"`
-	AmqpPass string `env:"AMQP_PASS" env-default:"guest"`
+	Server       ServerConfig       `yaml:"server" toml:"server"`
+	Database     DatabaseConfig     `yaml:"database" toml:"database"`
+	Amqp         AmqpConfig         `yaml:"amqp" toml:"amqp"`
+	Workers      WorkersConfig      `yaml:"workers" toml:"workers"`
+	Webhook      WebhookConfig      `yaml:"webhook" toml:"webhook"`
+	Schedule     ScheduleConfig     `yaml:"schedule" toml:"schedule"`
+	Notification NotificationConfig `yaml:"notification" toml:"notification"`
+	Stream       StreamConfig       `yaml:"stream" toml:"stream"`
+	Log          LogConfig          `yaml:"log" toml:"log"`
+	Auth         AuthConfig         `yaml:"auth" toml:"auth"`
+	Health       HealthConfig       `yaml:"health" toml:"health"`
+	Metrics      MetricsConfig      `yaml:"metrics" toml:"metrics"`
+	Tracing      TracingConfig      `yaml:"tracing" toml:"tracing"`
+	Reporter     ReporterConfig     `yaml:"reporter" toml:"reporter"`
+
+	// Locale of validation and error messages when the client doesn't ask for a supported one
+	DefaultLocale string `yaml:"default_locale" toml:"default_locale" env:"DEFAULT_LOCALE" default:"id" validate:"oneof=en id"`
+}
+
+type ServerConfig struct {
+	Host            string        `yaml:"host" toml:"host" env:"HOST" default:""`
+	Port            string        `yaml:"port" toml:"port" env:"PORT" default:"8000" validate:"required,numeric"`
+	GrpcPort        string        `yaml:"grpc_port" toml:"grpc_port" env:"GRPC_PORT" default:"9090" validate:"required,numeric"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"SERVER_READ_TIMEOUT" default:"30s" validate:"gte=0"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" default:"120s" validate:"gte=0"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" default:"10s" validate:"gt=0"`
+}
+
+type DatabaseConfig struct {
+	Host    string     `yaml:"host" toml:"host" env:"DB_HOST" default:"localhost" validate:"required"`
+	Port    string     `yaml:"port" toml:"port" env:"DB_PORT" default:"5432" validate:"required,numeric"`
+	User    string     `yaml:"user" toml:"user" env:"DB_USER" default:"user" validate:"required"`
+	Pass    string     `yaml:"pass" toml:"pass" env:"DB_PASS" default:"secret" secret:"true"`
+	Name    string     `yaml:"name" toml:"name" env:"DB_NAME" default:"todoapp" validate:"required"`
+	SSL     string     `yaml:"ssl" toml:"ssl" env:"DB_SSL" default:"disable" validate:"oneof=disable allow prefer require verify-ca verify-full"`
+	Dialect string     `yaml:"dialect" toml:"dialect" env:"DB_DIALECT" default:"pgx" validate:"required"`
+	Pool    PoolConfig `yaml:"pool" toml:"pool"`
+
+	// StatementTimeout is set on every Postgres session, zero leaves the statement_timeout of the server in place
+	StatementTimeout time.Duration       `yaml:"statement_timeout" toml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT" default:"5s" validate:"gte=0"`
+	Timeouts         QueryTimeoutsConfig `yaml:"timeouts" toml:"timeouts"`
+
+	Replica ReplicaConfig `yaml:"replica" toml:"replica"`
+}
+
+// ReplicaConfig is an optional read replica sharing the credentials, database name and pool settings of the primary
+type ReplicaConfig struct {
+	// Host enables the replica, FindByUuid, FetchAll and CountAll are served from it
+	Host string `yaml:"host" toml:"host" env:"DB_REPLICA_HOST" default:""`
+	// Port defaults to the port of the primary
+	Port string `yaml:"port" toml:"port" env:"DB_REPLICA_PORT" default:"" validate:"omitempty,numeric"`
+	// ReadYourWrites pins a request or queue message to the primary once it has begun a transaction,
+	// so its later reads see what it wrote
+	ReadYourWrites bool `yaml:"read_your_writes" toml:"read_your_writes" env:"DB_REPLICA_READ_YOUR_WRITES" default:"true"`
+}
+
+type PoolConfig struct {
+	MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"60" validate:"min=1"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"30" validate:"min=0,ltefield=MaxOpenConns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"120s" validate:"gte=0"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" default:"20s" validate:"gte=0"`
+}
+
+// QueryTimeoutsConfig bounds the database work of a single service call by kind of operation
+type QueryTimeoutsConfig struct {
+	Read  time.Duration `yaml:"read" toml:"read" env:"DB_READ_TIMEOUT" default:"3s" validate:"gt=0"`
+	Write time.Duration `yaml:"write" toml:"write" env:"DB_WRITE_TIMEOUT" default:"3s" validate:"gt=0"`
+	Count time.Duration `yaml:"count" toml:"count" env:"DB_COUNT_TIMEOUT" default:"3s" validate:"gt=0"`
+}
+
+type AmqpConfig struct {
+	Host   string      `yaml:"host" toml:"host" env:"AMQP_HOST" default:"localhost" validate:"required"`
+	Port   string      `yaml:"port" toml:"port" env:"AMQP_PORT" default:"5672" validate:"required,numeric"`
+	User   string      `yaml:"user" toml:"user" env:"AMQP_USER" default:"guest" validate:"required"`
+	Pass   string      `yaml:"pass" toml:"pass" env:"AMQP_PASS" default:"guest" secret:"true"`
+	Queues QueueConfig `yaml:"queues" toml:"queues"`
+
+	Publisher PublisherConfig `yaml:"publisher" toml:"publisher"`
+}
+
+// PublisherConfig tunes the replies of the queue workers, published with confirms and persistent delivery
+type PublisherConfig struct {
+	// Channels is the number of idle confirm mode channels kept for reuse
+	Channels       int           `yaml:"channels" toml:"channels" env:"AMQP_PUBLISHER_CHANNELS" default:"4" validate:"min=1"`
+	ConfirmTimeout time.Duration `yaml:"confirm_timeout" toml:"confirm_timeout" env:"AMQP_PUBLISHER_CONFIRM_TIMEOUT" default:"5s" validate:"gt=0"`
+	// Retries is the number of attempts after the first one when a reply isn't confirmed
+	Retries int `yaml:"retries" toml:"retries" env:"AMQP_PUBLISHER_RETRIES" default:"3" validate:"min=0"`
+	// DurableReplies declares the reply and error queues durable, queues declared otherwise before must be deleted first
+	DurableReplies bool `yaml:"durable_replies" toml:"durable_replies" env:"AMQP_DURABLE_REPLIES" default:"true"`
+}
+
+// QueueConfig names the queue prefix of each worker, e.g. activity-group consumes activity-group.request
+type QueueConfig struct {
+	ActivityGroup string `yaml:"activity_group" toml:"activity_group" env:"AMQP_QUEUE_ACTIVITY_GROUP" default:"activity-group" validate:"required"`
+	TodoItem      string `yaml:"todo_item" toml:"todo_item" env:"AMQP_QUEUE_TODO_ITEM" default:"todo-item" validate:"required,nefield=ActivityGroup"`
+	// Notification is the durable queue reminders are published to for the users receiving them on the queue channel
+	Notification string `yaml:"notification" toml:"notification" env:"AMQP_QUEUE_NOTIFICATION" default:"notifications" validate:"required"`
+}
+
+// WorkersConfig is shared by the queue workers, each can override it in its own section
+type WorkersConfig struct {
+	// Prefetch is the number of unacknowledged messages a worker may hold
+	Prefetch int `yaml:"prefetch" toml:"prefetch" env:"WORKER_PREFETCH" default:"1" validate:"min=1"`
+	// Concurrency is the number of messages a worker handles at once
+	Concurrency int `yaml:"concurrency" toml:"concurrency" env:"WORKER_CONCURRENCY" default:"1" validate:"min=1"`
+
+	ActivityGroup WorkerConfig `yaml:"activity_group" toml:"activity_group" env-prefix:"WORKER_ACTIVITY_GROUP_"`
+	TodoItem      WorkerConfig `yaml:"todo_item" toml:"todo_item" env-prefix:"WORKER_TODO_ITEM_"`
+}
+
+// WorkerConfig overrides the shared worker settings, zero keeps the shared value
+type WorkerConfig struct {
+	Prefetch    int `yaml:"prefetch" toml:"prefetch" env:"PREFETCH" default:"0" validate:"min=0"`
+	Concurrency int `yaml:"concurrency" toml:"concurrency" env:"CONCURRENCY" default:"0" validate:"min=0"`
+	// Ordered handles related messages one at a time in delivery order, those of an activity group
+	// on the activity group worker, those of a todo item or creating one in an activity group on the todo item one
+	Ordered bool `yaml:"ordered" toml:"ordered" env:"ORDERED" default:"false"`
+}
+
+// Worker resolves the settings of a worker, its prefetch is raised to its concurrency so no goroutine idles
+func (c WorkersConfig) Worker(w WorkerConfig) WorkerConfig {
+	if w.Prefetch == 0 {
+		w.Prefetch = c.Prefetch
+	}
+	if w.Concurrency == 0 {
+		w.Concurrency = c.Concurrency
+	}
+	if w.Prefetch < w.Concurrency {
+		w.Prefetch = w.Concurrency
+	}
+
+	return w
+}
+
+type WebhookConfig struct {
+	Timeout      time.Duration `yaml:"timeout" toml:"timeout" env:"WEBHOOK_TIMEOUT" default:"10s" validate:"gt=0"`
+	PollInterval time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"WEBHOOK_POLL_INTERVAL" default:"5s" validate:"gt=0"`
+}
+
+// ScheduleConfig drives the jobs of the queue binary acting at a given time
+type ScheduleConfig struct {
+	// PollInterval of the queue requests delayed with execute_at
+	PollInterval time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"SCHEDULE_POLL_INTERVAL" default:"5s" validate:"gt=0"`
+	// RecurrenceInterval of the recurring todo items, their next occurrence is created at most this late
+	RecurrenceInterval time.Duration `yaml:"recurrence_interval" toml:"recurrence_interval" env:"RECURRENCE_POLL_INTERVAL" default:"30s" validate:"gt=0"`
+	// ReminderInterval of the todo item reminders, they fire at most this late
+	ReminderInterval time.Duration `yaml:"reminder_interval" toml:"reminder_interval" env:"REMINDER_POLL_INTERVAL" default:"30s" validate:"gt=0"`
+}
+
+// NotificationConfig configures the delivery of reminders, users pick their channels in their preferences
+type NotificationConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"NOTIFICATION_POLL_INTERVAL" default:"5s" validate:"gt=0"`
+	Smtp         SmtpConfig    `yaml:"smtp" toml:"smtp"`
+}
+
+// SmtpConfig is the server of the email channel, which is disabled while Host is empty
+type SmtpConfig struct {
+	Host     string        `yaml:"host" toml:"host" env:"SMTP_HOST" default:""`
+	Port     string        `yaml:"port" toml:"port" env:"SMTP_PORT" default:"587" validate:"required,numeric"`
+	Username string        `yaml:"username" toml:"username" env:"SMTP_USERNAME" default:""`
+	Password string        `yaml:"password" toml:"password" env:"SMTP_PASSWORD" default:"" secret:"true"`
+	From     string        `yaml:"from" toml:"from" env:"SMTP_FROM" default:"" validate:"required_with=Host,omitempty,email"`
+	Timeout  time.Duration `yaml:"timeout" toml:"timeout" env:"SMTP_TIMEOUT" default:"10s" validate:"gt=0"`
+}
+
+type StreamConfig struct {
+	PollInterval         time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"STREAM_POLL_INTERVAL" default:"1s" validate:"gt=0"`
+	SseHeartbeatInterval time.Duration `yaml:"sse_heartbeat_interval" toml:"sse_heartbeat_interval" env:"SSE_HEARTBEAT_INTERVAL" default:"15s" validate:"gt=0"`
+	EventRetention       time.Duration `yaml:"event_retention" toml:"event_retention" env:"EVENT_RETENTION" default:"24h" validate:"gt=0"`
+	PruneInterval        time.Duration `yaml:"prune_interval" toml:"prune_interval" env:"EVENT_PRUNE_INTERVAL" default:"1h" validate:"gt=0"`
+	// GapTimeout bounds how long live events wait behind a missing event id when the database can't tell
+	// whether the transaction that took it is still running
+	GapTimeout time.Duration `yaml:"gap_timeout" toml:"gap_timeout" env:"STREAM_GAP_TIMEOUT" default:"10s" validate:"gt=0"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level" toml:"level" env:"LOG_LEVEL" default:"info" validate:"oneof=trace debug info warn error fatal panic"`
+	// Format is json for log collectors, text for reading on a terminal
+	Format string `yaml:"format" toml:"format" env:"LOG_FORMAT" default:"json" validate:"oneof=json text"`
+}
+
+type AuthConfig struct {
+	// Tokens maps users to their access token, as comma separated user:token pairs in the environment
+	Tokens map[string]string `yaml:"tokens" toml:"tokens" env:"AUTH_TOKENS" default:"" secret:"true"`
+}
+
+type HealthConfig struct {
+	// Port of the /healthz and /readyz listener of the queue binary, the API serves them on its own port
+	Port string `yaml:"port" toml:"port" env:"HEALTH_PORT" default:"8081" validate:"required,numeric"`
+	// Timeout bounds each dependency check of /readyz
+	Timeout time.Duration `yaml:"timeout" toml:"timeout" env:"HEALTH_TIMEOUT" default:"2s" validate:"gt=0"`
+}
+
+type MetricsConfig struct {
+	// Port of the /metrics listener of the queue binary, the API serves it on its own port
+	Port string `yaml:"port" toml:"port" env:"METRICS_PORT" default:"9102" validate:"required,numeric"`
+}
+
+type TracingConfig struct {
+	// Exporter is none, stdout or otlp, the trace context is propagated either way
+	Exporter string `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER" default:"none" validate:"oneof=none stdout otlp"`
+	// Endpoint is the host:port of the OTLP gRPC collector
+	Endpoint string `yaml:"endpoint" toml:"endpoint" env:"TRACING_ENDPOINT" default:"localhost:4317" validate:"required_if=Exporter otlp"`
+	Insecure bool   `yaml:"insecure" toml:"insecure" env:"TRACING_INSECURE" default:"true"`
+	// ServiceName is suffixed with the binary, e.g. todoapp-api
+	ServiceName string  `yaml:"service_name" toml:"service_name" env:"TRACING_SERVICE_NAME" default:"todoapp" validate:"required"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1" validate:"gte=0,lte=1"`
+}
+
+type ReporterConfig struct {
+	// Sink is none, slack for Slack compatible incoming webhooks or http to POST the reports as JSON
+	Sink string `yaml:"sink" toml:"sink" env:"REPORTER_SINK" default:"none" validate:"oneof=none slack http"`
+	Url  string `yaml:"url" toml:"url" env:"REPORTER_URL" default:"" validate:"required_unless=Sink none,omitempty,url" secret:"true"`
+	// MinStatus is the lowest response status reported, panics are reported regardless
+	MinStatus int           `yaml:"min_status" toml:"min_status" env:"REPORTER_MIN_STATUS" default:"500" validate:"gte=300,lte=599"`
+	Timeout   time.Duration `yaml:"timeout" toml:"timeout" env:"REPORTER_TIMEOUT" default:"5s" validate:"gt=0"`
+	// DedupeWindow mutes the reports with the same title, e.g. a route failing repeatedly, for that long
+	DedupeWindow time.Duration `yaml:"dedupe_window" toml:"dedupe_window" env:"REPORTER_DEDUPE_WINDOW" default:"5m" validate:"gt=0"`
+	// RateLimit caps the reports sent per minute
+	RateLimit int `yaml:"rate_limit" toml:"rate_limit" env:"REPORTER_RATE_LIMIT" default:"30" validate:"gte=1"`
 }
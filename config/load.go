@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	"github.com/ilyakaznacheev/cleanenv"
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+// Load reads path (.yaml, .yml, .toml, .json or .env) and applies environment overrides on top,
+// without a path .env is read when present and the environment alone otherwise. Defaults are set
+// before reading so an explicit zero or false in the file or the environment is kept.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	if err := setDefaults(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, fmt.Errorf("setting configuration defaults: %w", err)
+	}
+
+	if path == "" {
+		if _, err := os.Stat(".env"); err == nil {
+			path = ".env"
+		}
+	}
+
+	var err error
+	if path != "" {
+		err = cleanenv.ReadConfig(path, cfg)
+	} else {
+		err = cleanenv.ReadEnv(cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration: %w", err)
+	}
+
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// setDefaults sets every field to its default tag. It isn't left to cleanenv's env-default, which is
+// applied after the file to every field still zero and so overwrites an explicit zero or false.
+func setDefaults(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+
+		if value.Kind() == reflect.Struct {
+			if err := setDefaults(value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		def := field.Tag.Get("default")
+		if def == "" {
+			continue
+		}
+
+		var err error
+		switch {
+		case value.Type() == reflect.TypeOf(time.Duration(0)):
+			var d time.Duration
+			d, err = time.ParseDuration(def)
+			value.SetInt(int64(d))
+		case value.Kind() == reflect.String:
+			value.SetString(def)
+		case value.Kind() == reflect.Bool:
+			var b bool
+			b, err = strconv.ParseBool(def)
+			value.SetBool(b)
+		case value.Kind() == reflect.Int:
+			var n int64
+			n, err = strconv.ParseInt(def, 10, 0)
+			value.SetInt(n)
+		case value.Kind() == reflect.Float64:
+			var f float64
+			f, err = strconv.ParseFloat(def, 64)
+			value.SetFloat(f)
+		default:
+			err = fmt.Errorf("unsupported type %s", value.Type())
+		}
+		if err != nil {
+			return fmt.Errorf("default of %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate reports every invalid field by its configuration file path, e.g. database.pool.max_open_conns
+func Validate(cfg *Config) error {
+	locale := en.New()
+	trans, _ := ut.New(locale, locale).GetTranslator(locale.Locale())
+
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("yaml"), ",")[0]
+	})
+	en_translations.RegisterDefaultTranslations(validate, trans)
+
+	err := validate.Struct(cfg)
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return err
+	}
+
+	msgs := []string{}
+	for _, e := range validationErrs {
+		_, path, _ := strings.Cut(e.Namespace(), ".")
+		msgs = append(msgs, fmt.Sprintf("%s: %s", path, e.Translate(trans)))
+	}
+
+	return fmt.Errorf("invalid configuration: %s", strings.Join(msgs, "; "))
+}
+
+// Redacted renders cfg as YAML with the values of secret fields replaced
+func Redacted(cfg *Config) ([]byte, error) {
+	copied := *cfg
+	redact(reflect.ValueOf(&copied).Elem())
+
+	return yaml.Marshal(copied)
+}
+
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+
+		switch {
+		case value.Kind() == reflect.Struct:
+			redact(value)
+		case field.Tag.Get("secret") != "true":
+			continue
+		case value.Kind() == reflect.String && value.Len() > 0:
+			value.SetString(redacted)
+		case value.Kind() == reflect.Map && value.Len() > 0:
+			// the map is shared with the original config, redact a copy
+			masked := reflect.MakeMapWithSize(value.Type(), value.Len())
+			for _, key := range value.MapKeys() {
+				masked.SetMapIndex(key, reflect.ValueOf(redacted))
+			}
+			value.Set(masked)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing config: %s", err)
+	}
+
+	return path
+}
+
+// TestLoadKeepsExplicitZero makes sure a zero or false in the file isn't replaced by the default of the field
+func TestLoadKeepsExplicitZero(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  statement_timeout: 0s
+  pool:
+    max_idle_conns: 0
+  replica:
+    read_your_writes: false
+amqp:
+  publisher:
+    retries: 0
+    durable_replies: false
+tracing:
+  insecure: false
+  sample_ratio: 0
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("loading: %s", err)
+	}
+
+	if cfg.Database.StatementTimeout != 0 {
+		t.Errorf("statement_timeout %s, want 0", cfg.Database.StatementTimeout)
+	}
+	if cfg.Database.Pool.MaxIdleConns != 0 {
+		t.Errorf("max_idle_conns %d, want 0", cfg.Database.Pool.MaxIdleConns)
+	}
+	if cfg.Database.Replica.ReadYourWrites {
+		t.Error("read_your_writes true, want false")
+	}
+	if cfg.Amqp.Publisher.Retries != 0 {
+		t.Errorf("retries %d, want 0", cfg.Amqp.Publisher.Retries)
+	}
+	if cfg.Amqp.Publisher.DurableReplies {
+		t.Error("durable_replies true, want false")
+	}
+	if cfg.Tracing.Insecure {
+		t.Error("insecure true, want false")
+	}
+	if cfg.Tracing.SampleRatio != 0 {
+		t.Errorf("sample_ratio %v, want 0", cfg.Tracing.SampleRatio)
+	}
+}
+
+func TestLoadDefaultsOmittedFields(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  port: "8080"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("loading: %s", err)
+	}
+
+	if cfg.Server.Port != "8080" {
+		t.Errorf("port %q, want the one in the file", cfg.Server.Port)
+	}
+	if cfg.Server.GrpcPort != "9090" {
+		t.Errorf("grpc_port %q, want 9090", cfg.Server.GrpcPort)
+	}
+	if cfg.Database.StatementTimeout != 5*time.Second {
+		t.Errorf("statement_timeout %s, want 5s", cfg.Database.StatementTimeout)
+	}
+	if !cfg.Database.Replica.ReadYourWrites || !cfg.Amqp.Publisher.DurableReplies {
+		t.Error("read_your_writes and durable_replies should default to true")
+	}
+	if cfg.Tracing.SampleRatio != 1 {
+		t.Errorf("sample_ratio %v, want 1", cfg.Tracing.SampleRatio)
+	}
+}
+
+func TestLoadEnvironmentOverridesFile(t *testing.T) {
+	path := writeConfig(t, `
+database:
+  statement_timeout: 2s
+  replica:
+    read_your_writes: true
+`)
+	t.Setenv("DB_STATEMENT_TIMEOUT", "0s")
+	t.Setenv("DB_REPLICA_READ_YOUR_WRITES", "false")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("loading: %s", err)
+	}
+
+	if cfg.Database.StatementTimeout != 0 {
+		t.Errorf("statement_timeout %s, want the 0s of the environment", cfg.Database.StatementTimeout)
+	}
+	if cfg.Database.Replica.ReadYourWrites {
+		t.Error("read_your_writes true, want the false of the environment")
+	}
+}
+
+// TestLoadRejectsZeroReporterDurations makes sure the reporter can't be configured to give up on every report or never dedupe
+func TestLoadRejectsZeroReporterDurations(t *testing.T) {
+	for _, field := range []string{"timeout", "dedupe_window"} {
+		path := writeConfig(t, "reporter:\n  "+field+": 0s\n")
+		if _, err := Load(path); err == nil {
+			t.Errorf("loaded reporter.%s 0s, want a validation error", field)
+		}
+	}
+}
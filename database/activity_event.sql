@@ -0,0 +1,12 @@
+CREATE TABLE activity_event
+(
+	id BIGSERIAL NOT NULL,
+	activity_uuid CHAR(36) NOT NULL,
+	event VARCHAR(100) NOT NULL,
+	payload TEXT NOT NULL,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+
+	PRIMARY KEY (id)
+);
+
+CREATE INDEX activity_event_activity_idx ON activity_event (activity_uuid, id);
@@ -0,0 +1,65 @@
+CREATE SEQUENCE notification_preference_seq;
+
+CREATE TABLE notification_preference
+(
+	id INT NOT NULL DEFAULT NEXTVAL ('notification_preference_seq'),
+	username VARCHAR(255) NOT NULL UNIQUE,
+	email_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+	email VARCHAR(255) NOT NULL DEFAULT '',
+	webhook_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+	webhook_url VARCHAR(2048) NOT NULL DEFAULT '',
+	webhook_secret VARCHAR(255) NOT NULL DEFAULT '',
+	queue_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+
+	PRIMARY KEY (id)
+);
+
+CREATE SEQUENCE todo_reminder_seq;
+
+CREATE TABLE todo_reminder
+(
+	id INT NOT NULL DEFAULT NEXTVAL ('todo_reminder_seq'),
+	uuid CHAR(36) NOT NULL UNIQUE,
+	todo_item_id INT NOT NULL,
+	username VARCHAR(255) NOT NULL,
+	offset_minutes INT NOT NULL DEFAULT 0,
+	fired_due_at TIMESTAMP(0) NULL,
+	fired_at TIMESTAMP(0) NULL,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+
+	FOREIGN KEY (todo_item_id) REFERENCES todo_item(id) ON DELETE CASCADE,
+	UNIQUE (todo_item_id, username, offset_minutes),
+
+	PRIMARY KEY (id)
+);
+
+CREATE SEQUENCE notification_delivery_seq;
+
+CREATE TABLE notification_delivery
+(
+	id INT NOT NULL DEFAULT NEXTVAL ('notification_delivery_seq'),
+	uuid CHAR(36) NOT NULL UNIQUE,
+	reminder_id INT NULL,
+	username VARCHAR(255) NOT NULL,
+	channel VARCHAR(20) NOT NULL,
+	recipient VARCHAR(2048) NOT NULL DEFAULT '',
+	event VARCHAR(100) NOT NULL,
+	payload TEXT NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	attempts INT NOT NULL DEFAULT 0,
+	last_error TEXT NULL,
+	next_attempt_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	delivered_at TIMESTAMP(0) NULL,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+
+	FOREIGN KEY (reminder_id) REFERENCES todo_reminder(id) ON DELETE SET NULL,
+
+	PRIMARY KEY (id)
+);
+
+CREATE INDEX notification_delivery_pending_idx ON notification_delivery (status, next_attempt_at);
+CREATE INDEX notification_delivery_user_idx ON notification_delivery (username, created_at);
@@ -0,0 +1,24 @@
+CREATE SEQUENCE scheduled_action_seq;
+
+CREATE TABLE scheduled_action
+(
+	id INT NOT NULL DEFAULT NEXTVAL ('scheduled_action_seq'),
+	uuid CHAR(36) NOT NULL UNIQUE,
+	queue VARCHAR(100) NOT NULL,
+	action VARCHAR(50) NOT NULL,
+	version INT NOT NULL DEFAULT 1,
+	data TEXT NOT NULL,
+	locale VARCHAR(20) NOT NULL DEFAULT '',
+	activity_uuid CHAR(36) NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	execute_at TIMESTAMP(0) NOT NULL,
+	dispatched_at TIMESTAMP(0) NULL,
+	cancelled_at TIMESTAMP(0) NULL,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+
+	PRIMARY KEY (id)
+);
+
+CREATE INDEX scheduled_action_due_idx ON scheduled_action (status, execute_at);
+CREATE INDEX scheduled_action_activity_idx ON scheduled_action (activity_uuid);
@@ -0,0 +1,14 @@
+-- every change to the scripts in database/ adds a row here and bumps repository.SchemaVersion,
+-- the readiness check holds the service back until the version it was built for is applied
+CREATE TABLE schema_migration
+(
+	version INT NOT NULL,
+	description TEXT,
+	applied_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (version)
+);
+
+INSERT INTO schema_migration
+(version, description)
+VALUES
+(1, 'activity groups, todo items and series, webhooks, activity events, scheduled actions and notifications');
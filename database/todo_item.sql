@@ -1,3 +1,26 @@
+CREATE SEQUENCE todo_series_seq;
+
+CREATE TABLE todo_series
+(
+	id INT NOT NULL DEFAULT NEXTVAL ('todo_series_seq'),
+	uuid CHAR(36) NOT NULL UNIQUE,
+	activity_id INT NOT NULL,
+	name VARCHAR(255),
+	description TEXT,
+	rrule VARCHAR(255) NOT NULL,
+	start_at TIMESTAMP(0) NOT NULL,
+	occurrences INT NOT NULL DEFAULT 1,
+	current_due_at TIMESTAMP(0) NOT NULL,
+	current_item_id INT NULL,
+	ended_at TIMESTAMP(0) NULL,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+
+	FOREIGN KEY (activity_id) REFERENCES activity_group(id) ON DELETE CASCADE,
+
+	PRIMARY KEY (id)
+);
+
 CREATE SEQUENCE todo_item_seq;
 
 CREATE TABLE todo_item
@@ -7,10 +30,18 @@ CREATE TABLE todo_item
     activity_id INT NOT NULL,
 	name VARCHAR(255),
 	description TEXT,
+	due_at TIMESTAMP(0) NULL,
+	series_id INT NULL,
+	completed_at TIMESTAMP(0) NULL,
 	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
 	updated_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
 
     FOREIGN KEY (activity_id) REFERENCES activity_group(id) ON DELETE CASCADE,
+	FOREIGN KEY (series_id) REFERENCES todo_series(id) ON DELETE SET NULL,
 
 	PRIMARY KEY (id)
-);
\ No newline at end of file
+);
+
+ALTER TABLE todo_series ADD FOREIGN KEY (current_item_id) REFERENCES todo_item(id) ON DELETE SET NULL;
+
+CREATE INDEX todo_series_open_idx ON todo_series (ended_at);
@@ -0,0 +1,64 @@
+CREATE SEQUENCE webhook_endpoint_seq;
+
+CREATE TABLE webhook_endpoint
+(
+	id INT NOT NULL DEFAULT NEXTVAL ('webhook_endpoint_seq'),
+	uuid CHAR(36) NOT NULL UNIQUE,
+	activity_id INT NOT NULL,
+	url VARCHAR(2048) NOT NULL,
+	secret VARCHAR(255) NOT NULL,
+	events TEXT NOT NULL DEFAULT '*',
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	failure_count INT NOT NULL DEFAULT 0,
+	disabled_at TIMESTAMP(0) NULL,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+
+	FOREIGN KEY (activity_id) REFERENCES activity_group(id) ON DELETE CASCADE,
+
+	PRIMARY KEY (id)
+);
+
+CREATE SEQUENCE webhook_delivery_seq;
+
+CREATE TABLE webhook_delivery
+(
+	id INT NOT NULL DEFAULT NEXTVAL ('webhook_delivery_seq'),
+	uuid CHAR(36) NOT NULL UNIQUE,
+	endpoint_id INT NULL,
+	url VARCHAR(2048) NOT NULL,
+	secret VARCHAR(255) NOT NULL,
+	event VARCHAR(100) NOT NULL,
+	payload TEXT NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	attempts INT NOT NULL DEFAULT 0,
+	last_status_code INT NULL,
+	last_error TEXT NULL,
+	next_attempt_at TIMESTAMP(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	delivered_at TIMESTAMP(0) NULL,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+
+	FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoint(id) ON DELETE SET NULL,
+
+	PRIMARY KEY (id)
+);
+
+CREATE INDEX webhook_delivery_pending_idx ON webhook_delivery (status, next_attempt_at);
+
+CREATE SEQUENCE webhook_delivery_attempt_seq;
+
+CREATE TABLE webhook_delivery_attempt
+(
+	id INT NOT NULL DEFAULT NEXTVAL ('webhook_delivery_attempt_seq'),
+	delivery_id INT NOT NULL,
+	attempt INT NOT NULL,
+	status_code INT NULL,
+	error TEXT NULL,
+	duration_ms INT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP(0) DEFAULT CURRENT_TIMESTAMP,
+
+	FOREIGN KEY (delivery_id) REFERENCES webhook_delivery(id) ON DELETE CASCADE,
+
+	PRIMARY KEY (id)
+);
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Adhiana46/go-restapi-template/config"
+	"github.com/Adhiana46/go-restapi-template/internal/auth"
+	"github.com/Adhiana46/go-restapi-template/internal/event"
+	"github.com/Adhiana46/go-restapi-template/internal/i18n"
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/Adhiana46/go-restapi-template/pkg/health"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
+	"github.com/Adhiana46/go-restapi-template/pkg/rabbitmq"
+	"github.com/Adhiana46/go-restapi-template/pkg/reporter"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	"github.com/go-playground/validator/v10"
+	"github.com/prometheus/client_golang/prometheus"
+	amqp "github.com/rabbitmq/amqp091-go"
+	log "github.com/sirupsen/logrus"
+)
+
+// Hook is a lifecycle callback pair, OnStart must not block and hooks are stopped in reverse order
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+type Repositories struct {
+	ActivityGroup   repository.ActivityGroupRepository
+	TodoItem        repository.TodoItemRepository
+	TodoSeries      repository.TodoSeriesRepository
+	Webhook         repository.WebhookRepository
+	ActivityEvent   repository.ActivityEventRepository
+	ScheduledAction repository.ScheduledActionRepository
+	TodoReminder    repository.TodoReminderRepository
+	Notification    repository.NotificationRepository
+}
+
+type Services struct {
+	ActivityGroup   service.ActivityGroupService
+	TodoItem        service.TodoItemService
+	Webhook         service.WebhookService
+	ActivityEvent   service.ActivityEventService
+	ScheduledAction service.ScheduledActionService
+	Notification    service.NotificationService
+}
+
+// App is the dependency container shared by the binaries, components are added with options
+// so each binary only opens the connections it needs
+type App struct {
+	Config *config.Config
+
+	// utils
+	Validate      *validator.Validate
+	Translator    i18n.Translator
+	Dispatcher    event.Dispatcher
+	Authenticator auth.Authenticator
+
+	// connections
+	DB         *sqldb.DB
+	RabbitConn *amqp.Connection
+	Publisher  rabbitmq.Publisher
+
+	Repositories Repositories
+	Services     Services
+
+	// Live event stream
+	EventHub stream.Hub
+
+	// Liveness and Readiness back /healthz and /readyz, options add a readiness check
+	// for every connection they open
+	Liveness  health.Checker
+	Readiness health.Checker
+
+	// Metrics is served on /metrics, options register the collectors of what they open
+	Metrics *prometheus.Registry
+
+	// Reporter alerts on failed responses and panics, it discards them unless WithReporter is used
+	Reporter reporter.Reporter
+
+	hooks   []Hook
+	started int
+}
+
+type Option func(a *App) error
+
+func New(cfg *config.Config, opts ...Option) (*App, error) {
+	a := &App{
+		Config:        cfg,
+		Validate:      validator.New(),
+		Dispatcher:    event.NewDispatcher(),
+		Authenticator: auth.NewTokenAuthenticator(cfg.Auth.Tokens),
+		Liveness:      health.NewChecker(cfg.Health.Timeout),
+		Readiness:     health.NewChecker(cfg.Health.Timeout),
+		Metrics:       metrics.NewRegistry(),
+		Reporter:      reporter.Nop(),
+	}
+
+	// validation & validation trans
+	if err := service.RegisterValidations(a.Validate); err != nil {
+		return nil, err
+	}
+	translator, err := i18n.NewTranslator(a.Validate, cfg.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+	a.Translator = translator
+
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			// release whatever the previous options opened
+			a.Stop(context.Background())
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// Append registers a lifecycle hook, hooks run in the order they are appended
+func (a *App) Append(hook Hook) {
+	a.hooks = append(a.hooks, hook)
+}
+
+// Start runs the OnStart hooks, on failure the hooks that already started are stopped
+func (a *App) Start(ctx context.Context) error {
+	for a.started < len(a.hooks) {
+		hook := a.hooks[a.started]
+		if hook.OnStart != nil {
+			log.Infof("starting %s", hook.Name)
+			if err := hook.OnStart(ctx); err != nil {
+				a.Stop(ctx)
+				return err
+			}
+		}
+		a.started++
+	}
+
+	return nil
+}
+
+// Stop runs the OnStop hooks in reverse order, hooks without OnStart are stopped even if Start was never called
+func (a *App) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(a.hooks) - 1; i >= 0; i-- {
+		hook := a.hooks[i]
+		if hook.OnStop == nil || (hook.OnStart != nil && i >= a.started) {
+			continue
+		}
+
+		log.Infof("stopping %s", hook.Name)
+		if err := hook.OnStop(ctx); err != nil {
+			log.Errorf("stopping %s: %s", hook.Name, err)
+			errs = append(errs, err)
+		}
+	}
+	a.hooks, a.started = nil, 0
+
+	return errors.Join(errs...)
+}
+
+// Run starts the app and blocks on serve, whose context is cancelled on SIGINT or SIGTERM.
+// The app is stopped once serve returns.
+func (a *App) Run(serve func(ctx context.Context) error) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := a.Start(ctx); err != nil {
+		return err
+	}
+
+	serveErr := serve(ctx)
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), a.Config.Server.ShutdownTimeout)
+	defer stopCancel()
+
+	return errors.Join(serveErr, a.Stop(stopCtx))
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Adhiana46/go-restapi-template/config"
+)
+
+// recordHook appends start:name and stop:name to calls, failing OnStart when failStart is set
+func recordHook(calls *[]string, name string, failStart bool) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			*calls = append(*calls, "start:"+name)
+			if failStart {
+				return errors.New(name + " unreachable")
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			*calls = append(*calls, "stop:"+name)
+			return nil
+		},
+	}
+}
+
+func newTestApp(t *testing.T, opts ...Option) *App {
+	t.Helper()
+
+	a, err := New(&config.Config{DefaultLocale: "en"}, opts...)
+	if err != nil {
+		t.Fatalf("building app: %s", err)
+	}
+
+	return a
+}
+
+func TestHooksStartInOrderAndStopInReverse(t *testing.T) {
+	calls := []string{}
+	a := newTestApp(t)
+	a.Append(recordHook(&calls, "database", false))
+	a.Append(Hook{Name: "reporter", OnStop: func(ctx context.Context) error {
+		calls = append(calls, "stop:reporter")
+		return nil
+	}})
+	a.Append(recordHook(&calls, "rabbitmq", false))
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("starting: %s", err)
+	}
+	if err := a.Stop(context.Background()); err != nil {
+		t.Fatalf("stopping: %s", err)
+	}
+
+	if got := strings.Join(calls, " "); got != "start:database start:rabbitmq stop:rabbitmq stop:reporter stop:database" {
+		t.Fatalf("calls %s", got)
+	}
+}
+
+func TestFailedStartStopsStartedHooks(t *testing.T) {
+	calls := []string{}
+	a := newTestApp(t)
+	a.Append(recordHook(&calls, "database", false))
+	a.Append(recordHook(&calls, "rabbitmq", true))
+	a.Append(recordHook(&calls, "hub", false))
+
+	if err := a.Start(context.Background()); err == nil {
+		t.Fatal("start succeeded with rabbitmq unreachable")
+	}
+
+	// rabbitmq never started, so it isn't stopped, and hub never ran at all
+	if got := strings.Join(calls, " "); got != "start:database start:rabbitmq stop:database" {
+		t.Fatalf("calls %s", got)
+	}
+}
+
+func TestFailedOptionReleasesPreviousOptions(t *testing.T) {
+	calls := []string{}
+	opened := func(a *App) error {
+		a.Append(Hook{Name: "database", OnStop: func(ctx context.Context) error {
+			calls = append(calls, "stop:database")
+			return nil
+		}})
+		return nil
+	}
+
+	_, err := New(&config.Config{DefaultLocale: "en"}, opened, WithServices())
+	if err == nil || !strings.Contains(err.Error(), "services need the repositories") {
+		t.Fatalf("services without repositories returned %v", err)
+	}
+	if strings.Join(calls, " ") != "stop:database" {
+		t.Fatalf("calls %v, want the database of the previous option closed", calls)
+	}
+}
+
+func TestNewRejectsUnsupportedLocale(t *testing.T) {
+	if _, err := New(&config.Config{DefaultLocale: "fr"}); err == nil {
+		t.Fatal("fr accepted as default locale")
+	}
+}
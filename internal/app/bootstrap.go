@@ -0,0 +1,56 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/config"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	log "github.com/sirupsen/logrus"
+)
+
+func SetupLogging(cfg config.LogConfig) {
+	log.SetReportCaller(true)
+	switch cfg.Format {
+	case logging.FormatText:
+		log.SetFormatter(&log.TextFormatter{
+			FullTimestamp:   true,
+			TimestampFormat: time.RFC3339,
+		})
+	default:
+		log.SetFormatter(&log.JSONFormatter{
+			TimestampFormat: time.RFC3339Nano,
+		})
+	}
+	log.SetOutput(os.Stdout)
+
+	if level, err := log.ParseLevel(cfg.Level); err == nil {
+		log.SetLevel(level)
+	}
+}
+
+// LoadConfig loads the file given by --config (or CONFIG_FILE) with environment overrides,
+// --print-config prints the result with secrets redacted and exits
+func LoadConfig() (*config.Config, error) {
+	path := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a .yaml, .toml, .json or .env configuration file")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	flag.Parse()
+
+	cfg, err := config.Load(*path)
+	if err != nil {
+		return nil, err
+	}
+
+	if *printConfig {
+		out, err := config.Redacted(cfg)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Print(string(out))
+		os.Exit(0)
+	}
+
+	return cfg, nil
+}
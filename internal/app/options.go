@@ -0,0 +1,246 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
+	"github.com/Adhiana46/go-restapi-template/pkg/notify"
+	"github.com/Adhiana46/go-restapi-template/pkg/rabbitmq"
+	"github.com/Adhiana46/go-restapi-template/pkg/reporter"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	"github.com/Adhiana46/go-restapi-template/pkg/tracing"
+	"github.com/Adhiana46/go-restapi-template/pkg/webhook"
+	_ "github.com/jackc/pgx/stdlib"
+	log "github.com/sirupsen/logrus"
+)
+
+// WithTracing exports the spans of the component, e.g. api, add it first so every
+// other component is traced and its spans are flushed last on shutdown
+func WithTracing(component string) Option {
+	return func(a *App) error {
+		shutdown, err := tracing.Setup(context.Background(), a.Config.Tracing, component)
+		if err != nil {
+			return err
+		}
+
+		a.Append(Hook{
+			Name:   "tracing",
+			OnStop: shutdown,
+		})
+
+		return nil
+	}
+}
+
+// WithReporter sends the error reports to the configured sink, queued reports are flushed on shutdown
+func WithReporter() Option {
+	return func(a *App) error {
+		cfg := a.Config.Reporter
+
+		var sink reporter.Sink
+		switch cfg.Sink {
+		case "slack":
+			sink = reporter.NewSlackSink(cfg.Url, cfg.Timeout)
+		case "http":
+			sink = reporter.NewHttpSink(cfg.Url, cfg.Timeout)
+		default:
+			return nil
+		}
+
+		a.Reporter = reporter.New(sink, reporter.Options{
+			Timeout:      cfg.Timeout,
+			DedupeWindow: cfg.DedupeWindow,
+			RateLimit:    cfg.RateLimit,
+		})
+		a.Append(Hook{
+			Name: "reporter",
+			OnStop: func(ctx context.Context) error {
+				return a.Reporter.Close(ctx)
+			},
+		})
+
+		return nil
+	}
+}
+
+// WithDatabase opens the Postgres primary and optional replica and builds the repositories on top of them
+func WithDatabase() Option {
+	return func(a *App) error {
+		log.Infoln("Connecting to database...")
+		db, err := sqldb.Open(a.Config.Database)
+		if err != nil {
+			return err
+		}
+
+		a.DB = db
+		a.Append(Hook{
+			Name: "database",
+			OnStop: func(ctx context.Context) error {
+				return a.DB.Close()
+			},
+		})
+
+		a.Readiness.Add("database", db.Primary.PingContext)
+		metrics.RegisterDB(a.Metrics, "primary", db.Primary.DB)
+		if db.Replica != nil {
+			a.Readiness.Add("database_replica", db.Replica.PingContext)
+			metrics.RegisterDB(a.Metrics, "replica", db.Replica.DB)
+		}
+		// the schema is ready once the scripts in database/ ran up to the version this binary was built for
+		a.Readiness.Add("schema", func(ctx context.Context) error {
+			if err := db.CheckTables(ctx, repository.Tables...); err != nil {
+				return err
+			}
+			return db.CheckVersion(ctx, repository.SchemaVersionTable, repository.SchemaVersion)
+		})
+
+		return WithRepositories(Repositories{
+			ActivityGroup:   repository.NewPostgresActivityGroupRepository(db),
+			TodoItem:        repository.NewPostgresTodoItemRepository(db),
+			TodoSeries:      repository.NewPostgresTodoSeriesRepository(db),
+			Webhook:         repository.NewPostgresWebhookRepository(db),
+			ActivityEvent:   repository.NewPostgresActivityEventRepository(db.Primary),
+			ScheduledAction: repository.NewPostgresScheduledActionRepository(db),
+			TodoReminder:    repository.NewPostgresTodoReminderRepository(db),
+			Notification:    repository.NewPostgresNotificationRepository(db),
+		})(a)
+	}
+}
+
+// WithRepositories uses the given repositories, tests use it to swap in fakes
+func WithRepositories(repos Repositories) Option {
+	return func(a *App) error {
+		a.Repositories = repos
+		return nil
+	}
+}
+
+// WithServices builds the services and subscribes the event listeners, repositories must be set first
+func WithServices() Option {
+	return func(a *App) error {
+		repos := a.Repositories
+		if repos.ActivityGroup == nil || repos.TodoItem == nil || repos.TodoSeries == nil || repos.Webhook == nil || repos.ActivityEvent == nil || repos.ScheduledAction == nil || repos.TodoReminder == nil || repos.Notification == nil {
+			return errors.New("services need the repositories, add WithDatabase or WithRepositories first")
+		}
+
+		timeouts := service.Timeouts(a.Config.Database.Timeouts)
+
+		a.Services = Services{
+			ActivityGroup:   service.WithActivityGroupTracing(service.NewActivityGroupService(a.Validate, timeouts, repos.ActivityGroup, a.Dispatcher)),
+			TodoItem:        service.WithTodoItemTracing(service.NewTodoItemService(a.Validate, timeouts, repos.TodoItem, repos.TodoSeries, repos.TodoReminder, repos.ActivityGroup, a.Dispatcher)),
+			Webhook:         service.WithWebhookTracing(service.NewWebhookService(a.Validate, timeouts, repos.Webhook, repos.ActivityGroup, webhook.NewSender(a.Config.Webhook.Timeout))),
+			ActivityEvent:   service.WithActivityEventTracing(service.NewActivityEventService(timeouts, repos.ActivityEvent)),
+			ScheduledAction: service.WithScheduledActionTracing(service.NewScheduledActionService(a.Validate, timeouts, repos.ScheduledAction)),
+			Notification:    service.WithNotificationTracing(service.NewNotificationService(a.Validate, timeouts, repos.Notification, repos.TodoReminder, repos.TodoItem, repos.ActivityGroup, notificationChannels(a))),
+		}
+
+		// event listeners
+		a.Dispatcher.Subscribe(a.Services.Webhook)
+		a.Dispatcher.Subscribe(a.Services.ActivityEvent)
+
+		a.Metrics.MustRegister(metrics.NewDomainCollector(a.Services.TodoItem.CountPerActivity))
+
+		return nil
+	}
+}
+
+// notificationChannels lists the configured channels, email needs an SMTP host and the queue channel
+// needs WithRabbitMQ first
+func notificationChannels(a *App) []notify.Channel {
+	channels := []notify.Channel{
+		notify.NewWebhookChannel(webhook.NewSender(a.Config.Webhook.Timeout)),
+	}
+
+	if smtp := a.Config.Notification.Smtp; smtp.Host != "" {
+		channels = append(channels, notify.NewSmtpChannel(notify.SmtpOptions{
+			Host:     smtp.Host,
+			Port:     smtp.Port,
+			Username: smtp.Username,
+			Password: smtp.Password,
+			From:     smtp.From,
+			Timeout:  smtp.Timeout,
+		}))
+	}
+	if a.Publisher != nil {
+		channels = append(channels, notify.NewQueueChannel(a.Publisher, a.Config.Amqp.Queues.Notification))
+	}
+
+	return channels
+}
+
+// WithEventHub polls the event log for live subscribers while the app is running
+func WithEventHub() Option {
+	return func(a *App) error {
+		if a.Services.ActivityEvent == nil {
+			return errors.New("the event hub needs the services, add WithServices first")
+		}
+
+		a.EventHub = stream.NewHub(a.Config.Stream.PollInterval, a.Config.Stream.GapTimeout, a.Services.ActivityEvent)
+
+		var cancel context.CancelFunc
+		a.Append(Hook{
+			Name: "live event stream",
+			OnStart: func(ctx context.Context) error {
+				var hubCtx context.Context
+				hubCtx, cancel = context.WithCancel(context.Background())
+				go func() {
+					if err := a.EventHub.Run(hubCtx); err != nil && hubCtx.Err() == nil {
+						log.Errorf("Live event stream stopped: %s", err)
+					}
+				}()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+
+		return nil
+	}
+}
+
+// WithRabbitMQ opens the AMQP connection and the publisher sharing its channels
+func WithRabbitMQ() Option {
+	return func(a *App) error {
+		log.Infoln("Connecting to RabbitMQ...")
+		conn, err := rabbitmq.OpenConn(a.Config.Amqp)
+		if err != nil {
+			return err
+		}
+
+		a.RabbitConn = conn
+		a.Append(Hook{
+			Name: "rabbitmq",
+			OnStop: func(ctx context.Context) error {
+				return a.RabbitConn.Close()
+			},
+		})
+
+		cfg := a.Config.Amqp.Publisher
+		a.Publisher = rabbitmq.NewPublisher(conn, rabbitmq.PublisherOptions{
+			Channels:       cfg.Channels,
+			ConfirmTimeout: cfg.ConfirmTimeout,
+			Retries:        cfg.Retries,
+		})
+		a.Append(Hook{
+			Name: "rabbitmq publisher",
+			OnStop: func(ctx context.Context) error {
+				return a.Publisher.Close()
+			},
+		})
+
+		a.Readiness.Add("rabbitmq", func(ctx context.Context) error {
+			if a.RabbitConn.IsClosed() {
+				return errors.New("connection closed")
+			}
+			return nil
+		})
+
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindBadRequest
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+)
+
+// Code is a stable machine readable identifier, clients may switch on it
+type Code string
+
+const (
+	CodeInternal               Code = "INTERNAL_ERROR"
+	CodeBadRequest             Code = "BAD_REQUEST"
+	CodeValidationFailed       Code = "VALIDATION_FAILED"
+	CodeInvalidSort            Code = "INVALID_SORT_PARAMETER"
+	CodeInvalidMessage         Code = "INVALID_MESSAGE"
+	CodeUnsupportedVersion     Code = "UNSUPPORTED_MESSAGE_VERSION"
+	CodeUnsupportedContentType Code = "UNSUPPORTED_CONTENT_TYPE"
+	CodeInvalidLastEventId     Code = "INVALID_LAST_EVENT_ID"
+	CodeStreamReset            Code = "STREAM_RESET"
+	CodeUnauthenticated        Code = "UNAUTHENTICATED"
+	CodeForbidden              Code = "FORBIDDEN"
+	CodeNotFound               Code = "NOT_FOUND"
+	CodeConflict               Code = "CONFLICT"
+
+	CodeActivityGroupNotFound Code = "ACTIVITY_GROUP_NOT_FOUND"
+	CodeActivityGroupConflict Code = "ACTIVITY_GROUP_CONFLICT"
+	CodeTodoItemNotFound      Code = "TODO_ITEM_NOT_FOUND"
+	CodeTodoItemConflict      Code = "TODO_ITEM_CONFLICT"
+	CodeWebhookNotFound       Code = "WEBHOOK_NOT_FOUND"
+	CodeWebhookConflict       Code = "WEBHOOK_CONFLICT"
+
+	CodeScheduledActionNotFound   Code = "SCHEDULED_ACTION_NOT_FOUND"
+	CodeScheduledActionNotPending Code = "SCHEDULED_ACTION_NOT_PENDING"
+
+	CodeTodoSeriesNotFound Code = "TODO_SERIES_NOT_FOUND"
+	CodeTodoSeriesEnded    Code = "TODO_SERIES_ENDED"
+
+	CodeReminderNotFound Code = "REMINDER_NOT_FOUND"
+	CodeReminderConflict Code = "REMINDER_CONFLICT"
+	CodeReminderNoDueAt  Code = "REMINDER_NO_DUE_DATE"
+)
+
+type Error struct {
+	Kind    Kind
+	Code    Code
+	Message string
+
+	err error
+}
+
+func (e *Error) Error() string {
+	// the cause of internal errors is only meant for logs, Message is what clients get to see
+	if e.err != nil && e.Kind == KindInternal {
+		return e.Message + ": " + e.err.Error()
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Wrap keeps the cause reachable through errors.Is and errors.As
+func (e *Error) Wrap(err error) *Error {
+	e.err = err
+	return e
+}
+
+func New(kind Kind, code Code, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func BadRequest(code Code, message string) *Error {
+	return New(KindBadRequest, code, message)
+}
+
+func Validation(err error) *Error {
+	return New(KindValidation, CodeValidationFailed, "validation failed").Wrap(err)
+}
+
+func Unauthorized(code Code, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code Code, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func NotFound(code Code, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code Code, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+// From returns err as a typed error, untyped errors known to the services are mapped and anything else is internal
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	var validationErrs validator.ValidationErrors
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return NotFound(CodeNotFound, "resource not found").Wrap(err)
+	case errors.As(err, &validationErrs):
+		return Validation(err)
+	}
+
+	return New(KindInternal, CodeInternal, "internal error").Wrap(err)
+}
+
+// ValidationErrors returns the field errors carried by a validation error
+func (e *Error) ValidationErrors() validator.ValidationErrors {
+	var validationErrs validator.ValidationErrors
+	if errors.As(e.err, &validationErrs) {
+		return validationErrs
+	}
+
+	return nil
+}
+
+func (k Kind) HttpStatus() int {
+	switch k {
+	case KindBadRequest, KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
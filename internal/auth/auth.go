@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+const UserLocalsKey = "auth_user"
+
+var ErrUnauthenticated = apperror.Unauthorized(apperror.CodeUnauthenticated, "missing or invalid access token")
+
+type Authenticator interface {
+	Authenticate(token string) (string, error)
+}
+
+type tokenAuthenticator struct {
+	users map[string]string
+}
+
+// NewTokenAuthenticator accepts a user -> token map, as configured in AUTH_TOKENS
+func NewTokenAuthenticator(userTokens map[string]string) Authenticator {
+	return &tokenAuthenticator{
+		users: userTokens,
+	}
+}
+
+func (a *tokenAuthenticator) Authenticate(token string) (string, error) {
+	if token == "" {
+		return "", ErrUnauthenticated
+	}
+
+	for user, userToken := range a.users {
+		if subtle.ConstantTimeCompare([]byte(userToken), []byte(token)) == 1 {
+			return user, nil
+		}
+	}
+
+	return "", ErrUnauthenticated
+}
+
+// FiberToken reads a bearer token from the Authorization header, falling back to the token query
+// parameter for clients such as browser WebSockets that can't set headers
+func FiberToken(c *fiber.Ctx) string {
+	header := c.Get(fiber.HeaderAuthorization)
+	if strings.HasPrefix(strings.ToLower(header), "bearer ") {
+		return strings.TrimSpace(header[len("bearer "):])
+	}
+
+	return c.Query("token")
+}
+
+func FiberMiddleware(authenticator Authenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, err := authenticator.Authenticate(FiberToken(c))
+		if err != nil {
+			return err
+		}
+
+		c.Locals(UserLocalsKey, user)
+		c.SetUserContext(logging.WithFields(c.UserContext(), log.Fields{"user": user}))
+
+		return c.Next()
+	}
+}
+
+func FiberUser(c *fiber.Ctx) string {
+	user, _ := c.Locals(UserLocalsKey).(string)
+
+	return user
+}
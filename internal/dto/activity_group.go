@@ -38,11 +38,15 @@ type ActivityGroupUuidRequest struct {
 	Uuid string `uri:"uuid" validate:"required"`
 }
 
+type ActivityGroupIdsRequest struct {
+	Ids []int `validate:"required,dive,min=1"`
+}
+
 type ActivityGroupFetchRequest struct {
-	Page   int    `query="page" validate:"numeric,min=1"`
-	Limit  int    `query="limit" validate:"numeric,min=1,max=200"`
-	SortBy string `query="sortBy" validate:""`
-	Filter string `query="filter" validate:""`
+	Page   int    `query:"page" validate:"numeric,min=1"`
+	Limit  int    `query:"limit" validate:"numeric,min=1,max=200"`
+	SortBy string `query:"sortBy" validate:""`
+	Filter string `query:"filter" validate:""`
 }
 
 type ActivityGroupCreateRequest struct {
@@ -0,0 +1,165 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+)
+
+func TodoReminderToResponse(e *entity.TodoReminder) *TodoReminderResponse {
+	resp := &TodoReminderResponse{
+		Uuid:          e.Uuid,
+		OffsetMinutes: e.OffsetMinutes,
+		FiredDueAt:    e.FiredDueAt,
+		FiredAt:       e.FiredAt,
+		CreatedAt:     e.CreatedAt,
+		UpdatedAt:     e.UpdatedAt,
+	}
+
+	if e.TodoItem != nil {
+		resp.TodoItemUuid = e.TodoItem.Uuid
+		if e.TodoItem.DueAt != nil {
+			remindAt := e.TodoItem.DueAt.Add(-time.Duration(e.OffsetMinutes) * time.Minute)
+			resp.RemindAt = &remindAt
+		}
+	}
+
+	return resp
+}
+
+func TodoReminderToResponseList(ents []*entity.TodoReminder) []*TodoReminderResponse {
+	respList := []*TodoReminderResponse{}
+
+	for _, e := range ents {
+		respList = append(respList, TodoReminderToResponse(e))
+	}
+
+	return respList
+}
+
+func NotificationPreferenceToResponse(e *entity.NotificationPreference) *NotificationPreferenceResponse {
+	return &NotificationPreferenceResponse{
+		EmailEnabled:     e.EmailEnabled,
+		Email:            e.Email,
+		WebhookEnabled:   e.WebhookEnabled,
+		WebhookUrl:       e.WebhookUrl,
+		HasWebhookSecret: e.WebhookSecret != "",
+		QueueEnabled:     e.QueueEnabled,
+	}
+}
+
+func NotificationDeliveryToResponse(e *entity.NotificationDelivery) *NotificationDeliveryResponse {
+	return &NotificationDeliveryResponse{
+		Uuid:          e.Uuid,
+		Channel:       e.Channel,
+		Recipient:     e.Recipient,
+		Event:         e.Event,
+		Status:        e.Status,
+		Attempts:      e.Attempts,
+		LastError:     e.LastError,
+		NextAttemptAt: e.NextAttemptAt,
+		DeliveredAt:   e.DeliveredAt,
+		CreatedAt:     e.CreatedAt,
+		UpdatedAt:     e.UpdatedAt,
+	}
+}
+
+func NotificationDeliveryToResponseList(ents []*entity.NotificationDelivery) []*NotificationDeliveryResponse {
+	respList := []*NotificationDeliveryResponse{}
+
+	for _, e := range ents {
+		respList = append(respList, NotificationDeliveryToResponse(e))
+	}
+
+	return respList
+}
+
+type TodoReminderResponse struct {
+	Uuid          string `json:"uuid"`
+	TodoItemUuid  string `json:"todo_item_uuid"`
+	OffsetMinutes int    `json:"offset_minutes"`
+	// RemindAt is when the reminder fires for the current due date of the todo item
+	RemindAt   *time.Time `json:"remind_at"`
+	FiredDueAt *time.Time `json:"fired_due_at"`
+	FiredAt    *time.Time `json:"fired_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type NotificationPreferenceResponse struct {
+	EmailEnabled     bool   `json:"email_enabled"`
+	Email            string `json:"email"`
+	WebhookEnabled   bool   `json:"webhook_enabled"`
+	WebhookUrl       string `json:"webhook_url"`
+	HasWebhookSecret bool   `json:"has_webhook_secret"`
+	QueueEnabled     bool   `json:"queue_enabled"`
+}
+
+type NotificationDeliveryResponse struct {
+	Uuid          string     `json:"uuid"`
+	Channel       string     `json:"channel"`
+	Recipient     string     `json:"recipient"`
+	Event         string     `json:"event"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ReminderNotification is the body of a reminder on the webhook and queue channels
+type ReminderNotification struct {
+	Event         string            `json:"event"`
+	ReminderUuid  string            `json:"reminder_uuid"`
+	User          string            `json:"user"`
+	ActivityUuid  string            `json:"activity_uuid"`
+	OffsetMinutes int               `json:"offset_minutes"`
+	DueAt         time.Time         `json:"due_at"`
+	TodoItem      *TodoItemResponse `json:"todo_item"`
+	FiredAt       time.Time         `json:"fired_at"`
+}
+
+type TodoReminderFetchRequest struct {
+	ActivityUuid string `uri:"activity_uuid" validate:"required"`
+	TodoItemUuid string `uri:"todo_item_uuid" validate:"required"`
+	User         string `json:"-" validate:"required"`
+}
+
+type TodoReminderUuidRequest struct {
+	Uuid         string `uri:"uuid" validate:"required"`
+	ActivityUuid string `uri:"activity_uuid" validate:"required"`
+	TodoItemUuid string `uri:"todo_item_uuid" validate:"required"`
+	User         string `json:"-" validate:"required"`
+}
+
+type TodoReminderCreateRequest struct {
+	ActivityUuid string `json:"activity_uuid" uri:"activity_uuid" validate:"required"`
+	TodoItemUuid string `json:"todo_item_uuid" uri:"todo_item_uuid" validate:"required"`
+	User         string `json:"-" validate:"required"`
+	// OffsetMinutes before the due date the reminder fires, up to a year
+	OffsetMinutes int `json:"offset_minutes" validate:"min=0,max=525600"`
+}
+
+type NotificationPreferenceFindRequest struct {
+	User string `json:"-" validate:"required"`
+}
+
+type NotificationPreferenceUpdateRequest struct {
+	User           string `json:"-" validate:"required"`
+	EmailEnabled   bool   `json:"email_enabled"`
+	Email          string `json:"email" validate:"required_if=EmailEnabled true,omitempty,email,max=255"`
+	WebhookEnabled bool   `json:"webhook_enabled"`
+	WebhookUrl     string `json:"webhook_url" validate:"required_if=WebhookEnabled true,omitempty,url,public_url,max=2048"`
+	// WebhookSecret keeps the saved secret when empty
+	WebhookSecret string `json:"webhook_secret" validate:"omitempty,min=16,max=255"`
+	QueueEnabled  bool   `json:"queue_enabled"`
+}
+
+type NotificationDeliveryFetchRequest struct {
+	User   string `json:"-" validate:"required"`
+	Status string `query:"status" validate:"omitempty,oneof=pending success failed"`
+	Page   int    `query:"page" validate:"numeric,min=1"`
+	Limit  int    `query:"limit" validate:"numeric,min=1,max=200"`
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+)
+
+func ScheduledActionToResponse(e *entity.ScheduledAction) *ScheduledActionResponse {
+	data := map[string]interface{}{}
+	// Data is always written from a decoded request, an error here means the row was edited by hand
+	_ = json.Unmarshal([]byte(e.Data), &data)
+
+	return &ScheduledActionResponse{
+		Uuid:         e.Uuid,
+		Queue:        e.Queue,
+		Action:       e.Action,
+		Version:      e.Version,
+		Data:         data,
+		ActivityUuid: e.ActivityUuid,
+		Status:       e.Status,
+		ExecuteAt:    e.ExecuteAt,
+		DispatchedAt: e.DispatchedAt,
+		CancelledAt:  e.CancelledAt,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+func ScheduledActionToResponseList(ents []*entity.ScheduledAction) []*ScheduledActionResponse {
+	respList := []*ScheduledActionResponse{}
+
+	for _, e := range ents {
+		respList = append(respList, ScheduledActionToResponse(e))
+	}
+
+	return respList
+}
+
+type ScheduledActionResponse struct {
+	Uuid         string                 `json:"uuid"`
+	Queue        string                 `json:"queue"`
+	Action       string                 `json:"action"`
+	Version      int                    `json:"version"`
+	Data         map[string]interface{} `json:"data"`
+	ActivityUuid *string                `json:"activity_uuid"`
+	Status       string                 `json:"status"`
+	ExecuteAt    time.Time              `json:"execute_at"`
+	DispatchedAt *time.Time             `json:"dispatched_at"`
+	CancelledAt  *time.Time             `json:"cancelled_at"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+type ScheduledActionUuidRequest struct {
+	Uuid string `uri:"uuid" validate:"required"`
+}
+
+type ScheduledActionFetchRequest struct {
+	Page         int    `query:"page" validate:"numeric,min=1"`
+	Limit        int    `query:"limit" validate:"numeric,min=1,max=200"`
+	Queue        string `query:"queue" validate:"omitempty,max=100"`
+	Status       string `query:"status" validate:"omitempty,oneof=pending dispatched cancelled"`
+	ActivityUuid string `query:"activity_uuid" validate:"omitempty,max=36"`
+}
+
+// ScheduledActionCreateRequest holds a queue request received with an execute_at in the future
+type ScheduledActionCreateRequest struct {
+	Queue        string                 `validate:"required,max=100"`
+	Action       string                 `validate:"required,max=50"`
+	Version      int                    `validate:"min=1"`
+	Data         map[string]interface{} `validate:"required"`
+	Locale       string                 `validate:"max=20"`
+	ActivityUuid string                 `validate:"omitempty,max=36"`
+	ExecuteAt    time.Time              `validate:"required"`
+}
@@ -12,6 +12,10 @@ func TodoItemToResponse(e *entity.TodoItem) *TodoItemResponse {
 		ActivityID:  e.ActivityID,
 		Name:        e.Name,
 		Description: e.Description,
+		DueAt:       e.DueAt,
+		IsRecurring: e.SeriesID != nil,
+		IsCompleted: e.CompletedAt != nil,
+		CompletedAt: e.CompletedAt,
 		CreatedAt:   e.CreatedAt,
 		UpdatedAt:   e.UpdatedAt,
 	}
@@ -38,6 +42,10 @@ type TodoItemResponse struct {
 	ActivityID  int                    `json:"activity_id"`
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
+	DueAt       *time.Time             `json:"due_at"`
+	IsRecurring bool                   `json:"is_recurring"`
+	IsCompleted bool                   `json:"is_completed"`
+	CompletedAt *time.Time             `json:"completed_at"`
 	CreatedAt   time.Time              `json:"created_at"`
 	UpdatedAt   time.Time              `json:"updated_at"`
 	Activity    *ActivityGroupResponse `json:"activity,omitempty"`
@@ -48,17 +56,24 @@ type TodoItemUuidRequest struct {
 }
 
 type TodoItemFetchRequest struct {
-	Page         int    `query="page" validate:"numeric,min=1"`
-	Limit        int    `query="limit" validate:"numeric,min=1,max=200"`
-	SortBy       string `query="sortBy" validate:""`
+	Page         int    `query:"page" validate:"numeric,min=1"`
+	Limit        int    `query:"limit" validate:"numeric,min=1,max=200"`
+	SortBy       string `query:"sortBy" validate:""`
 	ActivityUuid string `uri:"activity_uuid" query:"activity_uuid"`
-	Filter       string `query="filter" validate:""`
+	Filter       string `query:"filter" validate:""`
+}
+
+type TodoItemCountRequest struct {
+	ActivityIds []int `validate:"required,dive,min=1"`
 }
 
 type TodoItemCreateRequest struct {
-	ActivityUuid string `json:"activity_uuid" uri:"activity_uuid" validate:"required"`
-	Name         string `json:"name" validate:"required,min=3,max=100"`
-	Description  string `json:"description" validate:""`
+	ActivityUuid string     `json:"activity_uuid" uri:"activity_uuid" validate:"required"`
+	Name         string     `json:"name" validate:"required,min=3,max=100"`
+	Description  string     `json:"description" validate:""`
+	DueAt        *time.Time `json:"due_at" validate:"required_with=Recurrence"`
+	// Recurrence is an RRULE such as FREQ=WEEKLY;BYDAY=MO, the item is then the first occurrence of a series due from DueAt
+	Recurrence string `json:"recurrence" validate:"omitempty,max=255,rrule"`
 }
 
 type TodoItemUpdateRequest struct {
@@ -66,4 +81,55 @@ type TodoItemUpdateRequest struct {
 	ActivityUuid string `json:"activity_uuid" uri:"activity_uuid" validate:"required"`
 	Name         string `json:"name" validate:"required,min=3,max=100"`
 	Description  string `json:"description" validate:""`
+	// DueAt moves the due date, it is kept when omitted and removed with ClearDueAt
+	DueAt      *time.Time `json:"due_at"`
+	ClearDueAt bool       `json:"clear_due_at" validate:"excluded_with=DueAt"`
+	// IsCompleted completes or reopens the item, it keeps its completion when omitted
+	IsCompleted *bool `json:"is_completed"`
+}
+
+// TodoItemUpdateRequestV1 is version 1 of the queue update action, sent by producers without a version
+type TodoItemUpdateRequestV1 struct {
+	Uuid         string `json:"uuid"`
+	ActivityUuid string `json:"activity_uuid"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+}
+
+func TodoSeriesToResponse(e *entity.TodoSeries) *TodoSeriesResponse {
+	return &TodoSeriesResponse{
+		Uuid:         e.Uuid,
+		ActivityID:   e.ActivityID,
+		Name:         e.Name,
+		Description:  e.Description,
+		Recurrence:   e.Rrule,
+		StartAt:      e.StartAt,
+		Occurrences:  e.Occurrences,
+		CurrentDueAt: e.CurrentDueAt,
+		EndedAt:      e.EndedAt,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+type TodoSeriesResponse struct {
+	Uuid         string     `json:"uuid"`
+	ActivityID   int        `json:"activity_id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Recurrence   string     `json:"recurrence"`
+	StartAt      time.Time  `json:"start_at"`
+	Occurrences  int        `json:"occurrences"`
+	CurrentDueAt time.Time  `json:"current_due_at"`
+	EndedAt      *time.Time `json:"ended_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TodoSeriesUpdateRequest edits the series of the todo item Uuid, its open occurrence and the next ones
+type TodoSeriesUpdateRequest struct {
+	Uuid        string `uri:"uuid" validate:"required"`
+	Name        string `json:"name" validate:"required,min=3,max=100"`
+	Description string `json:"description" validate:""`
+	Recurrence  string `json:"recurrence" validate:"required,max=255,rrule"`
 }
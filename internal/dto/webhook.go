@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+)
+
+func WebhookToResponse(e *entity.WebhookEndpoint) *WebhookResponse {
+	return &WebhookResponse{
+		Uuid:         e.Uuid,
+		ActivityID:   e.ActivityID,
+		Url:          e.Url,
+		Events:       strings.Split(e.Events, ","),
+		IsActive:     e.IsActive,
+		FailureCount: e.FailureCount,
+		DisabledAt:   e.DisabledAt,
+		CreatedAt:    e.CreatedAt,
+		UpdatedAt:    e.UpdatedAt,
+	}
+}
+
+func WebhookToResponseList(ents []*entity.WebhookEndpoint) []*WebhookResponse {
+	respList := []*WebhookResponse{}
+
+	for _, e := range ents {
+		respList = append(respList, WebhookToResponse(e))
+	}
+
+	return respList
+}
+
+func WebhookDeliveryToResponse(e *entity.WebhookDelivery) *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		Uuid:           e.Uuid,
+		Event:          e.Event,
+		Status:         e.Status,
+		Attempts:       e.Attempts,
+		LastStatusCode: e.LastStatusCode,
+		LastError:      e.LastError,
+		NextAttemptAt:  e.NextAttemptAt,
+		DeliveredAt:    e.DeliveredAt,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
+
+func WebhookDeliveryToResponseList(ents []*entity.WebhookDelivery) []*WebhookDeliveryResponse {
+	respList := []*WebhookDeliveryResponse{}
+
+	for _, e := range ents {
+		respList = append(respList, WebhookDeliveryToResponse(e))
+	}
+
+	return respList
+}
+
+type WebhookResponse struct {
+	Uuid         string     `json:"uuid"`
+	ActivityID   int        `json:"activity_id"`
+	Url          string     `json:"url"`
+	Events       []string   `json:"events"`
+	IsActive     bool       `json:"is_active"`
+	FailureCount int        `json:"failure_count"`
+	DisabledAt   *time.Time `json:"disabled_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	Uuid           string     `json:"uuid"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastStatusCode *int       `json:"last_status_code"`
+	LastError      *string    `json:"last_error"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type WebhookUuidRequest struct {
+	Uuid         string `uri:"uuid" validate:"required"`
+	ActivityUuid string `uri:"activity_uuid" validate:"required"`
+}
+
+type WebhookFetchRequest struct {
+	ActivityUuid string `uri:"activity_uuid" validate:"required"`
+}
+
+type WebhookCreateRequest struct {
+	ActivityUuid string   `json:"activity_uuid" uri:"activity_uuid" validate:"required"`
+	Url          string   `json:"url" validate:"required,url,public_url,max=2048"`
+	Secret       string   `json:"secret" validate:"required,min=16,max=255"`
+	Events       []string `json:"events" validate:"dive,webhook_event"`
+}
+
+type WebhookUpdateRequest struct {
+	Uuid         string   `uri:"uuid" validate:"required"`
+	ActivityUuid string   `json:"activity_uuid" uri:"activity_uuid" validate:"required"`
+	Url          string   `json:"url" validate:"required,url,public_url,max=2048"`
+	Secret       string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	Events       []string `json:"events" validate:"dive,webhook_event"`
+	// IsActive keeps the endpoint as it is when omitted
+	IsActive *bool `json:"is_active"`
+}
+
+type WebhookDeliveryFetchRequest struct {
+	Uuid         string `uri:"uuid" validate:"required"`
+	ActivityUuid string `uri:"activity_uuid" validate:"required"`
+	Page         int    `query:"page" validate:"numeric,min=1"`
+	Limit        int    `query:"limit" validate:"numeric,min=1,max=200"`
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+type ActivityEvent struct {
+	ID           int64     `db:"id" json:"id"`
+	ActivityUuid string    `db:"activity_uuid" json:"activity_uuid"`
+	Event        string    `db:"event" json:"event"`
+	Payload      string    `db:"payload" json:"payload"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+const (
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+	NotificationChannelQueue   = "queue"
+)
+
+const (
+	NotificationDeliveryPending = "pending"
+	NotificationDeliverySuccess = "success"
+	NotificationDeliveryFailed  = "failed"
+)
+
+// TodoReminder notifies User OffsetMinutes before the due date of a todo item, once per due date
+type TodoReminder struct {
+	ID            int    `db:"id" json:"id"`
+	Uuid          string `db:"uuid" json:"uuid"`
+	TodoItemID    int    `db:"todo_item_id" json:"todo_item_id"`
+	User          string `db:"username" json:"user"`
+	OffsetMinutes int    `db:"offset_minutes" json:"offset_minutes"`
+	// FiredDueAt is the due date the reminder last fired for, moving the due date arms it again
+	FiredDueAt *time.Time `db:"fired_due_at" json:"fired_due_at"`
+	FiredAt    *time.Time `db:"fired_at" json:"fired_at"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+	TodoItem   *TodoItem  `db:"-" json:"todo_item,omitempty"`
+}
+
+// NotificationPreference picks the channels the notifications of User are delivered on
+type NotificationPreference struct {
+	ID             int       `db:"id" json:"id"`
+	User           string    `db:"username" json:"user"`
+	EmailEnabled   bool      `db:"email_enabled" json:"email_enabled"`
+	Email          string    `db:"email" json:"email"`
+	WebhookEnabled bool      `db:"webhook_enabled" json:"webhook_enabled"`
+	WebhookUrl     string    `db:"webhook_url" json:"webhook_url"`
+	WebhookSecret  string    `db:"webhook_secret" json:"-"`
+	QueueEnabled   bool      `db:"queue_enabled" json:"queue_enabled"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type NotificationDelivery struct {
+	ID         int    `db:"id" json:"id"`
+	Uuid       string `db:"uuid" json:"uuid"`
+	ReminderID *int   `db:"reminder_id" json:"reminder_id"`
+	User       string `db:"username" json:"user"`
+	Channel    string `db:"channel" json:"channel"`
+	// Recipient is the email address or webhook url the notification is sent to, empty on the queue channel
+	Recipient     string     `db:"recipient" json:"recipient"`
+	Event         string     `db:"event" json:"event"`
+	Payload       string     `db:"payload" json:"payload"`
+	Status        string     `db:"status" json:"status"`
+	Attempts      int        `db:"attempts" json:"attempts"`
+	LastError     *string    `db:"last_error" json:"last_error"`
+	NextAttemptAt time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt   *time.Time `db:"delivered_at" json:"delivered_at"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	ScheduledActionPending    = "pending"
+	ScheduledActionDispatched = "dispatched"
+	ScheduledActionCancelled  = "cancelled"
+)
+
+// ScheduledAction is a queue request held until ExecuteAt, then published back on the request queue of Queue
+type ScheduledAction struct {
+	ID      int    `db:"id" json:"id"`
+	Uuid    string `db:"uuid" json:"uuid"`
+	Queue   string `db:"queue" json:"queue"`
+	Action  string `db:"action" json:"action"`
+	Version int    `db:"version" json:"version"`
+	// Data is the JSON data field of the request
+	Data         string     `db:"data" json:"data"`
+	Locale       string     `db:"locale" json:"locale"`
+	ActivityUuid *string    `db:"activity_uuid" json:"activity_uuid"`
+	Status       string     `db:"status" json:"status"`
+	ExecuteAt    time.Time  `db:"execute_at" json:"execute_at"`
+	DispatchedAt *time.Time `db:"dispatched_at" json:"dispatched_at"`
+	CancelledAt  *time.Time `db:"cancelled_at" json:"cancelled_at"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
+}
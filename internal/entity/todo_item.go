@@ -8,6 +8,9 @@ type TodoItem struct {
 	ActivityID  int            `db:"activity_id" json:"activity_id"`
 	Name        string         `db:"name" json:"name"`
 	Description string         `db:"description" json:"description"`
+	DueAt       *time.Time     `db:"due_at" json:"due_at"`
+	SeriesID    *int           `db:"series_id" json:"series_id"`
+	CompletedAt *time.Time     `db:"completed_at" json:"completed_at"`
 	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
 	Activity    *ActivityGroup `json:"activity,omitempty"`
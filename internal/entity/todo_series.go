@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// TodoSeries generates the occurrences of a recurring todo item, one open at a time
+type TodoSeries struct {
+	ID          int    `db:"id" json:"id"`
+	Uuid        string `db:"uuid" json:"uuid"`
+	ActivityID  int    `db:"activity_id" json:"activity_id"`
+	Name        string `db:"name" json:"name"`
+	Description string `db:"description" json:"description"`
+	// Rrule is the recurrence rule, e.g. FREQ=WEEKLY;BYDAY=MO
+	Rrule   string    `db:"rrule" json:"rrule"`
+	StartAt time.Time `db:"start_at" json:"start_at"`
+	// Occurrences counts the occurrences generated since StartAt, for the COUNT of the rule
+	Occurrences int `db:"occurrences" json:"occurrences"`
+	// CurrentDueAt is the due date the rule gave the current occurrence, edits of the occurrence don't move it
+	CurrentDueAt  time.Time  `db:"current_due_at" json:"current_due_at"`
+	CurrentItemID *int       `db:"current_item_id" json:"current_item_id"`
+	EndedAt       *time.Time `db:"ended_at" json:"ended_at"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+}
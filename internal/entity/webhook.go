@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+const (
+	WebhookDeliveryPending = "pending"
+	WebhookDeliverySuccess = "success"
+	WebhookDeliveryFailed  = "failed"
+)
+
+type WebhookEndpoint struct {
+	ID           int        `db:"id" json:"id"`
+	Uuid         string     `db:"uuid" json:"uuid"`
+	ActivityID   int        `db:"activity_id" json:"activity_id"`
+	Url          string     `db:"url" json:"url"`
+	Secret       string     `db:"secret" json:"-"`
+	Events       string     `db:"events" json:"events"`
+	IsActive     bool       `db:"is_active" json:"is_active"`
+	FailureCount int        `db:"failure_count" json:"failure_count"`
+	DisabledAt   *time.Time `db:"disabled_at" json:"disabled_at"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int        `db:"id" json:"id"`
+	Uuid           string     `db:"uuid" json:"uuid"`
+	EndpointID     *int       `db:"endpoint_id" json:"endpoint_id"`
+	Url            string     `db:"url" json:"url"`
+	Secret         string     `db:"secret" json:"-"`
+	Event          string     `db:"event" json:"event"`
+	Payload        string     `db:"payload" json:"payload"`
+	Status         string     `db:"status" json:"status"`
+	Attempts       int        `db:"attempts" json:"attempts"`
+	LastStatusCode *int       `db:"last_status_code" json:"last_status_code"`
+	LastError      *string    `db:"last_error" json:"last_error"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt    *time.Time `db:"delivered_at" json:"delivered_at"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+type WebhookDeliveryAttempt struct {
+	ID         int       `db:"id" json:"id"`
+	DeliveryID int       `db:"delivery_id" json:"delivery_id"`
+	Attempt    int       `db:"attempt" json:"attempt"`
+	StatusCode *int      `db:"status_code" json:"status_code"`
+	Error      *string   `db:"error" json:"error"`
+	DurationMs int       `db:"duration_ms" json:"duration_ms"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
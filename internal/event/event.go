@@ -0,0 +1,92 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	ActivityGroupCreated = "activity-group.created"
+	ActivityGroupUpdated = "activity-group.updated"
+	ActivityGroupDeleted = "activity-group.deleted"
+
+	TodoItemCreated   = "todo-item.created"
+	TodoItemUpdated   = "todo-item.updated"
+	TodoItemDeleted   = "todo-item.deleted"
+	TodoItemCompleted = "todo-item.completed"
+)
+
+// Names lists every event emitted by the services, used to validate event filters
+var Names = []string{
+	ActivityGroupCreated,
+	ActivityGroupUpdated,
+	ActivityGroupDeleted,
+	TodoItemCreated,
+	TodoItemUpdated,
+	TodoItemDeleted,
+	TodoItemCompleted,
+}
+
+type Event struct {
+	Name         string    `json:"event"`
+	ActivityUuid string    `json:"activity_uuid"`
+	Data         any       `json:"data"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+func New(name string, activityUuid string, data any) Event {
+	return Event{
+		Name:         name,
+		ActivityUuid: activityUuid,
+		Data:         data,
+		OccurredAt:   time.Now(),
+	}
+}
+
+// Listener handles an event inside the transaction of the change that emitted it, so whatever it stores
+// commits or rolls back together with the change
+type Listener interface {
+	Handle(ctx context.Context, tx *sqlx.Tx, e Event) error
+}
+
+type Dispatcher interface {
+	Subscribe(l Listener)
+	Dispatch(ctx context.Context, tx *sqlx.Tx, e Event) error
+}
+
+type dispatcher struct {
+	mu        sync.RWMutex
+	listeners []Listener
+}
+
+func NewDispatcher() Dispatcher {
+	return &dispatcher{
+		listeners: []Listener{},
+	}
+}
+
+func (d *dispatcher) Subscribe(l Listener) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.listeners = append(d.listeners, l)
+}
+
+// Dispatch notifies every listener in tx, the caller must roll the change back when it fails so an event is
+// never lost after the change commits
+func (d *dispatcher) Dispatch(ctx context.Context, tx *sqlx.Tx, e Event) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, l := range d.listeners {
+		if err := l.Handle(ctx, tx, e); err != nil {
+			return fmt.Errorf("handle %s: %w", e.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type listenerFunc func(ctx context.Context, tx *sqlx.Tx, e Event) error
+
+func (f listenerFunc) Handle(ctx context.Context, tx *sqlx.Tx, e Event) error {
+	return f(ctx, tx, e)
+}
+
+// TestDispatchFailsWithListener makes sure a failing listener fails the dispatch, so the change that emitted the
+// event rolls back instead of committing without it
+func TestDispatchFailsWithListener(t *testing.T) {
+	d := NewDispatcher()
+
+	handled := []string{}
+	d.Subscribe(listenerFunc(func(ctx context.Context, tx *sqlx.Tx, e Event) error {
+		handled = append(handled, "first")
+		return errors.New("boom")
+	}))
+	d.Subscribe(listenerFunc(func(ctx context.Context, tx *sqlx.Tx, e Event) error {
+		handled = append(handled, "second")
+		return nil
+	}))
+
+	err := d.Dispatch(context.Background(), nil, New(ActivityGroupCreated, "activity", nil))
+	if err == nil {
+		t.Fatal("dispatch succeeded with a failing listener")
+	}
+	if len(handled) != 1 {
+		t.Fatalf("listeners after the failing one ran: %v", handled)
+	}
+}
+
+func TestDispatchPassesTx(t *testing.T) {
+	d := NewDispatcher()
+	tx := &sqlx.Tx{}
+
+	var got *sqlx.Tx
+	d.Subscribe(listenerFunc(func(ctx context.Context, handledTx *sqlx.Tx, e Event) error {
+		got = handledTx
+		return nil
+	}))
+
+	if err := d.Dispatch(context.Background(), tx, New(TodoItemDeleted, "activity", nil)); err != nil {
+		t.Fatalf("dispatching: %s", err)
+	}
+	if got != tx {
+		t.Fatal("listener didn't get the transaction of the change")
+	}
+}
@@ -0,0 +1,45 @@
+package i18n
+
+import (
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+)
+
+var english = language{
+	locale:   func() locales.Translator { return en.New() },
+	register: en_translations.RegisterDefaultTranslations,
+	tags: map[string]string{
+		"public_url":    "{0} must not point to localhost or a private network",
+		"rrule":         "{0} must be a recurrence rule like FREQ=WEEKLY;BYDAY=MO,FR",
+		"webhook_event": "{0} must only contain known event names",
+	},
+	messages: map[apperror.Code]string{
+		apperror.CodeInternal:              "Something went wrong on our side",
+		apperror.CodeValidationFailed:      "The request contains invalid fields",
+		apperror.CodeInvalidSort:           "Malformed sortBy query parameter, should be field.asc or field.desc",
+		apperror.CodeInvalidLastEventId:    "Malformed Last-Event-ID, should be a numeric event id",
+		apperror.CodeStreamReset:           "The stream fell behind, resume from the last event id",
+		apperror.CodeUnauthenticated:       "Missing or invalid access token",
+		apperror.CodeForbidden:             "You are not allowed to access this resource",
+		apperror.CodeNotFound:              "Resource not found",
+		apperror.CodeConflict:              "Resource already exists",
+		apperror.CodeActivityGroupNotFound: "Activity group not found",
+		apperror.CodeActivityGroupConflict: "Activity group already exists",
+		apperror.CodeTodoItemNotFound:      "Todo item not found",
+		apperror.CodeTodoItemConflict:      "Todo item already exists",
+		apperror.CodeWebhookNotFound:       "Webhook not found",
+		apperror.CodeWebhookConflict:       "Webhook already exists",
+
+		apperror.CodeScheduledActionNotFound:   "Scheduled action not found",
+		apperror.CodeScheduledActionNotPending: "Scheduled action was already dispatched or cancelled",
+
+		apperror.CodeTodoSeriesNotFound: "Todo item doesn't recur",
+		apperror.CodeTodoSeriesEnded:    "Recurrence of the todo item already ended",
+
+		apperror.CodeReminderNotFound: "Reminder not found",
+		apperror.CodeReminderConflict: "Reminder with this offset already exists",
+		apperror.CodeReminderNoDueAt:  "Todo item needs a due date to be reminded of",
+	},
+}
@@ -0,0 +1,148 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/go-playground/locales"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// language bundles everything needed to speak a locale, add an entry to languages to support a new one
+type language struct {
+	locale func() locales.Translator
+	// register adds the translations of the builtin validation tags
+	register func(v *validator.Validate, trans ut.Translator) error
+	// tags translates the custom validation tags, {0} is the field name
+	tags map[string]string
+	// messages translates domain errors by code
+	messages map[apperror.Code]string
+}
+
+var languages = []language{english, indonesian}
+
+type Translator interface {
+	// Get returns the translator of locale, falling back to the default locale
+	Get(locale string) ut.Translator
+	// FromAcceptLanguage picks the best supported translator of an Accept-Language header
+	FromAcceptLanguage(header string) ut.Translator
+	// Error translates the message of a domain error, errors without a translation keep their message
+	Error(trans ut.Translator, err *apperror.Error) string
+}
+
+type translator struct {
+	uni *ut.UniversalTranslator
+}
+
+func NewTranslator(validate *validator.Validate, defaultLocale string) (Translator, error) {
+	var fallback locales.Translator
+	supported := []locales.Translator{}
+	for _, lang := range languages {
+		locale := lang.locale()
+		supported = append(supported, locale)
+		if locale.Locale() == defaultLocale {
+			fallback = locale
+		}
+	}
+	if fallback == nil {
+		return nil, fmt.Errorf("unsupported default locale %q", defaultLocale)
+	}
+
+	uni := ut.New(fallback, supported...)
+	for _, lang := range languages {
+		trans, _ := uni.GetTranslator(lang.locale().Locale())
+
+		if err := lang.register(validate, trans); err != nil {
+			return nil, err
+		}
+		for tag, text := range lang.tags {
+			if err := validate.RegisterTranslation(tag, trans, registerTag(tag, text), translateTag(tag)); err != nil {
+				return nil, err
+			}
+		}
+		for code, text := range lang.messages {
+			if err := trans.Add(string(code), text, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &translator{
+		uni: uni,
+	}, nil
+}
+
+func (t *translator) Get(locale string) ut.Translator {
+	trans, _ := t.uni.FindTranslator(candidates(locale)...)
+	return trans
+}
+
+func (t *translator) FromAcceptLanguage(header string) ut.Translator {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	tags := []weighted{}
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		tags = append(tags, weighted{locale: locale, q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	locales := []string{}
+	for _, tag := range tags {
+		locales = append(locales, candidates(tag.locale)...)
+	}
+
+	trans, _ := t.uni.FindTranslator(locales...)
+	return trans
+}
+
+func (t *translator) Error(trans ut.Translator, err *apperror.Error) string {
+	if msg, tErr := trans.T(string(err.Code)); tErr == nil {
+		return msg
+	}
+
+	return err.Message
+}
+
+// candidates turns a language tag such as en-US into the locale names known to the translators, most specific first
+func candidates(tag string) []string {
+	locale := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "-", "_")
+	if base, region, ok := strings.Cut(locale, "_"); ok {
+		return []string{base + "_" + strings.ToUpper(region), base}
+	}
+
+	return []string{locale}
+}
+
+func registerTag(tag string, text string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, text, true)
+	}
+}
+
+func translateTag(tag string) validator.TranslationFunc {
+	return func(trans ut.Translator, fe validator.FieldError) string {
+		msg, err := trans.T(tag, fe.Field())
+		if err != nil {
+			return fe.Error()
+		}
+		return msg
+	}
+}
@@ -0,0 +1,82 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/go-playground/validator/v10"
+)
+
+func newTestTranslator(t *testing.T, defaultLocale string) Translator {
+	t.Helper()
+
+	translator, err := NewTranslator(validator.New(), defaultLocale)
+	if err != nil {
+		t.Fatalf("building translator: %s", err)
+	}
+
+	return translator
+}
+
+func TestNewTranslatorRejectsUnsupportedDefault(t *testing.T) {
+	if _, err := NewTranslator(validator.New(), "fr"); err == nil {
+		t.Fatal("fr accepted as default locale")
+	}
+}
+
+func TestFromAcceptLanguage(t *testing.T) {
+	translator := newTestTranslator(t, "id")
+
+	for header, want := range map[string]string{
+		"":                       "id",
+		"en":                     "en",
+		"en-US":                  "en",
+		"id-ID,en;q=0.5":         "id",
+		"en;q=0.2, id;q=0.9":     "id",
+		"fr, en;q=0.8":           "en",
+		"fr, *;q=0.5":            "id",
+		"en;q=malformed, id;q=0": "en",
+	} {
+		if got := translator.FromAcceptLanguage(header).Locale(); got != want {
+			t.Errorf("Accept-Language %q picked %s, want %s", header, got, want)
+		}
+	}
+}
+
+func TestErrorTranslatesCodes(t *testing.T) {
+	translator := newTestTranslator(t, "en")
+
+	err := apperror.NotFound(apperror.CodeActivityGroupNotFound, "activity group not found")
+	if got := translator.Error(translator.Get("id"), err); got != "Activity group tidak ditemukan" {
+		t.Errorf("id message %q", got)
+	}
+	if got := translator.Error(translator.Get("en"), err); got != "Activity group not found" {
+		t.Errorf("en message %q", got)
+	}
+
+	// codes without a translation keep the message of the error
+	err = apperror.BadRequest(apperror.Code("SOMETHING_NEW"), "something new went wrong")
+	if got := translator.Error(translator.Get("id"), err); got != "something new went wrong" {
+		t.Errorf("untranslated message %q", got)
+	}
+}
+
+func TestValidationMessages(t *testing.T) {
+	validate := validator.New()
+	translator, err := NewTranslator(validate, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type request struct {
+		Name string `validate:"required"`
+	}
+	validationErrs := validate.Struct(request{}).(validator.ValidationErrors)
+
+	if got := validationErrs.Translate(translator.Get("id"))["request.Name"]; got != "Name wajib diisi" {
+		t.Errorf("id validation message %q", got)
+	}
+	if got := validationErrs.Translate(translator.Get("en"))["request.Name"]; got != "Name is a required field" {
+		t.Errorf("en validation message %q", got)
+	}
+}
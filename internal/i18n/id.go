@@ -0,0 +1,45 @@
+package i18n
+
+import (
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/id"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+)
+
+var indonesian = language{
+	locale:   func() locales.Translator { return id.New() },
+	register: id_translations.RegisterDefaultTranslations,
+	tags: map[string]string{
+		"public_url":    "{0} tidak boleh mengarah ke localhost atau jaringan privat",
+		"rrule":         "{0} harus berupa aturan pengulangan seperti FREQ=WEEKLY;BYDAY=MO,FR",
+		"webhook_event": "{0} hanya boleh berisi nama event yang dikenal",
+	},
+	messages: map[apperror.Code]string{
+		apperror.CodeInternal:              "Terjadi kesalahan pada server",
+		apperror.CodeValidationFailed:      "Permintaan berisi field yang tidak valid",
+		apperror.CodeInvalidSort:           "Parameter query sortBy tidak valid, seharusnya field.asc atau field.desc",
+		apperror.CodeInvalidLastEventId:    "Last-Event-ID tidak valid, seharusnya berupa id event numerik",
+		apperror.CodeStreamReset:           "Stream tertinggal, lanjutkan dari id event terakhir",
+		apperror.CodeUnauthenticated:       "Token akses tidak ada atau tidak valid",
+		apperror.CodeForbidden:             "Anda tidak diizinkan mengakses resource ini",
+		apperror.CodeNotFound:              "Resource tidak ditemukan",
+		apperror.CodeConflict:              "Resource sudah ada",
+		apperror.CodeActivityGroupNotFound: "Activity group tidak ditemukan",
+		apperror.CodeActivityGroupConflict: "Activity group sudah ada",
+		apperror.CodeTodoItemNotFound:      "Todo item tidak ditemukan",
+		apperror.CodeTodoItemConflict:      "Todo item sudah ada",
+		apperror.CodeWebhookNotFound:       "Webhook tidak ditemukan",
+		apperror.CodeWebhookConflict:       "Webhook sudah ada",
+
+		apperror.CodeScheduledActionNotFound:   "Aksi terjadwal tidak ditemukan",
+		apperror.CodeScheduledActionNotPending: "Aksi terjadwal sudah dijalankan atau dibatalkan",
+
+		apperror.CodeTodoSeriesNotFound: "Todo item tidak berulang",
+		apperror.CodeTodoSeriesEnded:    "Pengulangan todo item sudah berakhir",
+
+		apperror.CodeReminderNotFound: "Pengingat tidak ditemukan",
+		apperror.CodeReminderConflict: "Pengingat dengan selisih waktu ini sudah ada",
+		apperror.CodeReminderNoDueAt:  "Todo item harus memiliki tenggat waktu untuk diingatkan",
+	},
+}
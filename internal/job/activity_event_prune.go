@@ -0,0 +1,44 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type activityEventPruneJob struct {
+	interval  time.Duration
+	retention time.Duration
+
+	svcActivityEvent service.ActivityEventService
+}
+
+func NewActivityEventPruneJob(interval time.Duration, retention time.Duration, svcActivityEvent service.ActivityEventService) Job {
+	return &activityEventPruneJob{
+		interval:         interval,
+		retention:        retention,
+		svcActivityEvent: svcActivityEvent,
+	}
+}
+
+func (j *activityEventPruneJob) GetJobName() string {
+	return "activity-event-prune"
+}
+
+func (j *activityEventPruneJob) Run(ctx context.Context) error {
+	return every(ctx, j.GetJobName(), j.interval, j.tick)
+}
+
+func (j *activityEventPruneJob) tick(ctx context.Context) {
+	runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	count, err := j.svcActivityEvent.Prune(runCtx, j.retention)
+	if err != nil {
+		log.Errorf("[%s] pruning activity events: %s", j.GetJobName(), err)
+	} else if count > 0 {
+		log.Infof("[%s] pruned %d activity events older than %s", j.GetJobName(), count, j.retention)
+	}
+}
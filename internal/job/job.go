@@ -0,0 +1,58 @@
+package job
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Job is a long running background task started by the queue binary next to the queue workers
+type Job interface {
+	GetJobName() string
+	Run(ctx context.Context) error
+}
+
+// every runs tick on every interval until ctx is cancelled, a tick that panics is logged and the job keeps going
+func every(ctx context.Context, name string, interval time.Duration, tick func(ctx context.Context)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			recoverTick(ctx, name, tick)
+		}
+	}
+}
+
+func recoverTick(ctx context.Context, name string, tick func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("[%s] tick panicked: %v\n%s", name, r, debug.Stack())
+		}
+	}()
+
+	tick(ctx)
+}
+
+// drain runs batch, each run bounded by timeout, until it finds nothing left to do so a backlog clears without
+// waiting for the next tick. done is called after every batch that did something.
+func drain(ctx context.Context, timeout time.Duration, batch func(ctx context.Context) (int, error), done func(count int)) error {
+	for ctx.Err() == nil {
+		runCtx, cancel := context.WithTimeout(ctx, timeout)
+		count, err := batch(runCtx)
+		cancel()
+
+		if err != nil || count == 0 {
+			return err
+		}
+
+		done(count)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestEveryRecoversPanickingTick makes sure a tick that panics, like a failed MustBeginTx, doesn't take the
+// queue binary down and the job keeps ticking
+func TestEveryRecoversPanickingTick(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	ticks := 0
+	err := every(ctx, "test", time.Millisecond, func(ctx context.Context) {
+		ticks++
+		if ticks == 3 {
+			cancel()
+		}
+		panic("database is down")
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("every returned %v, want context.Canceled", err)
+	}
+	if ticks < 3 {
+		t.Fatalf("job stopped after %d ticks", ticks)
+	}
+}
+
+func TestDrainRunsUntilNothingIsLeft(t *testing.T) {
+	backlog := []int{20, 20, 5, 0, 7}
+	runs, reported := 0, 0
+
+	err := drain(context.Background(), time.Second, func(ctx context.Context) (int, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("batch runs without a timeout")
+		}
+		count := backlog[runs]
+		runs++
+		return count, nil
+	}, func(count int) {
+		reported += count
+	})
+
+	if err != nil {
+		t.Fatalf("draining: %s", err)
+	}
+	if runs != 4 || reported != 45 {
+		t.Fatalf("ran %d batches reporting %d, want 4 batches reporting 45", runs, reported)
+	}
+}
+
+func TestDrainStopsOnError(t *testing.T) {
+	runs := 0
+	failure := errors.New("boom")
+
+	err := drain(context.Background(), time.Second, func(ctx context.Context) (int, error) {
+		runs++
+		return 1, failure
+	}, func(count int) {
+		t.Error("failed batch reported as done")
+	})
+
+	if !errors.Is(err, failure) || runs != 1 {
+		t.Fatalf("drain returned %v after %d runs", err, runs)
+	}
+}
@@ -0,0 +1,41 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type notificationDeliveryJob struct {
+	interval time.Duration
+	timeout  time.Duration
+
+	svcNotification service.NotificationService
+}
+
+func NewNotificationDeliveryJob(interval time.Duration, timeout time.Duration, svcNotification service.NotificationService) Job {
+	return &notificationDeliveryJob{
+		interval:        interval,
+		timeout:         timeout,
+		svcNotification: svcNotification,
+	}
+}
+
+func (j *notificationDeliveryJob) GetJobName() string {
+	return "notification-delivery"
+}
+
+func (j *notificationDeliveryJob) Run(ctx context.Context) error {
+	return every(ctx, j.GetJobName(), j.interval, j.tick)
+}
+
+func (j *notificationDeliveryJob) tick(ctx context.Context) {
+	err := drain(ctx, j.timeout, j.svcNotification.DeliverPending, func(count int) {
+		log.Infof("[%s] attempted %d deliveries", j.GetJobName(), count)
+	})
+	if err != nil {
+		log.Errorf("[%s] delivering notifications: %s", j.GetJobName(), err)
+	}
+}
@@ -0,0 +1,41 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type reminderJob struct {
+	interval time.Duration
+	timeout  time.Duration
+
+	svcNotification service.NotificationService
+}
+
+func NewReminderJob(interval time.Duration, timeout time.Duration, svcNotification service.NotificationService) Job {
+	return &reminderJob{
+		interval:        interval,
+		timeout:         timeout,
+		svcNotification: svcNotification,
+	}
+}
+
+func (j *reminderJob) GetJobName() string {
+	return "reminder"
+}
+
+func (j *reminderJob) Run(ctx context.Context) error {
+	return every(ctx, j.GetJobName(), j.interval, j.tick)
+}
+
+func (j *reminderJob) tick(ctx context.Context) {
+	err := drain(ctx, j.timeout, j.svcNotification.FireDue, func(count int) {
+		log.Infof("[%s] fired %d reminders", j.GetJobName(), count)
+	})
+	if err != nil {
+		log.Errorf("[%s] firing reminders: %s", j.GetJobName(), err)
+	}
+}
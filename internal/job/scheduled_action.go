@@ -0,0 +1,46 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type scheduledActionJob struct {
+	interval time.Duration
+	timeout  time.Duration
+
+	svcScheduledAction service.ScheduledActionService
+	dispatch           service.ScheduledActionDispatch
+}
+
+func NewScheduledActionJob(interval time.Duration, timeout time.Duration, svcScheduledAction service.ScheduledActionService, dispatch service.ScheduledActionDispatch) Job {
+	return &scheduledActionJob{
+		interval:           interval,
+		timeout:            timeout,
+		svcScheduledAction: svcScheduledAction,
+		dispatch:           dispatch,
+	}
+}
+
+func (j *scheduledActionJob) GetJobName() string {
+	return "scheduled-action"
+}
+
+func (j *scheduledActionJob) Run(ctx context.Context) error {
+	return every(ctx, j.GetJobName(), j.interval, j.tick)
+}
+
+// tick dispatches until nothing is due, or another queue binary holds the lock
+func (j *scheduledActionJob) tick(ctx context.Context) {
+	err := drain(ctx, j.timeout, func(ctx context.Context) (int, error) {
+		return j.svcScheduledAction.DispatchDue(ctx, j.dispatch)
+	}, func(count int) {
+		log.Infof("[%s] dispatched %d actions", j.GetJobName(), count)
+	})
+	if err != nil {
+		log.Errorf("[%s] dispatching scheduled actions: %s", j.GetJobName(), err)
+	}
+}
@@ -0,0 +1,41 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type todoSeriesJob struct {
+	interval time.Duration
+	timeout  time.Duration
+
+	svcTodoItem service.TodoItemService
+}
+
+func NewTodoSeriesJob(interval time.Duration, timeout time.Duration, svcTodoItem service.TodoItemService) Job {
+	return &todoSeriesJob{
+		interval:    interval,
+		timeout:     timeout,
+		svcTodoItem: svcTodoItem,
+	}
+}
+
+func (j *todoSeriesJob) GetJobName() string {
+	return "todo-series"
+}
+
+func (j *todoSeriesJob) Run(ctx context.Context) error {
+	return every(ctx, j.GetJobName(), j.interval, j.tick)
+}
+
+func (j *todoSeriesJob) tick(ctx context.Context) {
+	err := drain(ctx, j.timeout, j.svcTodoItem.GenerateOccurrences, func(count int) {
+		log.Infof("[%s] moved %d series to their next occurrence", j.GetJobName(), count)
+	})
+	if err != nil {
+		log.Errorf("[%s] generating occurrences: %s", j.GetJobName(), err)
+	}
+}
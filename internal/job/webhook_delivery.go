@@ -0,0 +1,41 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+type webhookDeliveryJob struct {
+	interval time.Duration
+	timeout  time.Duration
+
+	svcWebhook service.WebhookService
+}
+
+func NewWebhookDeliveryJob(interval time.Duration, timeout time.Duration, svcWebhook service.WebhookService) Job {
+	return &webhookDeliveryJob{
+		interval:   interval,
+		timeout:    timeout,
+		svcWebhook: svcWebhook,
+	}
+}
+
+func (j *webhookDeliveryJob) GetJobName() string {
+	return "webhook-delivery"
+}
+
+func (j *webhookDeliveryJob) Run(ctx context.Context) error {
+	return every(ctx, j.GetJobName(), j.interval, j.tick)
+}
+
+func (j *webhookDeliveryJob) tick(ctx context.Context) {
+	err := drain(ctx, j.timeout, j.svcWebhook.DeliverPending, func(count int) {
+		log.Infof("[%s] attempted %d deliveries", j.GetJobName(), count)
+	})
+	if err != nil {
+		log.Errorf("[%s] delivering webhooks: %s", j.GetJobName(), err)
+	}
+}
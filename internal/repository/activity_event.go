@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type ActivityEventRepository interface {
+	LastId(ctx context.Context) (int64, error)
+	TransactionHorizon(ctx context.Context) (xmin int64, xmax int64, err error)
+	FetchSince(ctx context.Context, afterId int64, limit int) ([]*entity.ActivityEvent, error)
+	FetchSinceByActivity(ctx context.Context, activityUuid string, afterId int64, limit int) ([]*entity.ActivityEvent, error)
+	Store(ctx context.Context, tx *sqlx.Tx, e *entity.ActivityEvent) (*entity.ActivityEvent, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type activityEventRepositoryPostgres struct {
+	db *sqlx.DB
+}
+
+func (r *activityEventRepositoryPostgres) TableName() string {
+	return "activity_event"
+}
+
+func (r *activityEventRepositoryPostgres) PrimaryField() string {
+	return "id"
+}
+
+func NewPostgresActivityEventRepository(db *sqlx.DB) ActivityEventRepository {
+	return &activityEventRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *activityEventRepositoryPostgres) LastId(ctx context.Context) (int64, error) {
+	var lastId int64
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("COALESCE(MAX(id), 0)").
+		From(r.TableName()).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.db.GetContext(ctx, &lastId, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return lastId, nil
+}
+
+// TransactionHorizon reads the oldest transaction id still running, xmin, and the next one to be assigned, xmax
+func (r *activityEventRepositoryPostgres) TransactionHorizon(ctx context.Context) (int64, int64, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select(
+		"txid_snapshot_xmin(txid_current_snapshot())",
+		"txid_snapshot_xmax(txid_current_snapshot())",
+	).ToSql()
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var xmin, xmax int64
+	err = r.db.QueryRowxContext(ctx, sql, args...).Scan(&xmin, &xmax)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return xmin, xmax, nil
+}
+
+func (r *activityEventRepositoryPostgres) FetchSince(ctx context.Context, afterId int64, limit int) ([]*entity.ActivityEvent, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Gt{"id": afterId}).
+		OrderBy("id asc").
+		Limit(uint64(limit)).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.ActivityEvent{}
+	err = r.db.SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *activityEventRepositoryPostgres) FetchSinceByActivity(ctx context.Context, activityUuid string, afterId int64, limit int) ([]*entity.ActivityEvent, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"activity_uuid": activityUuid}).
+		Where(sq.Gt{"id": afterId}).
+		OrderBy("id asc").
+		Limit(uint64(limit)).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.ActivityEvent{}
+	err = r.db.SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *activityEventRepositoryPostgres) Store(ctx context.Context, tx *sqlx.Tx, e *entity.ActivityEvent) (*entity.ActivityEvent, error) {
+	values := map[string]interface{}{
+		"activity_uuid": e.ActivityUuid,
+		"event":         e.Event,
+		"payload":       e.Payload,
+		"created_at":    e.CreatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(r.TableName()).
+		SetMap(values).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowxContext(ctx, sql, args...).Scan(&e.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (r *activityEventRepositoryPostgres) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Delete(r.TableName()).
+		Where(sq.Lt{"created_at": before}).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
 	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 )
@@ -14,6 +16,7 @@ type ActivityGroupRepository interface {
 	BeginTx(ctx context.Context) *sqlx.Tx
 
 	FindById(ctx context.Context, id int) (*entity.ActivityGroup, error)
+	FindByIds(ctx context.Context, ids []int) ([]*entity.ActivityGroup, error)
 	FindByUuid(ctx context.Context, uuid string) (*entity.ActivityGroup, error)
 	FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.ActivityGroup, error)
 	FetchAll(ctx context.Context, page int, limit int, sorts map[string]string, filter string) ([]*entity.ActivityGroup, error)
@@ -24,7 +27,7 @@ type ActivityGroupRepository interface {
 }
 
 type activityGroupRepositoryPostgres struct {
-	db *sqlx.DB
+	db *sqldb.DB
 }
 
 func (r *activityGroupRepositoryPostgres) TableName() string {
@@ -35,7 +38,7 @@ func (r *activityGroupRepositoryPostgres) PrimaryField() string {
 	return "id"
 }
 
-func NewPostgresActivityGroupRepository(db *sqlx.DB) ActivityGroupRepository {
+func NewPostgresActivityGroupRepository(db *sqldb.DB) ActivityGroupRepository {
 	return &activityGroupRepositoryPostgres{
 		db: db,
 	}
@@ -57,14 +60,34 @@ func (r *activityGroupRepositoryPostgres) FindById(ctx context.Context, id int)
 	}
 
 	row := entity.ActivityGroup{}
-	err = r.db.GetContext(ctx, &row, sql, args...)
+	err = r.db.Reader(ctx).GetContext(ctx, &row, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, apperror.CodeActivityGroupNotFound, "activity group not found")
 	}
 
 	return &row, nil
 }
 
+func (r *activityGroupRepositoryPostgres) FindByIds(ctx context.Context, ids []int) ([]*entity.ActivityGroup, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"id": ids}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.ActivityGroup{}
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
 func (r *activityGroupRepositoryPostgres) FindByUuid(ctx context.Context, uuid string) (*entity.ActivityGroup, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	sql, args, err := psql.Select("*").
@@ -77,19 +100,21 @@ func (r *activityGroupRepositoryPostgres) FindByUuid(ctx context.Context, uuid s
 	}
 
 	row := entity.ActivityGroup{}
-	err = r.db.GetContext(ctx, &row, sql, args...)
+	err = r.db.Reader(ctx).GetContext(ctx, &row, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, apperror.CodeActivityGroupNotFound, "activity group not found")
 	}
 
 	return &row, nil
 }
 
+// FindByUuidTx locks the activity group until tx ends, so concurrent edits aren't lost
 func (r *activityGroupRepositoryPostgres) FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.ActivityGroup, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	sql, args, err := psql.Select("*").
 		From(r.TableName()).
 		Where(sq.Eq{"uuid": uuid}).
+		Suffix("FOR UPDATE").
 		ToSql()
 
 	if err != nil {
@@ -99,7 +124,7 @@ func (r *activityGroupRepositoryPostgres) FindByUuidTx(ctx context.Context, tx *
 	row := entity.ActivityGroup{}
 	err = tx.GetContext(ctx, &row, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, apperror.CodeActivityGroupNotFound, "activity group not found")
 	}
 
 	return &row, nil
@@ -132,7 +157,7 @@ func (r *activityGroupRepositoryPostgres) FetchAll(ctx context.Context, page int
 	}
 
 	rows := []*entity.ActivityGroup{}
-	err = r.db.SelectContext(ctx, &rows, sql, args...)
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +182,7 @@ func (r *activityGroupRepositoryPostgres) CountAll(ctx context.Context, filter s
 		return 0, err
 	}
 
-	rows, err := r.db.QueryxContext(ctx, sql, args...)
+	rows, err := r.db.Reader(ctx).QueryxContext(ctx, sql, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -192,7 +217,7 @@ func (r *activityGroupRepositoryPostgres) Store(ctx context.Context, tx *sqlx.Tx
 
 	_, err = tx.ExecContext(ctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, conflict(err, apperror.CodeActivityGroupConflict, "activity group already exists")
 	}
 
 	return r.FindByUuidTx(ctx, tx, e.Uuid)
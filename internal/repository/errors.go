@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+)
+
+const uniqueViolation = "23505"
+
+// notFound turns a missing row into a typed error carrying the code of the resource
+func notFound(err error, code apperror.Code, message string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperror.NotFound(code, message).Wrap(err)
+	}
+
+	return err
+}
+
+// conflict turns a unique constraint violation into a typed error carrying the code of the resource
+func conflict(err error, code apperror.Code, message string) error {
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) && pgErr.SQLState() == uniqueViolation {
+		return apperror.Conflict(code, message).Wrap(err)
+	}
+
+	return err
+}
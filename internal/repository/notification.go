@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationRepository interface {
+	BeginTx(ctx context.Context) *sqlx.Tx
+
+	FindPreference(ctx context.Context, user string) (*entity.NotificationPreference, error)
+	SavePreference(ctx context.Context, tx *sqlx.Tx, e *entity.NotificationPreference) (*entity.NotificationPreference, error)
+
+	FetchDeliveries(ctx context.Context, user string, status string, page int, limit int) ([]*entity.NotificationDelivery, error)
+	CountDeliveries(ctx context.Context, user string, status string) (int, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, claimUntil time.Time, limit int) ([]*entity.NotificationDelivery, error)
+	StoreDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.NotificationDelivery) error
+	UpdateDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.NotificationDelivery) error
+}
+
+type notificationRepositoryPostgres struct {
+	db *sqldb.DB
+}
+
+func (r *notificationRepositoryPostgres) TableName() string {
+	return "notification_preference"
+}
+
+func (r *notificationRepositoryPostgres) DeliveryTableName() string {
+	return "notification_delivery"
+}
+
+func (r *notificationRepositoryPostgres) PrimaryField() string {
+	return "id"
+}
+
+func NewPostgresNotificationRepository(db *sqldb.DB) NotificationRepository {
+	return &notificationRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *notificationRepositoryPostgres) BeginTx(ctx context.Context) *sqlx.Tx {
+	return r.db.MustBeginTx(ctx, &sql.TxOptions{})
+}
+
+// FindPreference returns a not found error wrapping sql.ErrNoRows when user never saved preferences
+func (r *notificationRepositoryPostgres) FindPreference(ctx context.Context, user string) (*entity.NotificationPreference, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"username": user}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.NotificationPreference{}
+	err = r.db.Reader(ctx).GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeNotFound, "notification preference not found")
+	}
+
+	return &row, nil
+}
+
+// SavePreference inserts the preferences of the user or replaces the saved ones
+func (r *notificationRepositoryPostgres) SavePreference(ctx context.Context, tx *sqlx.Tx, e *entity.NotificationPreference) (*entity.NotificationPreference, error) {
+	values := map[string]interface{}{
+		"username":        e.User,
+		"email_enabled":   e.EmailEnabled,
+		"email":           e.Email,
+		"webhook_enabled": e.WebhookEnabled,
+		"webhook_url":     e.WebhookUrl,
+		"webhook_secret":  e.WebhookSecret,
+		"queue_enabled":   e.QueueEnabled,
+		"created_at":      e.CreatedAt,
+		"updated_at":      e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(r.TableName()).
+		SetMap(values).
+		Suffix("ON CONFLICT (username) DO UPDATE SET " +
+			"email_enabled = EXCLUDED.email_enabled, email = EXCLUDED.email, " +
+			"webhook_enabled = EXCLUDED.webhook_enabled, webhook_url = EXCLUDED.webhook_url, webhook_secret = EXCLUDED.webhook_secret, " +
+			"queue_enabled = EXCLUDED.queue_enabled, updated_at = EXCLUDED.updated_at " +
+			"RETURNING id, created_at").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowxContext(ctx, sql, args...).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (r *notificationRepositoryPostgres) FetchDeliveries(ctx context.Context, user string, status string, page int, limit int) ([]*entity.NotificationDelivery, error) {
+	offset := (page - 1) * limit
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	queryBuilder := psql.Select("*").
+		From(r.DeliveryTableName()).
+		Where(sq.Eq{"username": user}).
+		OrderBy("created_at desc", "id desc").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	if status != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"status": status})
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.NotificationDelivery{}
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *notificationRepositoryPostgres) CountDeliveries(ctx context.Context, user string, status string) (int, error) {
+	total := 0
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	queryBuilder := psql.Select("COUNT(id) AS total").
+		From(r.DeliveryTableName()).
+		Where(sq.Eq{"username": user})
+
+	if status != "" {
+		queryBuilder = queryBuilder.Where(sq.Eq{"status": status})
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.db.Reader(ctx).GetContext(ctx, &total, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// ClaimDueDeliveries moves the next attempt of the due deliveries to claimUntil in a single statement, so
+// concurrent workers never send the same notification twice and no lock is held while it is sent
+func (r *notificationRepositoryPostgres) ClaimDueDeliveries(ctx context.Context, now time.Time, claimUntil time.Time, limit int) ([]*entity.NotificationDelivery, error) {
+	due := sq.Select("id").
+		From(r.DeliveryTableName()).
+		Where(sq.Eq{"status": entity.NotificationDeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_at": now}).
+		OrderBy("next_attempt_at asc").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update(r.DeliveryTableName()).
+		Set("next_attempt_at", claimUntil).
+		Where(sq.Expr("id IN (?)", due)).
+		Suffix("RETURNING *").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.NotificationDelivery{}
+	err = r.db.Primary.SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *notificationRepositoryPostgres) StoreDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.NotificationDelivery) error {
+	values := map[string]interface{}{
+		"uuid":            e.Uuid,
+		"reminder_id":     e.ReminderID,
+		"username":        e.User,
+		"channel":         e.Channel,
+		"recipient":       e.Recipient,
+		"event":           e.Event,
+		"payload":         e.Payload,
+		"status":          e.Status,
+		"attempts":        e.Attempts,
+		"next_attempt_at": e.NextAttemptAt,
+		"created_at":      e.CreatedAt,
+		"updated_at":      e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(r.DeliveryTableName()).
+		SetMap(values).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *notificationRepositoryPostgres) UpdateDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.NotificationDelivery) error {
+	values := map[string]interface{}{
+		"status":          e.Status,
+		"attempts":        e.Attempts,
+		"last_error":      e.LastError,
+		"next_attempt_at": e.NextAttemptAt,
+		"delivered_at":    e.DeliveredAt,
+		"updated_at":      e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update(r.DeliveryTableName()).
+		SetMap(values).
+		Where(sq.Eq{"id": e.ID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
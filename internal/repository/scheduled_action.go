@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+// scheduledActionLockKey is the advisory lock electing the queue binary that dispatches the due actions
+const scheduledActionLockKey int64 = 0x7363686564756c65
+
+type ScheduledActionRepository interface {
+	BeginTx(ctx context.Context) *sqlx.Tx
+
+	FindByUuid(ctx context.Context, uuid string) (*entity.ScheduledAction, error)
+	FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.ScheduledAction, error)
+	FetchAll(ctx context.Context, page int, limit int, queue string, status string, activityUuid string) ([]*entity.ScheduledAction, error)
+	CountAll(ctx context.Context, queue string, status string, activityUuid string) (int, error)
+	Store(ctx context.Context, tx *sqlx.Tx, e *entity.ScheduledAction) (*entity.ScheduledAction, error)
+	Update(ctx context.Context, tx *sqlx.Tx, e *entity.ScheduledAction) (*entity.ScheduledAction, error)
+
+	LockLeaderTx(ctx context.Context, tx *sqlx.Tx) (bool, error)
+	FetchDueTx(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]*entity.ScheduledAction, error)
+}
+
+type scheduledActionRepositoryPostgres struct {
+	db *sqldb.DB
+}
+
+func (r *scheduledActionRepositoryPostgres) TableName() string {
+	return "scheduled_action"
+}
+
+func (r *scheduledActionRepositoryPostgres) PrimaryField() string {
+	return "id"
+}
+
+func NewPostgresScheduledActionRepository(db *sqldb.DB) ScheduledActionRepository {
+	return &scheduledActionRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *scheduledActionRepositoryPostgres) BeginTx(ctx context.Context) *sqlx.Tx {
+	return r.db.MustBeginTx(ctx, &sql.TxOptions{})
+}
+
+func (r *scheduledActionRepositoryPostgres) FindByUuid(ctx context.Context, uuid string) (*entity.ScheduledAction, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"uuid": uuid}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.ScheduledAction{}
+	err = r.db.Reader(ctx).GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeScheduledActionNotFound, "scheduled action not found")
+	}
+
+	return &row, nil
+}
+
+// FindByUuidTx locks the action until tx ends, so it can't be dispatched while being cancelled
+func (r *scheduledActionRepositoryPostgres) FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.ScheduledAction, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"uuid": uuid}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.ScheduledAction{}
+	err = tx.GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeScheduledActionNotFound, "scheduled action not found")
+	}
+
+	return &row, nil
+}
+
+func (r *scheduledActionRepositoryPostgres) filter(builder sq.SelectBuilder, queue string, status string, activityUuid string) sq.SelectBuilder {
+	if queue != "" {
+		builder = builder.Where(sq.Eq{"queue": queue})
+	}
+	if status != "" {
+		builder = builder.Where(sq.Eq{"status": status})
+	}
+	if activityUuid != "" {
+		builder = builder.Where(sq.Eq{"activity_uuid": activityUuid})
+	}
+
+	return builder
+}
+
+func (r *scheduledActionRepositoryPostgres) FetchAll(ctx context.Context, page int, limit int, queue string, status string, activityUuid string) ([]*entity.ScheduledAction, error) {
+	offset := (page - 1) * limit
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	queryBuilder := psql.Select("*").
+		From(r.TableName()).
+		OrderBy("execute_at asc").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	sql, args, err := r.filter(queryBuilder, queue, status, activityUuid).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.ScheduledAction{}
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *scheduledActionRepositoryPostgres) CountAll(ctx context.Context, queue string, status string, activityUuid string) (int, error) {
+	total := 0
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	queryBuilder := psql.Select("COUNT(id) AS total").
+		From(r.TableName())
+
+	sql, args, err := r.filter(queryBuilder, queue, status, activityUuid).ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.db.Reader(ctx).GetContext(ctx, &total, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *scheduledActionRepositoryPostgres) Store(ctx context.Context, tx *sqlx.Tx, e *entity.ScheduledAction) (*entity.ScheduledAction, error) {
+	values := map[string]interface{}{
+		"uuid":          e.Uuid,
+		"queue":         e.Queue,
+		"action":        e.Action,
+		"version":       e.Version,
+		"data":          e.Data,
+		"locale":        e.Locale,
+		"activity_uuid": e.ActivityUuid,
+		"status":        e.Status,
+		"execute_at":    e.ExecuteAt,
+		"created_at":    e.CreatedAt,
+		"updated_at":    e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(r.TableName()).
+		SetMap(values).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.GetContext(ctx, &e.ID, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (r *scheduledActionRepositoryPostgres) Update(ctx context.Context, tx *sqlx.Tx, e *entity.ScheduledAction) (*entity.ScheduledAction, error) {
+	values := map[string]interface{}{
+		"status":        e.Status,
+		"dispatched_at": e.DispatchedAt,
+		"cancelled_at":  e.CancelledAt,
+		"updated_at":    e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update(r.TableName()).
+		SetMap(values).
+		Where(sq.Eq{"id": e.ID}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// LockLeaderTx elects tx as the only dispatcher of the due actions until it ends, false when another instance is
+func (r *scheduledActionRepositoryPostgres) LockLeaderTx(ctx context.Context, tx *sqlx.Tx) (bool, error) {
+	locked := false
+	err := tx.GetContext(ctx, &locked, "SELECT pg_try_advisory_xact_lock($1)", scheduledActionLockKey)
+	if err != nil {
+		return false, err
+	}
+
+	return locked, nil
+}
+
+func (r *scheduledActionRepositoryPostgres) FetchDueTx(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]*entity.ScheduledAction, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"status": entity.ScheduledActionPending}).
+		Where(sq.LtOrEq{"execute_at": now}).
+		OrderBy("execute_at asc").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.ScheduledAction{}
+	err = tx.SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
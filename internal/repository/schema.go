@@ -0,0 +1,24 @@
+package repository
+
+// SchemaVersion is the newest version in schema_migration the repositories rely on, bump it together with
+// the row a change to database/ records there
+const SchemaVersion = 1
+
+// SchemaVersionTable records the versions of database/ applied to the database
+const SchemaVersionTable = "schema_migration"
+
+// Tables are created by the scripts in database/, the database isn't ready until all of them exist
+var Tables = []string{
+	"activity_group",
+	"todo_series",
+	"todo_item",
+	"webhook_endpoint",
+	"webhook_delivery",
+	"webhook_delivery_attempt",
+	"activity_event",
+	"scheduled_action",
+	"notification_preference",
+	"todo_reminder",
+	"notification_delivery",
+	SchemaVersionTable,
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
 	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 )
@@ -15,15 +17,18 @@ type TodoItemRepository interface {
 
 	FindByUuid(ctx context.Context, uuid string) (*entity.TodoItem, error)
 	FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.TodoItem, error)
+	FindByIdTx(ctx context.Context, tx *sqlx.Tx, id int) (*entity.TodoItem, error)
 	FetchAll(ctx context.Context, page int, limit int, sorts map[string]string, activityId int, filter string) ([]*entity.TodoItem, error)
 	CountAll(ctx context.Context, activityId int, filter string) (int, error)
+	CountByActivityIds(ctx context.Context, activityIds []int) (map[int]int, error)
+	CountPerActivity(ctx context.Context) (map[string]int, error)
 	Store(ctx context.Context, tx *sqlx.Tx, e *entity.TodoItem) (*entity.TodoItem, error)
 	Update(ctx context.Context, tx *sqlx.Tx, e *entity.TodoItem) (*entity.TodoItem, error)
 	Delete(ctx context.Context, tx *sqlx.Tx, e *entity.TodoItem) error
 }
 
 type todoItemRepositoryPostgres struct {
-	db *sqlx.DB
+	db *sqldb.DB
 }
 
 func (a *todoItemRepositoryPostgres) TableName() string {
@@ -34,7 +39,7 @@ func (a *todoItemRepositoryPostgres) PrimaryField() string {
 	return "id"
 }
 
-func NewPostgresTodoItemRepository(db *sqlx.DB) TodoItemRepository {
+func NewPostgresTodoItemRepository(db *sqldb.DB) TodoItemRepository {
 	return &todoItemRepositoryPostgres{
 		db: db,
 	}
@@ -56,9 +61,9 @@ func (r *todoItemRepositoryPostgres) FindByUuid(ctx context.Context, uuid string
 	}
 
 	row := entity.TodoItem{}
-	err = r.db.GetContext(ctx, &row, sql, args...)
+	err = r.db.Reader(ctx).GetContext(ctx, &row, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, apperror.CodeTodoItemNotFound, "todo item not found")
 	}
 
 	if row.Activity != nil && row.Activity.ID == 0 {
@@ -68,11 +73,13 @@ func (r *todoItemRepositoryPostgres) FindByUuid(ctx context.Context, uuid string
 	return &row, nil
 }
 
+// FindByUuidTx locks the todo item until tx ends, so concurrent edits aren't lost
 func (r *todoItemRepositoryPostgres) FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.TodoItem, error) {
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	sql, args, err := psql.Select("*").
 		From(r.TableName()).
 		Where(sq.Eq{"uuid": uuid}).
+		Suffix("FOR UPDATE").
 		ToSql()
 
 	if err != nil {
@@ -82,7 +89,7 @@ func (r *todoItemRepositoryPostgres) FindByUuidTx(ctx context.Context, tx *sqlx.
 	row := entity.TodoItem{}
 	err = tx.GetContext(ctx, &row, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, apperror.CodeTodoItemNotFound, "todo item not found")
 	}
 
 	if row.Activity != nil && row.Activity.ID == 0 {
@@ -92,6 +99,26 @@ func (r *todoItemRepositoryPostgres) FindByUuidTx(ctx context.Context, tx *sqlx.
 	return &row, nil
 }
 
+func (r *todoItemRepositoryPostgres) FindByIdTx(ctx context.Context, tx *sqlx.Tx, id int) (*entity.TodoItem, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.TodoItem{}
+	err = tx.GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeTodoItemNotFound, "todo item not found")
+	}
+
+	return &row, nil
+}
+
 func (r *todoItemRepositoryPostgres) FetchAll(ctx context.Context, page int, limit int, sorts map[string]string, activityId int, filter string) ([]*entity.TodoItem, error) {
 	offset := (page - 1) * limit
 
@@ -123,7 +150,7 @@ func (r *todoItemRepositoryPostgres) FetchAll(ctx context.Context, page int, lim
 	}
 
 	rows := []*entity.TodoItem{}
-	err = r.db.SelectContext(ctx, &rows, sql, args...)
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -158,7 +185,7 @@ func (r *todoItemRepositoryPostgres) CountAll(ctx context.Context, activityId in
 		return 0, err
 	}
 
-	rows, err := r.db.QueryxContext(ctx, sql, args...)
+	rows, err := r.db.Reader(ctx).QueryxContext(ctx, sql, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -172,12 +199,75 @@ func (r *todoItemRepositoryPostgres) CountAll(ctx context.Context, activityId in
 	return total, nil
 }
 
+func (r *todoItemRepositoryPostgres) CountByActivityIds(ctx context.Context, activityIds []int) (map[int]int, error) {
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("activity_id", "COUNT(id) AS total").
+		From(r.TableName()).
+		Where(sq.Eq{"activity_id": activityIds}).
+		GroupBy("activity_id").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []struct {
+		ActivityID int `db:"activity_id"`
+		Total      int `db:"total"`
+	}{}
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[int]int{}
+	for _, row := range rows {
+		totals[row.ActivityID] = row.Total
+	}
+
+	return totals, nil
+}
+
+// CountPerActivity counts the todo items of every activity group by activity group uuid, empty groups included
+func (r *todoItemRepositoryPostgres) CountPerActivity(ctx context.Context) (map[string]int, error) {
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("activity_group.uuid AS activity_uuid", "COUNT(todo_item.id) AS total").
+		From("activity_group").
+		LeftJoin(r.TableName() + " ON todo_item.activity_id = activity_group.id").
+		GroupBy("activity_group.uuid").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []struct {
+		ActivityUuid string `db:"activity_uuid"`
+		Total        int    `db:"total"`
+	}{}
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := map[string]int{}
+	for _, row := range rows {
+		totals[row.ActivityUuid] = row.Total
+	}
+
+	return totals, nil
+}
+
 func (r *todoItemRepositoryPostgres) Store(ctx context.Context, tx *sqlx.Tx, e *entity.TodoItem) (*entity.TodoItem, error) {
 	values := map[string]interface{}{
 		"uuid":        e.Uuid,
 		"activity_id": e.ActivityID,
 		"name":        e.Name,
 		"description": e.Description,
+		"due_at":      e.DueAt,
+		"series_id":   e.SeriesID,
 		"created_at":  e.CreatedAt,
 		"updated_at":  e.UpdatedAt,
 	}
@@ -194,7 +284,7 @@ func (r *todoItemRepositoryPostgres) Store(ctx context.Context, tx *sqlx.Tx, e *
 
 	_, err = tx.ExecContext(ctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, conflict(err, apperror.CodeTodoItemConflict, "todo item already exists")
 	}
 
 	return r.FindByUuidTx(ctx, tx, e.Uuid)
@@ -202,10 +292,12 @@ func (r *todoItemRepositoryPostgres) Store(ctx context.Context, tx *sqlx.Tx, e *
 
 func (r *todoItemRepositoryPostgres) Update(ctx context.Context, tx *sqlx.Tx, e *entity.TodoItem) (*entity.TodoItem, error) {
 	values := map[string]interface{}{
-		"activity_id": e.ActivityID,
-		"name":        e.Name,
-		"description": e.Description,
-		"updated_at":  e.UpdatedAt,
+		"activity_id":  e.ActivityID,
+		"name":         e.Name,
+		"description":  e.Description,
+		"due_at":       e.DueAt,
+		"completed_at": e.CompletedAt,
+		"updated_at":   e.UpdatedAt,
 	}
 
 	// Build SQL
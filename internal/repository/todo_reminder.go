@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type TodoReminderRepository interface {
+	BeginTx(ctx context.Context) *sqlx.Tx
+
+	FindByUuid(ctx context.Context, uuid string) (*entity.TodoReminder, error)
+	FetchByTodoItem(ctx context.Context, todoItemId int, user string) ([]*entity.TodoReminder, error)
+	FetchAllByTodoItemTx(ctx context.Context, tx *sqlx.Tx, todoItemId int) ([]*entity.TodoReminder, error)
+	Store(ctx context.Context, tx *sqlx.Tx, e *entity.TodoReminder) (*entity.TodoReminder, error)
+	Update(ctx context.Context, tx *sqlx.Tx, e *entity.TodoReminder) (*entity.TodoReminder, error)
+	Delete(ctx context.Context, tx *sqlx.Tx, e *entity.TodoReminder) error
+	FetchDueTx(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]*entity.TodoReminder, error)
+}
+
+type todoReminderRepositoryPostgres struct {
+	db *sqldb.DB
+}
+
+func (r *todoReminderRepositoryPostgres) TableName() string {
+	return "todo_reminder"
+}
+
+func (r *todoReminderRepositoryPostgres) PrimaryField() string {
+	return "id"
+}
+
+func NewPostgresTodoReminderRepository(db *sqldb.DB) TodoReminderRepository {
+	return &todoReminderRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *todoReminderRepositoryPostgres) BeginTx(ctx context.Context) *sqlx.Tx {
+	return r.db.MustBeginTx(ctx, &sql.TxOptions{})
+}
+
+func (r *todoReminderRepositoryPostgres) FindByUuid(ctx context.Context, uuid string) (*entity.TodoReminder, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"uuid": uuid}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.TodoReminder{}
+	err = r.db.Reader(ctx).GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeReminderNotFound, "reminder not found")
+	}
+
+	return &row, nil
+}
+
+func (r *todoReminderRepositoryPostgres) FetchByTodoItem(ctx context.Context, todoItemId int, user string) ([]*entity.TodoReminder, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"todo_item_id": todoItemId, "username": user}).
+		OrderBy("offset_minutes desc").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.TodoReminder{}
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// FetchAllByTodoItemTx returns the reminders every user set on a todo item
+func (r *todoReminderRepositoryPostgres) FetchAllByTodoItemTx(ctx context.Context, tx *sqlx.Tx, todoItemId int) ([]*entity.TodoReminder, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"todo_item_id": todoItemId}).
+		OrderBy("id asc").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.TodoReminder{}
+	err = tx.SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *todoReminderRepositoryPostgres) Store(ctx context.Context, tx *sqlx.Tx, e *entity.TodoReminder) (*entity.TodoReminder, error) {
+	values := map[string]interface{}{
+		"uuid":           e.Uuid,
+		"todo_item_id":   e.TodoItemID,
+		"username":       e.User,
+		"offset_minutes": e.OffsetMinutes,
+		"fired_due_at":   e.FiredDueAt,
+		"fired_at":       e.FiredAt,
+		"created_at":     e.CreatedAt,
+		"updated_at":     e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(r.TableName()).
+		SetMap(values).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowxContext(ctx, sql, args...).Scan(&e.ID)
+	if err != nil {
+		return nil, conflict(err, apperror.CodeReminderConflict, "reminder already exists")
+	}
+
+	return e, nil
+}
+
+func (r *todoReminderRepositoryPostgres) Update(ctx context.Context, tx *sqlx.Tx, e *entity.TodoReminder) (*entity.TodoReminder, error) {
+	values := map[string]interface{}{
+		"offset_minutes": e.OffsetMinutes,
+		"fired_due_at":   e.FiredDueAt,
+		"fired_at":       e.FiredAt,
+		"updated_at":     e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update(r.TableName()).
+		SetMap(values).
+		Where(sq.Eq{"id": e.ID}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (r *todoReminderRepositoryPostgres) Delete(ctx context.Context, tx *sqlx.Tx, e *entity.TodoReminder) error {
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Delete(r.TableName()).
+		Where(sq.Eq{"id": e.ID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FetchDueTx locks the reminders of open todo items whose due date minus the offset passed and which haven't
+// fired for that due date yet
+func (r *todoReminderRepositoryPostgres) FetchDueTx(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]*entity.TodoReminder, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("todo_reminder.*").
+		From(r.TableName()).
+		Join("todo_item ON todo_item.id = todo_reminder.todo_item_id").
+		Where(sq.Eq{"todo_item.completed_at": nil}).
+		Where(sq.NotEq{"todo_item.due_at": nil}).
+		Where("todo_item.due_at - todo_reminder.offset_minutes * INTERVAL '1 minute' <= ?", now).
+		Where("todo_reminder.fired_due_at IS DISTINCT FROM todo_item.due_at").
+		OrderBy("todo_reminder.id asc").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF todo_reminder SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.TodoReminder{}
+	err = tx.SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type TodoSeriesRepository interface {
+	BeginTx(ctx context.Context) *sqlx.Tx
+
+	FindById(ctx context.Context, id int) (*entity.TodoSeries, error)
+	FindByIdTx(ctx context.Context, tx *sqlx.Tx, id int) (*entity.TodoSeries, error)
+	Store(ctx context.Context, tx *sqlx.Tx, e *entity.TodoSeries) (*entity.TodoSeries, error)
+	Update(ctx context.Context, tx *sqlx.Tx, e *entity.TodoSeries) (*entity.TodoSeries, error)
+
+	FetchDueTx(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]*entity.TodoSeries, error)
+}
+
+type todoSeriesRepositoryPostgres struct {
+	db *sqldb.DB
+}
+
+func (r *todoSeriesRepositoryPostgres) TableName() string {
+	return "todo_series"
+}
+
+func (r *todoSeriesRepositoryPostgres) PrimaryField() string {
+	return "id"
+}
+
+func NewPostgresTodoSeriesRepository(db *sqldb.DB) TodoSeriesRepository {
+	return &todoSeriesRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *todoSeriesRepositoryPostgres) BeginTx(ctx context.Context) *sqlx.Tx {
+	return r.db.MustBeginTx(ctx, &sql.TxOptions{})
+}
+
+func (r *todoSeriesRepositoryPostgres) FindById(ctx context.Context, id int) (*entity.TodoSeries, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.TodoSeries{}
+	err = r.db.Reader(ctx).GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeTodoSeriesNotFound, "todo series not found")
+	}
+
+	return &row, nil
+}
+
+// FindByIdTx locks the series until tx ends, so its next occurrence isn't created while it is edited
+func (r *todoSeriesRepositoryPostgres) FindByIdTx(ctx context.Context, tx *sqlx.Tx, id int) (*entity.TodoSeries, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.TodoSeries{}
+	err = tx.GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeTodoSeriesNotFound, "todo series not found")
+	}
+
+	return &row, nil
+}
+
+func (r *todoSeriesRepositoryPostgres) Store(ctx context.Context, tx *sqlx.Tx, e *entity.TodoSeries) (*entity.TodoSeries, error) {
+	values := map[string]interface{}{
+		"uuid":            e.Uuid,
+		"activity_id":     e.ActivityID,
+		"name":            e.Name,
+		"description":     e.Description,
+		"rrule":           e.Rrule,
+		"start_at":        e.StartAt,
+		"occurrences":     e.Occurrences,
+		"current_due_at":  e.CurrentDueAt,
+		"current_item_id": e.CurrentItemID,
+		"created_at":      e.CreatedAt,
+		"updated_at":      e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(r.TableName()).
+		SetMap(values).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.GetContext(ctx, &e.ID, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (r *todoSeriesRepositoryPostgres) Update(ctx context.Context, tx *sqlx.Tx, e *entity.TodoSeries) (*entity.TodoSeries, error) {
+	values := map[string]interface{}{
+		"name":            e.Name,
+		"description":     e.Description,
+		"rrule":           e.Rrule,
+		"start_at":        e.StartAt,
+		"occurrences":     e.Occurrences,
+		"current_due_at":  e.CurrentDueAt,
+		"current_item_id": e.CurrentItemID,
+		"ended_at":        e.EndedAt,
+		"updated_at":      e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update(r.TableName()).
+		SetMap(values).
+		Where(sq.Eq{"id": e.ID}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// FetchDueTx locks the running series whose current occurrence is completed, due or deleted
+func (r *todoSeriesRepositoryPostgres) FetchDueTx(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]*entity.TodoSeries, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("todo_series.*").
+		From(r.TableName()).
+		LeftJoin("todo_item ON todo_item.id = todo_series.current_item_id").
+		Where(sq.Eq{"todo_series.ended_at": nil}).
+		Where(sq.Or{
+			sq.Eq{"todo_item.id": nil},
+			sq.NotEq{"todo_item.completed_at": nil},
+			sq.LtOrEq{"todo_item.due_at": now},
+		}).
+		OrderBy("todo_series.id asc").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE OF todo_series SKIP LOCKED").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.TodoSeries{}
+	err = tx.SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,442 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type WebhookRepository interface {
+	BeginTx(ctx context.Context) *sqlx.Tx
+
+	FindById(ctx context.Context, id int) (*entity.WebhookEndpoint, error)
+	FindByIdTx(ctx context.Context, tx *sqlx.Tx, id int) (*entity.WebhookEndpoint, error)
+	FindByUuid(ctx context.Context, uuid string) (*entity.WebhookEndpoint, error)
+	FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.WebhookEndpoint, error)
+	FetchAll(ctx context.Context, activityId int) ([]*entity.WebhookEndpoint, error)
+	FetchActiveTx(ctx context.Context, tx *sqlx.Tx, activityUuid string) ([]*entity.WebhookEndpoint, error)
+	Store(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookEndpoint) (*entity.WebhookEndpoint, error)
+	Update(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookEndpoint) (*entity.WebhookEndpoint, error)
+	Delete(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookEndpoint) error
+
+	FetchDeliveries(ctx context.Context, endpointId int, page int, limit int) ([]*entity.WebhookDelivery, error)
+	CountDeliveries(ctx context.Context, endpointId int) (int, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, claimUntil time.Time, limit int) ([]*entity.WebhookDelivery, error)
+	StoreDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookDelivery) error
+	StoreDeliveryAttempt(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookDeliveryAttempt) error
+}
+
+type webhookRepositoryPostgres struct {
+	db *sqldb.DB
+}
+
+func (r *webhookRepositoryPostgres) TableName() string {
+	return "webhook_endpoint"
+}
+
+func (r *webhookRepositoryPostgres) DeliveryTableName() string {
+	return "webhook_delivery"
+}
+
+func (r *webhookRepositoryPostgres) AttemptTableName() string {
+	return "webhook_delivery_attempt"
+}
+
+func (r *webhookRepositoryPostgres) PrimaryField() string {
+	return "id"
+}
+
+func NewPostgresWebhookRepository(db *sqldb.DB) WebhookRepository {
+	return &webhookRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r *webhookRepositoryPostgres) BeginTx(ctx context.Context) *sqlx.Tx {
+	return r.db.MustBeginTx(ctx, &sql.TxOptions{})
+}
+
+func (r *webhookRepositoryPostgres) FindById(ctx context.Context, id int) (*entity.WebhookEndpoint, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.WebhookEndpoint{}
+	err = r.db.Reader(ctx).GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeWebhookNotFound, "webhook not found")
+	}
+
+	return &row, nil
+}
+
+// FindByIdTx locks the endpoint so concurrent deliveries count its failures one after another
+func (r *webhookRepositoryPostgres) FindByIdTx(ctx context.Context, tx *sqlx.Tx, id int) (*entity.WebhookEndpoint, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.WebhookEndpoint{}
+	err = tx.GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeWebhookNotFound, "webhook not found")
+	}
+
+	return &row, nil
+}
+
+func (r *webhookRepositoryPostgres) FindByUuid(ctx context.Context, uuid string) (*entity.WebhookEndpoint, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"uuid": uuid}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.WebhookEndpoint{}
+	err = r.db.Reader(ctx).GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeWebhookNotFound, "webhook not found")
+	}
+
+	return &row, nil
+}
+
+// FindByUuidTx locks the endpoint until tx ends, so concurrent edits aren't lost
+func (r *webhookRepositoryPostgres) FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.WebhookEndpoint, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"uuid": uuid}).
+		Suffix("FOR UPDATE").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	row := entity.WebhookEndpoint{}
+	err = tx.GetContext(ctx, &row, sql, args...)
+	if err != nil {
+		return nil, notFound(err, apperror.CodeWebhookNotFound, "webhook not found")
+	}
+
+	return &row, nil
+}
+
+func (r *webhookRepositoryPostgres) FetchAll(ctx context.Context, activityId int) ([]*entity.WebhookEndpoint, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.TableName()).
+		Where(sq.Eq{"activity_id": activityId}).
+		OrderBy("created_at asc").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.WebhookEndpoint{}
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// FetchActiveTx reads through tx, so it still sees the endpoints of a group the transaction is about to delete
+func (r *webhookRepositoryPostgres) FetchActiveTx(ctx context.Context, tx *sqlx.Tx, activityUuid string) ([]*entity.WebhookEndpoint, error) {
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select(r.TableName() + ".*").
+		From(r.TableName()).
+		Join("activity_group ON activity_group.id = " + r.TableName() + ".activity_id").
+		Where(sq.Eq{"activity_group.uuid": activityUuid, r.TableName() + ".is_active": true}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.WebhookEndpoint{}
+	err = tx.SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *webhookRepositoryPostgres) Store(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookEndpoint) (*entity.WebhookEndpoint, error) {
+	values := map[string]interface{}{
+		"uuid":          e.Uuid,
+		"activity_id":   e.ActivityID,
+		"url":           e.Url,
+		"secret":        e.Secret,
+		"events":        e.Events,
+		"is_active":     e.IsActive,
+		"failure_count": e.FailureCount,
+		"created_at":    e.CreatedAt,
+		"updated_at":    e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(r.TableName()).
+		SetMap(values).
+		Suffix("RETURNING id").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowxContext(ctx, sql, args...).Scan(&e.ID)
+	if err != nil {
+		return nil, conflict(err, apperror.CodeWebhookConflict, "webhook already exists")
+	}
+
+	return e, nil
+}
+
+func (r *webhookRepositoryPostgres) Update(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookEndpoint) (*entity.WebhookEndpoint, error) {
+	values := map[string]interface{}{
+		"url":           e.Url,
+		"secret":        e.Secret,
+		"events":        e.Events,
+		"is_active":     e.IsActive,
+		"failure_count": e.FailureCount,
+		"disabled_at":   e.DisabledAt,
+		"updated_at":    e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update(r.TableName()).
+		SetMap(values).
+		Where(sq.Eq{"id": e.ID}).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (r *webhookRepositoryPostgres) Delete(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookEndpoint) error {
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Delete(r.TableName()).
+		Where(sq.Eq{"id": e.ID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhookRepositoryPostgres) FetchDeliveries(ctx context.Context, endpointId int, page int, limit int) ([]*entity.WebhookDelivery, error) {
+	offset := (page - 1) * limit
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("*").
+		From(r.DeliveryTableName()).
+		Where(sq.Eq{"endpoint_id": endpointId}).
+		OrderBy("created_at desc").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.WebhookDelivery{}
+	err = r.db.Reader(ctx).SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *webhookRepositoryPostgres) CountDeliveries(ctx context.Context, endpointId int) (int, error) {
+	total := 0
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Select("COUNT(id) AS total").
+		From(r.DeliveryTableName()).
+		Where(sq.Eq{"endpoint_id": endpointId}).
+		ToSql()
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.db.Reader(ctx).GetContext(ctx, &total, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// ClaimDueDeliveries moves the next attempt of the due deliveries to claimUntil in a single statement, so
+// concurrent workers never send the same delivery twice and no lock is held while it is sent
+func (r *webhookRepositoryPostgres) ClaimDueDeliveries(ctx context.Context, now time.Time, claimUntil time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	due := sq.Select("id").
+		From(r.DeliveryTableName()).
+		Where(sq.Eq{"status": entity.WebhookDeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_at": now}).
+		OrderBy("next_attempt_at asc").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update(r.DeliveryTableName()).
+		Set("next_attempt_at", claimUntil).
+		Where(sq.Expr("id IN (?)", due)).
+		Suffix("RETURNING *").
+		ToSql()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []*entity.WebhookDelivery{}
+	err = r.db.Primary.SelectContext(ctx, &rows, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *webhookRepositoryPostgres) StoreDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookDelivery) error {
+	values := map[string]interface{}{
+		"uuid":            e.Uuid,
+		"endpoint_id":     e.EndpointID,
+		"url":             e.Url,
+		"secret":          e.Secret,
+		"event":           e.Event,
+		"payload":         e.Payload,
+		"status":          e.Status,
+		"attempts":        e.Attempts,
+		"next_attempt_at": e.NextAttemptAt,
+		"created_at":      e.CreatedAt,
+		"updated_at":      e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(r.DeliveryTableName()).
+		SetMap(values).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhookRepositoryPostgres) UpdateDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookDelivery) error {
+	values := map[string]interface{}{
+		"status":           e.Status,
+		"attempts":         e.Attempts,
+		"last_status_code": e.LastStatusCode,
+		"last_error":       e.LastError,
+		"next_attempt_at":  e.NextAttemptAt,
+		"delivered_at":     e.DeliveredAt,
+		"updated_at":       e.UpdatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Update(r.DeliveryTableName()).
+		SetMap(values).
+		Where(sq.Eq{"id": e.ID}).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *webhookRepositoryPostgres) StoreDeliveryAttempt(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookDeliveryAttempt) error {
+	values := map[string]interface{}{
+		"delivery_id": e.DeliveryID,
+		"attempt":     e.Attempt,
+		"status_code": e.StatusCode,
+		"error":       e.Error,
+		"duration_ms": e.DurationMs,
+		"created_at":  e.CreatedAt,
+	}
+
+	// Build SQL
+	psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	sql, args, err := psql.Insert(r.AttemptTableName()).
+		SetMap(values).
+		ToSql()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
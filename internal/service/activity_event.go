@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/event"
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+const activityEventBatchSize = 500
+
+type ActivityEventService interface {
+	event.Listener
+
+	LastId(ctx context.Context) (int64, error)
+	// TransactionHorizon is the oldest transaction still running, xmin, and the next one to start, xmax.
+	// Once xmin reaches the xmax read earlier, every transaction running back then has ended.
+	TransactionHorizon(ctx context.Context) (xmin int64, xmax int64, err error)
+	FetchSince(ctx context.Context, afterId int64) ([]*entity.ActivityEvent, error)
+	FetchSinceByActivity(ctx context.Context, activityUuid string, afterId int64) ([]*entity.ActivityEvent, error)
+	Prune(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type activityEventService struct {
+	timeouts Timeouts
+	repo     repository.ActivityEventRepository
+}
+
+func NewActivityEventService(timeouts Timeouts, repo repository.ActivityEventRepository) ActivityEventService {
+	return &activityEventService{
+		timeouts: timeouts,
+		repo:     repo,
+	}
+}
+
+// Handle appends the event to the activity event log, which is what live streams in every process read from
+func (s *activityEventService) Handle(ctx context.Context, tx *sqlx.Tx, e event.Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.Store(ctx, tx, &entity.ActivityEvent{
+		ActivityUuid: e.ActivityUuid,
+		Event:        e.Name,
+		Payload:      string(payload),
+		CreatedAt:    e.OccurredAt,
+	})
+
+	return err
+}
+
+func (s *activityEventService) LastId(ctx context.Context) (int64, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	return s.repo.LastId(ctx)
+}
+
+func (s *activityEventService) TransactionHorizon(ctx context.Context) (int64, int64, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	return s.repo.TransactionHorizon(ctx)
+}
+
+func (s *activityEventService) FetchSince(ctx context.Context, afterId int64) ([]*entity.ActivityEvent, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	return s.repo.FetchSince(ctx, afterId, activityEventBatchSize)
+}
+
+func (s *activityEventService) FetchSinceByActivity(ctx context.Context, activityUuid string, afterId int64) ([]*entity.ActivityEvent, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	return s.repo.FetchSinceByActivity(ctx, activityUuid, afterId, activityEventBatchSize)
+}
+
+func (s *activityEventService) Prune(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.DeleteBefore(ctx, time.Now().Add(-retention))
+}
@@ -2,11 +2,12 @@ package service
 
 import (
 	"context"
-	"math"
 	"time"
 
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
 	"github.com/Adhiana46/go-restapi-template/internal/dto"
 	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/event"
 	"github.com/Adhiana46/go-restapi-template/internal/repository"
 	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
 	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
@@ -15,32 +16,37 @@ import (
 )
 
 type ActivityGroupService interface {
-	FindByUuid(req dto.ActivityGroupUuidRequest) (*entity.ActivityGroup, error)
-	FetchAll(req dto.ActivityGroupFetchRequest) ([]*entity.ActivityGroup, *responsePkg.Pagination, error)
-	Create(req dto.ActivityGroupCreateRequest) (*entity.ActivityGroup, error)
-	Update(req dto.ActivityGroupUpdateRequest) (*entity.ActivityGroup, error)
-	Delete(req dto.ActivityGroupUuidRequest) error
+	FindByUuid(ctx context.Context, req dto.ActivityGroupUuidRequest) (*entity.ActivityGroup, error)
+	FindByIds(ctx context.Context, req dto.ActivityGroupIdsRequest) ([]*entity.ActivityGroup, error)
+	FetchAll(ctx context.Context, req dto.ActivityGroupFetchRequest) ([]*entity.ActivityGroup, *responsePkg.Pagination, error)
+	Create(ctx context.Context, req dto.ActivityGroupCreateRequest) (*entity.ActivityGroup, error)
+	Update(ctx context.Context, req dto.ActivityGroupUpdateRequest) (*entity.ActivityGroup, error)
+	Delete(ctx context.Context, req dto.ActivityGroupUuidRequest) error
 }
 
 type activityGroupService struct {
-	validate *validator.Validate
-	repo     repository.ActivityGroupRepository
+	validate   *validator.Validate
+	timeouts   Timeouts
+	repo       repository.ActivityGroupRepository
+	dispatcher event.Dispatcher
 }
 
-func NewActivityGroupService(validate *validator.Validate, repo repository.ActivityGroupRepository) ActivityGroupService {
+func NewActivityGroupService(validate *validator.Validate, timeouts Timeouts, repo repository.ActivityGroupRepository, dispatcher event.Dispatcher) ActivityGroupService {
 	return &activityGroupService{
-		validate: validate,
-		repo:     repo,
+		validate:   validate,
+		timeouts:   timeouts,
+		repo:       repo,
+		dispatcher: dispatcher,
 	}
 }
 
-func (s *activityGroupService) FindByUuid(req dto.ActivityGroupUuidRequest) (*entity.ActivityGroup, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *activityGroupService) FindByUuid(ctx context.Context, req dto.ActivityGroupUuidRequest) (*entity.ActivityGroup, error) {
+	ctx, cancel := s.timeouts.read(ctx)
 	defer cancel()
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
-		return nil, err
+		return nil, apperror.Validation(err)
 	}
 
 	activityGroup, err := s.repo.FindByUuid(ctx, req.Uuid)
@@ -51,8 +57,20 @@ func (s *activityGroupService) FindByUuid(req dto.ActivityGroupUuidRequest) (*en
 	return activityGroup, nil
 }
 
-func (s *activityGroupService) FetchAll(req dto.ActivityGroupFetchRequest) ([]*entity.ActivityGroup, *responsePkg.Pagination, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *activityGroupService) FindByIds(ctx context.Context, req dto.ActivityGroupIdsRequest) ([]*entity.ActivityGroup, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	return s.repo.FindByIds(ctx, req.Ids)
+}
+
+func (s *activityGroupService) FetchAll(ctx context.Context, req dto.ActivityGroupFetchRequest) ([]*entity.ActivityGroup, *responsePkg.Pagination, error) {
+	countCtx, ctx, cancel := s.timeouts.page(ctx)
 	defer cancel()
 
 	// Set Default Value
@@ -68,17 +86,17 @@ func (s *activityGroupService) FetchAll(req dto.ActivityGroupFetchRequest) ([]*e
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
-		return nil, nil, err
+		return nil, nil, apperror.Validation(err)
 	}
 
-	totalRows, err := s.repo.CountAll(ctx, req.Filter)
+	totalRows, err := s.repo.CountAll(countCtx, req.Filter)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	sorts, err := parserPkg.QuerySortToMap(req.SortBy)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, apperror.BadRequest(apperror.CodeInvalidSort, err.Error())
 	}
 
 	activityGroupList, err := s.repo.FetchAll(ctx, req.Page, req.Limit, sorts, req.Filter)
@@ -87,23 +105,18 @@ func (s *activityGroupService) FetchAll(req dto.ActivityGroupFetchRequest) ([]*e
 	}
 
 	// Create Pagination
-	pagination := responsePkg.Pagination{
-		CurrentPage: req.Page,
-		Total:       totalRows,
-		Size:        len(activityGroupList),
-		TotalPages:  int(math.Ceil(float64(totalRows) / float64(req.Limit))),
-	}
+	pagination := newPagination(req.Page, req.Limit, totalRows, len(activityGroupList))
 
-	return activityGroupList, &pagination, nil
+	return activityGroupList, pagination, nil
 }
 
-func (s *activityGroupService) Create(req dto.ActivityGroupCreateRequest) (*entity.ActivityGroup, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *activityGroupService) Create(ctx context.Context, req dto.ActivityGroupCreateRequest) (*entity.ActivityGroup, error) {
+	ctx, cancel := s.timeouts.write(ctx)
 	defer cancel()
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
-		return nil, err
+		return nil, apperror.Validation(err)
 	}
 
 	ent := &entity.ActivityGroup{
@@ -117,6 +130,9 @@ func (s *activityGroupService) Create(req dto.ActivityGroupCreateRequest) (*enti
 	// begin transaction
 	tx := s.repo.BeginTx(ctx)
 	insertedRow, err := s.repo.Store(ctx, tx, ent)
+	if err == nil {
+		err = s.dispatcher.Dispatch(ctx, tx, event.New(event.ActivityGroupCreated, insertedRow.Uuid, dto.ActivityGroupToResponse(insertedRow)))
+	}
 
 	// if error rollback, commit otherwise
 	if err != nil {
@@ -129,18 +145,21 @@ func (s *activityGroupService) Create(req dto.ActivityGroupCreateRequest) (*enti
 	return insertedRow, nil
 }
 
-func (s *activityGroupService) Update(req dto.ActivityGroupUpdateRequest) (*entity.ActivityGroup, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *activityGroupService) Update(ctx context.Context, req dto.ActivityGroupUpdateRequest) (*entity.ActivityGroup, error) {
+	ctx, cancel := s.timeouts.write(ctx)
 	defer cancel()
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
-		return nil, err
+		return nil, apperror.Validation(err)
 	}
 
-	ent, err := s.repo.FindByUuid(ctx, req.Uuid)
+	// begin transaction, the group is read on the primary and locked until the update commits
+	tx := s.repo.BeginTx(ctx)
+	ent, err := s.repo.FindByUuidTx(ctx, tx, req.Uuid)
 	if err != nil {
-		return ent, err
+		tx.Rollback()
+		return nil, err
 	}
 
 	// Update values
@@ -148,9 +167,10 @@ func (s *activityGroupService) Update(req dto.ActivityGroupUpdateRequest) (*enti
 	ent.Description = req.Description
 	ent.UpdatedAt = time.Now()
 
-	// begin transaction
-	tx := s.repo.BeginTx(ctx)
 	updatedRow, err := s.repo.Update(ctx, tx, ent)
+	if err == nil {
+		err = s.dispatcher.Dispatch(ctx, tx, event.New(event.ActivityGroupUpdated, updatedRow.Uuid, dto.ActivityGroupToResponse(updatedRow)))
+	}
 
 	// if error rollback, commit otherwise
 	if err != nil {
@@ -163,23 +183,28 @@ func (s *activityGroupService) Update(req dto.ActivityGroupUpdateRequest) (*enti
 	return updatedRow, nil
 }
 
-func (s *activityGroupService) Delete(req dto.ActivityGroupUuidRequest) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *activityGroupService) Delete(ctx context.Context, req dto.ActivityGroupUuidRequest) error {
+	ctx, cancel := s.timeouts.write(ctx)
 	defer cancel()
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
-		return err
+		return apperror.Validation(err)
 	}
 
-	ent, err := s.repo.FindByUuid(ctx, req.Uuid)
+	// begin transaction, the group is read on the primary and locked until it is deleted
+	tx := s.repo.BeginTx(ctx)
+	ent, err := s.repo.FindByUuidTx(ctx, tx, req.Uuid)
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	// begin transaction
-	tx := s.repo.BeginTx(ctx)
-	err = s.repo.Delete(ctx, tx, ent)
+	// dispatched before the delete, listeners still see the rows that cascade with the group
+	err = s.dispatcher.Dispatch(ctx, tx, event.New(event.ActivityGroupDeleted, ent.Uuid, dto.ActivityGroupToResponse(ent)))
+	if err == nil {
+		err = s.repo.Delete(ctx, tx, ent)
+	}
 
 	// if error rollback, commit otherwise
 	if err != nil {
@@ -0,0 +1,520 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/Adhiana46/go-restapi-template/pkg/notify"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	notificationReminderEvent = "todo-item.reminder"
+	notificationMaxAttempts   = 8
+	notificationDeliveryClaim = 10 * time.Minute
+)
+
+// NotificationBatchSize is the most reminders FireDue fires and the most deliveries DeliverPending sends, one
+// after the other
+const NotificationBatchSize = 20
+
+type NotificationService interface {
+	FetchReminders(ctx context.Context, req dto.TodoReminderFetchRequest) ([]*entity.TodoReminder, error)
+	CreateReminder(ctx context.Context, req dto.TodoReminderCreateRequest) (*entity.TodoReminder, error)
+	DeleteReminder(ctx context.Context, req dto.TodoReminderUuidRequest) error
+
+	FindPreference(ctx context.Context, req dto.NotificationPreferenceFindRequest) (*entity.NotificationPreference, error)
+	UpdatePreference(ctx context.Context, req dto.NotificationPreferenceUpdateRequest) (*entity.NotificationPreference, error)
+	FetchDeliveries(ctx context.Context, req dto.NotificationDeliveryFetchRequest) ([]*entity.NotificationDelivery, *responsePkg.Pagination, error)
+
+	FireDue(ctx context.Context) (int, error)
+	DeliverPending(ctx context.Context) (int, error)
+}
+
+type notificationService struct {
+	validate     *validator.Validate
+	timeouts     Timeouts
+	repo         repository.NotificationRepository
+	repoReminder repository.TodoReminderRepository
+	repoTodoItem repository.TodoItemRepository
+	repoActivity repository.ActivityGroupRepository
+	channels     map[string]notify.Channel
+}
+
+// NewNotificationService delivers the notifications on channels by name, deliveries on a channel left out fail
+func NewNotificationService(validate *validator.Validate, timeouts Timeouts, repo repository.NotificationRepository, repoReminder repository.TodoReminderRepository, repoTodoItem repository.TodoItemRepository, repoActivity repository.ActivityGroupRepository, channels []notify.Channel) NotificationService {
+	byName := map[string]notify.Channel{}
+	for _, channel := range channels {
+		byName[channel.Name()] = channel
+	}
+
+	return &notificationService{
+		validate:     validate,
+		timeouts:     timeouts,
+		repo:         repo,
+		repoReminder: repoReminder,
+		repoTodoItem: repoTodoItem,
+		repoActivity: repoActivity,
+		channels:     byName,
+	}
+}
+
+func (s *notificationService) FetchReminders(ctx context.Context, req dto.TodoReminderFetchRequest) ([]*entity.TodoReminder, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	todoItem, err := s.findTodoItem(ctx, req.ActivityUuid, req.TodoItemUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	reminderList, err := s.repoReminder.FetchByTodoItem(ctx, todoItem.ID, req.User)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reminder := range reminderList {
+		reminder.TodoItem = todoItem
+	}
+
+	return reminderList, nil
+}
+
+func (s *notificationService) CreateReminder(ctx context.Context, req dto.TodoReminderCreateRequest) (*entity.TodoReminder, error) {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	todoItem, err := s.findTodoItem(ctx, req.ActivityUuid, req.TodoItemUuid)
+	if err != nil {
+		return nil, err
+	}
+	if todoItem.DueAt == nil {
+		return nil, apperror.BadRequest(apperror.CodeReminderNoDueAt, "todo item has no due date")
+	}
+
+	ent := &entity.TodoReminder{
+		Uuid:          uuid.NewString(),
+		TodoItemID:    todoItem.ID,
+		User:          req.User,
+		OffsetMinutes: req.OffsetMinutes,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	// begin transaction
+	tx := s.repoReminder.BeginTx(ctx)
+	insertedRow, err := s.repoReminder.Store(ctx, tx, ent)
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	} else {
+		tx.Commit()
+	}
+
+	insertedRow.TodoItem = todoItem
+
+	return insertedRow, nil
+}
+
+func (s *notificationService) DeleteReminder(ctx context.Context, req dto.TodoReminderUuidRequest) error {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return apperror.Validation(err)
+	}
+
+	todoItem, err := s.findTodoItem(ctx, req.ActivityUuid, req.TodoItemUuid)
+	if err != nil {
+		return err
+	}
+
+	ent, err := s.repoReminder.FindByUuid(ctx, req.Uuid)
+	if err != nil {
+		return err
+	}
+	// reminders of other users are as good as missing
+	if ent.TodoItemID != todoItem.ID || ent.User != req.User {
+		return apperror.NotFound(apperror.CodeReminderNotFound, "reminder not found")
+	}
+
+	// begin transaction
+	tx := s.repoReminder.BeginTx(ctx)
+	err = s.repoReminder.Delete(ctx, tx, ent)
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return err
+	} else {
+		tx.Commit()
+	}
+
+	return nil
+}
+
+func (s *notificationService) FindPreference(ctx context.Context, req dto.NotificationPreferenceFindRequest) (*entity.NotificationPreference, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	return s.preference(ctx, req.User)
+}
+
+func (s *notificationService) UpdatePreference(ctx context.Context, req dto.NotificationPreferenceUpdateRequest) (*entity.NotificationPreference, error) {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	ent, err := s.preference(ctx, req.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update values
+	ent.EmailEnabled = req.EmailEnabled
+	ent.Email = req.Email
+	ent.WebhookEnabled = req.WebhookEnabled
+	ent.WebhookUrl = req.WebhookUrl
+	if req.WebhookSecret != "" {
+		ent.WebhookSecret = req.WebhookSecret
+	}
+	ent.QueueEnabled = req.QueueEnabled
+	ent.UpdatedAt = time.Now()
+
+	if ent.WebhookEnabled && ent.WebhookSecret == "" {
+		return nil, apperror.BadRequest(apperror.CodeValidationFailed, "webhook_secret is required to enable the webhook channel")
+	}
+
+	// begin transaction
+	tx := s.repo.BeginTx(ctx)
+	savedRow, err := s.repo.SavePreference(ctx, tx, ent)
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	} else {
+		tx.Commit()
+	}
+
+	return savedRow, nil
+}
+
+func (s *notificationService) FetchDeliveries(ctx context.Context, req dto.NotificationDeliveryFetchRequest) ([]*entity.NotificationDelivery, *responsePkg.Pagination, error) {
+	countCtx, ctx, cancel := s.timeouts.page(ctx)
+	defer cancel()
+
+	// Set Default Value
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, nil, apperror.Validation(err)
+	}
+
+	totalRows, err := s.repo.CountDeliveries(countCtx, req.User, req.Status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deliveryList, err := s.repo.FetchDeliveries(ctx, req.User, req.Status, req.Page, req.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create Pagination
+	pagination := newPagination(req.Page, req.Limit, totalRows, len(deliveryList))
+
+	return deliveryList, pagination, nil
+}
+
+// FireDue queues a delivery on every channel the user enabled for one batch of due reminders and returns how
+// many reminders fired. A reminder fires once per due date, a user without enabled channels isn't notified.
+func (s *notificationService) FireDue(ctx context.Context) (int, error) {
+	tx := s.repoReminder.BeginTx(ctx)
+	now := time.Now()
+
+	reminders, err := s.repoReminder.FetchDueTx(ctx, tx, now, NotificationBatchSize)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	for _, reminder := range reminders {
+		if err := s.fire(ctx, tx, reminder, now); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	return len(reminders), tx.Commit()
+}
+
+// DeliverPending sends one batch of due deliveries and returns how many were attempted. Like the webhook
+// deliveries the batch is claimed up front and every send is recorded in a short transaction of its own, so no
+// lock or connection is held while a mail server or endpoint answers.
+func (s *notificationService) DeliverPending(ctx context.Context) (int, error) {
+	now := time.Now()
+	claimUntil := now.Add(notificationDeliveryClaim)
+	if deadline, ok := ctx.Deadline(); ok && deadline.After(claimUntil) {
+		claimUntil = deadline
+	}
+
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, now, claimUntil, NotificationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	preferences := map[string]*entity.NotificationPreference{}
+	for _, delivery := range deliveries {
+		preference, ok := preferences[delivery.User]
+		if !ok {
+			preference, err = s.preference(ctx, delivery.User)
+			if err != nil {
+				return 0, err
+			}
+			preferences[delivery.User] = preference
+		}
+
+		if err := s.deliver(ctx, preference, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (s *notificationService) fire(ctx context.Context, tx *sqlx.Tx, reminder *entity.TodoReminder, now time.Time) error {
+	todoItem, err := s.repoTodoItem.FindByIdTx(ctx, tx, reminder.TodoItemID)
+	if err != nil {
+		return err
+	}
+
+	activity, err := s.repoActivity.FindById(ctx, todoItem.ActivityID)
+	if err != nil {
+		return err
+	}
+
+	preference, err := s.preference(ctx, reminder.User)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(dto.ReminderNotification{
+		Event:         notificationReminderEvent,
+		ReminderUuid:  reminder.Uuid,
+		User:          reminder.User,
+		ActivityUuid:  activity.Uuid,
+		OffsetMinutes: reminder.OffsetMinutes,
+		DueAt:         *todoItem.DueAt,
+		TodoItem:      dto.TodoItemToResponse(todoItem),
+		FiredAt:       now,
+	})
+	if err != nil {
+		return err
+	}
+
+	for channel, recipient := range notificationRecipients(preference) {
+		err := s.repo.StoreDelivery(ctx, tx, &entity.NotificationDelivery{
+			Uuid:          uuid.NewString(),
+			ReminderID:    &reminder.ID,
+			User:          reminder.User,
+			Channel:       channel,
+			Recipient:     recipient,
+			Event:         notificationReminderEvent,
+			Payload:       string(payload),
+			Status:        entity.NotificationDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	reminder.FiredDueAt = todoItem.DueAt
+	reminder.FiredAt = &now
+	reminder.UpdatedAt = now
+	_, err = s.repoReminder.Update(ctx, tx, reminder)
+
+	return err
+}
+
+func (s *notificationService) deliver(ctx context.Context, preference *entity.NotificationPreference, delivery *entity.NotificationDelivery) error {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+
+	channel, ok := s.channels[delivery.Channel]
+	if !ok {
+		errMsg := fmt.Sprintf("%s channel not configured", delivery.Channel)
+		delivery.Status = entity.NotificationDeliveryFailed
+		delivery.LastError = &errMsg
+		return s.record(ctx, delivery)
+	}
+
+	// a payload that doesn't decode never will, retrying it would only hold up the deliveries behind it
+	msg, err := notificationMessage(preference, delivery)
+	if err != nil {
+		errMsg := fmt.Sprintf("malformed payload: %s", err)
+		delivery.Status = entity.NotificationDeliveryFailed
+		delivery.LastError = &errMsg
+		return s.record(ctx, delivery)
+	}
+
+	if sendErr := channel.Send(ctx, msg); sendErr == nil {
+		delivery.Status = entity.NotificationDeliverySuccess
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+	} else {
+		errMsg := sendErr.Error()
+		delivery.LastError = &errMsg
+		// retried on the schedule of the webhook deliveries
+		delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+		if delivery.Attempts >= notificationMaxAttempts {
+			delivery.Status = entity.NotificationDeliveryFailed
+		}
+	}
+
+	return s.record(ctx, delivery)
+}
+
+// record stores the outcome of a delivery
+func (s *notificationService) record(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	tx := s.repo.BeginTx(ctx)
+
+	// if error rollback, commit otherwise
+	if err := s.repo.UpdateDelivery(ctx, tx, delivery); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// preference returns the saved preferences of user, users who never saved any get the queue channel only
+func (s *notificationService) preference(ctx context.Context, user string) (*entity.NotificationPreference, error) {
+	ent, err := s.repo.FindPreference(ctx, user)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &entity.NotificationPreference{
+			User:         user,
+			QueueEnabled: true,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ent, nil
+}
+
+func (s *notificationService) findTodoItem(ctx context.Context, activityUuid string, todoItemUuid string) (*entity.TodoItem, error) {
+	activity, err := s.repoActivity.FindByUuid(ctx, activityUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	todoItem, err := s.repoTodoItem.FindByUuid(ctx, todoItemUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if todoItem.ActivityID != activity.ID {
+		return nil, apperror.NotFound(apperror.CodeTodoItemNotFound, "todo item not found")
+	}
+
+	return todoItem, nil
+}
+
+// notificationRecipients maps the channels enabled in preference to the address notifications are sent to
+func notificationRecipients(preference *entity.NotificationPreference) map[string]string {
+	recipients := map[string]string{}
+	if preference.EmailEnabled {
+		recipients[entity.NotificationChannelEmail] = preference.Email
+	}
+	if preference.WebhookEnabled {
+		recipients[entity.NotificationChannelWebhook] = preference.WebhookUrl
+	}
+	if preference.QueueEnabled {
+		recipients[entity.NotificationChannelQueue] = ""
+	}
+
+	return recipients
+}
+
+// notificationMessage sends delivery to the recipient it was queued for, signed with the current webhook secret
+func notificationMessage(preference *entity.NotificationPreference, delivery *entity.NotificationDelivery) (notify.Message, error) {
+	reminder := dto.ReminderNotification{}
+	if err := json.Unmarshal([]byte(delivery.Payload), &reminder); err != nil {
+		return notify.Message{}, err
+	}
+	if reminder.TodoItem == nil {
+		return notify.Message{}, errors.New("no todo item")
+	}
+
+	recipient := notify.Recipient{User: delivery.User}
+	switch delivery.Channel {
+	case entity.NotificationChannelEmail:
+		recipient.Email = delivery.Recipient
+	case entity.NotificationChannelWebhook:
+		recipient.WebhookUrl = delivery.Recipient
+		recipient.WebhookSecret = preference.WebhookSecret
+	}
+
+	name := reminder.TodoItem.Name
+	dueAt := reminder.DueAt.Format("Mon, 02 Jan 2006 15:04 MST")
+	text := fmt.Sprintf("%s is due %s.\n", name, dueAt)
+	if reminder.TodoItem.Description != "" {
+		text += "\n" + reminder.TodoItem.Description + "\n"
+	}
+
+	return notify.Message{
+		Id:        delivery.Uuid,
+		Event:     delivery.Event,
+		Recipient: recipient,
+		Subject:   fmt.Sprintf("Reminder: %s is due %s", name, dueAt),
+		Text:      text,
+		Body:      []byte(delivery.Payload),
+	}, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/Adhiana46/go-restapi-template/pkg/notify"
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
+)
+
+type fakeTodoReminderRepository struct {
+	repository.TodoReminderRepository
+
+	reminders []*entity.TodoReminder
+}
+
+func (r *fakeTodoReminderRepository) BeginTx(ctx context.Context) *sqlx.Tx {
+	return beginTestTx(ctx)
+}
+
+func (r *fakeTodoReminderRepository) FetchAllByTodoItemTx(ctx context.Context, tx *sqlx.Tx, todoItemId int) ([]*entity.TodoReminder, error) {
+	reminders := []*entity.TodoReminder{}
+	for _, reminder := range r.reminders {
+		if reminder.TodoItemID == todoItemId {
+			reminders = append(reminders, reminder)
+		}
+	}
+
+	return reminders, nil
+}
+
+func (r *fakeTodoReminderRepository) FetchDueTx(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]*entity.TodoReminder, error) {
+	return r.reminders, nil
+}
+
+func (r *fakeTodoReminderRepository) Store(ctx context.Context, tx *sqlx.Tx, e *entity.TodoReminder) (*entity.TodoReminder, error) {
+	e.ID = len(r.reminders) + 1
+	r.reminders = append(r.reminders, e)
+	return e, nil
+}
+
+func (r *fakeTodoReminderRepository) Update(ctx context.Context, tx *sqlx.Tx, e *entity.TodoReminder) (*entity.TodoReminder, error) {
+	return e, nil
+}
+
+// fakeNotificationRepository keeps deliveries in memory, users have no saved preferences
+type fakeNotificationRepository struct {
+	repository.NotificationRepository
+
+	deliveries []*entity.NotificationDelivery
+	updated    []*entity.NotificationDelivery
+}
+
+func (r *fakeNotificationRepository) BeginTx(ctx context.Context) *sqlx.Tx {
+	return beginTestTx(ctx)
+}
+
+func (r *fakeNotificationRepository) FindPreference(ctx context.Context, user string) (*entity.NotificationPreference, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeNotificationRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, claimUntil time.Time, limit int) ([]*entity.NotificationDelivery, error) {
+	claimed := []*entity.NotificationDelivery{}
+	for _, delivery := range r.deliveries {
+		if len(claimed) < limit && delivery.Status == entity.NotificationDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			delivery.NextAttemptAt = claimUntil
+			claimed = append(claimed, delivery)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (r *fakeNotificationRepository) StoreDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.NotificationDelivery) error {
+	e.ID = len(r.deliveries) + 1
+	r.deliveries = append(r.deliveries, e)
+	return nil
+}
+
+func (r *fakeNotificationRepository) UpdateDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.NotificationDelivery) error {
+	r.updated = append(r.updated, e)
+	return nil
+}
+
+type fakeChannel struct {
+	sent []notify.Message
+	err  error
+}
+
+func (c *fakeChannel) Name() string {
+	return entity.NotificationChannelQueue
+}
+
+func (c *fakeChannel) Send(ctx context.Context, msg notify.Message) error {
+	c.sent = append(c.sent, msg)
+	return c.err
+}
+
+func TestFireDueQueuesDeliveryOncePerDueDate(t *testing.T) {
+	dueAt := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+	repoTodoItem := &fakeTodoItemRepository{items: map[string]*entity.TodoItem{
+		"item": {ID: 1, Uuid: "item", ActivityID: 1, Name: "stand-up", DueAt: &dueAt},
+	}}
+	repoReminder := &fakeTodoReminderRepository{reminders: []*entity.TodoReminder{
+		{ID: 1, Uuid: "reminder", TodoItemID: 1, User: "alice", OffsetMinutes: 10},
+	}}
+	repo := &fakeNotificationRepository{}
+	svc := NewNotificationService(validator.New(), testTimeouts, repo, repoReminder, repoTodoItem, &fakeActivityGroupRepository{}, nil)
+
+	fired, err := svc.FireDue(context.Background())
+	if err != nil || fired != 1 {
+		t.Fatalf("fired %d, %v, want 1", fired, err)
+	}
+
+	// without saved preferences a user is notified on the queue channel only
+	if len(repo.deliveries) != 1 || repo.deliveries[0].Channel != entity.NotificationChannelQueue {
+		t.Fatalf("queued %+v, want one queue delivery", repo.deliveries)
+	}
+
+	var payload dto.ReminderNotification
+	if err := json.Unmarshal([]byte(repo.deliveries[0].Payload), &payload); err != nil {
+		t.Fatalf("decoding payload: %s", err)
+	}
+	if payload.ReminderUuid != "reminder" || payload.ActivityUuid != "activity" || !payload.DueAt.Equal(dueAt) {
+		t.Fatalf("payload %+v", payload)
+	}
+
+	reminder := repoReminder.reminders[0]
+	if reminder.FiredDueAt == nil || !reminder.FiredDueAt.Equal(dueAt) || reminder.FiredAt == nil {
+		t.Fatalf("reminder fired for %v at %v, want it marked fired for the due date", reminder.FiredDueAt, reminder.FiredAt)
+	}
+}
+
+func TestDeliverPendingFailsMalformedPayload(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	repo := &fakeNotificationRepository{deliveries: []*entity.NotificationDelivery{
+		{ID: 1, Uuid: "malformed", User: "alice", Channel: entity.NotificationChannelQueue, Payload: "{", Status: entity.NotificationDeliveryPending, NextAttemptAt: past},
+		{ID: 2, Uuid: "empty", User: "alice", Channel: entity.NotificationChannelQueue, Payload: `{}`, Status: entity.NotificationDeliveryPending, NextAttemptAt: past},
+		{ID: 3, Uuid: "reminder", User: "alice", Channel: entity.NotificationChannelQueue, Payload: `{"todo_item":{"name":"stand-up"}}`, Status: entity.NotificationDeliveryPending, NextAttemptAt: past},
+	}}
+	channel := &fakeChannel{}
+	svc := NewNotificationService(validator.New(), testTimeouts, repo, nil, nil, nil, []notify.Channel{channel})
+
+	delivered, err := svc.DeliverPending(context.Background())
+	if err != nil || delivered != 3 {
+		t.Fatalf("delivered %d, %v, want 3", delivered, err)
+	}
+
+	if len(channel.sent) != 1 || channel.sent[0].Id != "reminder" {
+		t.Fatalf("sent %+v, want only the well formed delivery", channel.sent)
+	}
+	for _, malformed := range repo.deliveries[:2] {
+		if malformed.Status != entity.NotificationDeliveryFailed || malformed.LastError == nil {
+			t.Fatalf("malformed delivery %s is %s, want failed with its error", malformed.Uuid, malformed.Status)
+		}
+	}
+	if repo.deliveries[2].Status != entity.NotificationDeliverySuccess {
+		t.Fatalf("delivery behind the malformed ones is %s, want success", repo.deliveries[2].Status)
+	}
+	if len(repo.updated) != 3 {
+		t.Fatalf("recorded %d outcomes, want 3", len(repo.updated))
+	}
+}
+
+func TestDeliverPendingRetriesFailedSend(t *testing.T) {
+	repo := &fakeNotificationRepository{deliveries: []*entity.NotificationDelivery{
+		{ID: 1, Uuid: "reminder", User: "alice", Channel: entity.NotificationChannelQueue, Payload: `{"todo_item":{"name":"stand-up"}}`, Status: entity.NotificationDeliveryPending, NextAttemptAt: time.Now().Add(-time.Minute)},
+		{ID: 2, Uuid: "email", User: "alice", Channel: entity.NotificationChannelEmail, Payload: `{}`, Status: entity.NotificationDeliveryPending, NextAttemptAt: time.Now().Add(-time.Minute)},
+	}}
+	svc := NewNotificationService(validator.New(), testTimeouts, repo, nil, nil, nil, []notify.Channel{&fakeChannel{err: errors.New("broker unreachable")}})
+
+	if _, err := svc.DeliverPending(context.Background()); err != nil {
+		t.Fatalf("delivering: %s", err)
+	}
+
+	retried := repo.deliveries[0]
+	if retried.Status != entity.NotificationDeliveryPending || retried.Attempts != 1 || !retried.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("failed send is %s after %d attempts, next at %s, want it pending for a later retry", retried.Status, retried.Attempts, retried.NextAttemptAt)
+	}
+
+	// no email channel is configured
+	if unconfigured := repo.deliveries[1]; unconfigured.Status != entity.NotificationDeliveryFailed {
+		t.Fatalf("delivery on an unconfigured channel is %s, want failed", unconfigured.Status)
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"math"
+
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+)
+
+func newPagination(page int, limit int, total int, size int) *responsePkg.Pagination {
+	return &responsePkg.Pagination{
+		CurrentPage: page,
+		Total:       total,
+		Size:        size,
+		TotalPages:  int(math.Ceil(float64(total) / float64(limit))),
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// ScheduledActionBatchSize is the most actions DispatchDue dispatches, one after the other
+const ScheduledActionBatchSize = 20
+
+// ScheduledActionDispatch hands a due action back to the worker of its queue
+type ScheduledActionDispatch func(ctx context.Context, e *entity.ScheduledAction) error
+
+type ScheduledActionService interface {
+	Schedule(ctx context.Context, req dto.ScheduledActionCreateRequest) (*entity.ScheduledAction, error)
+	FindByUuid(ctx context.Context, req dto.ScheduledActionUuidRequest) (*entity.ScheduledAction, error)
+	FetchAll(ctx context.Context, req dto.ScheduledActionFetchRequest) ([]*entity.ScheduledAction, *responsePkg.Pagination, error)
+	Cancel(ctx context.Context, req dto.ScheduledActionUuidRequest) (*entity.ScheduledAction, error)
+
+	DispatchDue(ctx context.Context, dispatch ScheduledActionDispatch) (int, error)
+}
+
+type scheduledActionService struct {
+	validate *validator.Validate
+	timeouts Timeouts
+	repo     repository.ScheduledActionRepository
+}
+
+func NewScheduledActionService(validate *validator.Validate, timeouts Timeouts, repo repository.ScheduledActionRepository) ScheduledActionService {
+	return &scheduledActionService{
+		validate: validate,
+		timeouts: timeouts,
+		repo:     repo,
+	}
+}
+
+func (s *scheduledActionService) Schedule(ctx context.Context, req dto.ScheduledActionCreateRequest) (*entity.ScheduledAction, error) {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	data, err := json.Marshal(req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	ent := &entity.ScheduledAction{
+		Uuid:      uuid.NewString(),
+		Queue:     req.Queue,
+		Action:    req.Action,
+		Version:   req.Version,
+		Data:      string(data),
+		Locale:    req.Locale,
+		Status:    entity.ScheduledActionPending,
+		ExecuteAt: req.ExecuteAt,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if req.ActivityUuid != "" {
+		ent.ActivityUuid = &req.ActivityUuid
+	}
+
+	// begin transaction
+	tx := s.repo.BeginTx(ctx)
+	insertedRow, err := s.repo.Store(ctx, tx, ent)
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	} else {
+		tx.Commit()
+	}
+
+	return insertedRow, nil
+}
+
+func (s *scheduledActionService) FindByUuid(ctx context.Context, req dto.ScheduledActionUuidRequest) (*entity.ScheduledAction, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	return s.repo.FindByUuid(ctx, req.Uuid)
+}
+
+func (s *scheduledActionService) FetchAll(ctx context.Context, req dto.ScheduledActionFetchRequest) ([]*entity.ScheduledAction, *responsePkg.Pagination, error) {
+	countCtx, ctx, cancel := s.timeouts.page(ctx)
+	defer cancel()
+
+	// Set Default Value
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, nil, apperror.Validation(err)
+	}
+
+	totalRows, err := s.repo.CountAll(countCtx, req.Queue, req.Status, req.ActivityUuid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actionList, err := s.repo.FetchAll(ctx, req.Page, req.Limit, req.Queue, req.Status, req.ActivityUuid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create Pagination
+	pagination := newPagination(req.Page, req.Limit, totalRows, len(actionList))
+
+	return actionList, pagination, nil
+}
+
+// Cancel keeps a pending action from being dispatched, actions already dispatched or cancelled conflict
+func (s *scheduledActionService) Cancel(ctx context.Context, req dto.ScheduledActionUuidRequest) (*entity.ScheduledAction, error) {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	// begin transaction, the row stays locked so the dispatcher can't pick it up meanwhile
+	tx := s.repo.BeginTx(ctx)
+
+	ent, err := s.repo.FindByUuidTx(ctx, tx, req.Uuid)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if ent.Status != entity.ScheduledActionPending {
+		tx.Rollback()
+		return nil, apperror.Conflict(apperror.CodeScheduledActionNotPending, "scheduled action is "+ent.Status)
+	}
+
+	now := time.Now()
+	ent.Status = entity.ScheduledActionCancelled
+	ent.CancelledAt = &now
+	ent.UpdatedAt = now
+
+	updatedRow, err := s.repo.Update(ctx, tx, ent)
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	} else {
+		tx.Commit()
+	}
+
+	return updatedRow, nil
+}
+
+// DispatchDue hands one batch of due actions to dispatch and returns how many were dispatched.
+// Only the instance holding the advisory lock dispatches, the others return 0 until it is released.
+func (s *scheduledActionService) DispatchDue(ctx context.Context, dispatch ScheduledActionDispatch) (int, error) {
+	tx := s.repo.BeginTx(ctx)
+
+	leader, err := s.repo.LockLeaderTx(ctx, tx)
+	if err != nil || !leader {
+		tx.Rollback()
+		return 0, err
+	}
+
+	actions, err := s.repo.FetchDueTx(ctx, tx, time.Now(), ScheduledActionBatchSize)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	dispatched := 0
+	for _, action := range actions {
+		if err := dispatch(ctx, action); err != nil {
+			// left pending, it is retried on the next poll
+			logging.FromContext(ctx).WithFields(log.Fields{
+				"scheduled_action_uuid": action.Uuid,
+				"worker":                action.Queue,
+				"action":                action.Action,
+			}).Errorf("[scheduled action] dispatching %s: %s", action.Uuid, err)
+			continue
+		}
+
+		now := time.Now()
+		action.Status = entity.ScheduledActionDispatched
+		action.DispatchedAt = &now
+		action.UpdatedAt = now
+		if _, err := s.repo.Update(ctx, tx, action); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		dispatched++
+	}
+
+	return dispatched, tx.Commit()
+}
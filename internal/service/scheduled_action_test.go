@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
+)
+
+type fakeScheduledActionRepository struct {
+	repository.ScheduledActionRepository
+
+	leader  bool
+	actions []*entity.ScheduledAction
+}
+
+func (r *fakeScheduledActionRepository) BeginTx(ctx context.Context) *sqlx.Tx {
+	return beginTestTx(ctx)
+}
+
+func (r *fakeScheduledActionRepository) LockLeaderTx(ctx context.Context, tx *sqlx.Tx) (bool, error) {
+	return r.leader, nil
+}
+
+func (r *fakeScheduledActionRepository) FetchDueTx(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]*entity.ScheduledAction, error) {
+	due := []*entity.ScheduledAction{}
+	for _, action := range r.actions {
+		if len(due) < limit && action.Status == entity.ScheduledActionPending && !action.ExecuteAt.After(now) {
+			due = append(due, action)
+		}
+	}
+
+	return due, nil
+}
+
+func (r *fakeScheduledActionRepository) Update(ctx context.Context, tx *sqlx.Tx, e *entity.ScheduledAction) (*entity.ScheduledAction, error) {
+	return e, nil
+}
+
+func TestDispatchDueOnlyOnLeader(t *testing.T) {
+	repo := &fakeScheduledActionRepository{
+		actions: []*entity.ScheduledAction{{Uuid: "due", Status: entity.ScheduledActionPending, ExecuteAt: time.Now().Add(-time.Minute)}},
+	}
+	svc := NewScheduledActionService(validator.New(), Timeouts{}, repo)
+
+	dispatched, err := svc.DispatchDue(context.Background(), func(ctx context.Context, e *entity.ScheduledAction) error {
+		t.Fatalf("dispatched %s without holding the lock", e.Uuid)
+		return nil
+	})
+	if err != nil || dispatched != 0 {
+		t.Fatalf("dispatched %d, %v", dispatched, err)
+	}
+}
+
+func TestDispatchDueLeavesFailedActionsPending(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	repo := &fakeScheduledActionRepository{
+		leader: true,
+		actions: []*entity.ScheduledAction{
+			{Uuid: "failing", Status: entity.ScheduledActionPending, ExecuteAt: past},
+			{Uuid: "due", Status: entity.ScheduledActionPending, ExecuteAt: past},
+			{Uuid: "later", Status: entity.ScheduledActionPending, ExecuteAt: time.Now().Add(time.Hour)},
+		},
+	}
+	svc := NewScheduledActionService(validator.New(), Timeouts{}, repo)
+
+	sent := []string{}
+	dispatched, err := svc.DispatchDue(context.Background(), func(ctx context.Context, e *entity.ScheduledAction) error {
+		sent = append(sent, e.Uuid)
+		if e.Uuid == "failing" {
+			return errors.New("broker unreachable")
+		}
+		return nil
+	})
+	if err != nil || dispatched != 1 {
+		t.Fatalf("dispatched %d, %v, want 1", dispatched, err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("dispatched %v, want the two due actions", sent)
+	}
+
+	for _, action := range repo.actions {
+		want := entity.ScheduledActionPending
+		if action.Uuid == "due" {
+			want = entity.ScheduledActionDispatched
+		}
+		if action.Status != want {
+			t.Errorf("%s is %s, want %s", action.Uuid, action.Status, want)
+		}
+	}
+	if repo.actions[1].DispatchedAt == nil {
+		t.Error("dispatched action has no dispatched_at")
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// txDriver only begins, commits and rolls back, fake repositories ignore the transaction they are handed
+type txDriver struct{}
+
+func (txDriver) Open(name string) (driver.Conn, error) {
+	return txConn{}, nil
+}
+
+type txConn struct{}
+
+func (txConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("txDriver runs no statements")
+}
+
+func (txConn) Close() error {
+	return nil
+}
+
+func (txConn) Begin() (driver.Tx, error) {
+	return txConn{}, nil
+}
+
+func (txConn) Commit() error {
+	return nil
+}
+
+func (txConn) Rollback() error {
+	return nil
+}
+
+var testDB = func() *sqlx.DB {
+	sql.Register("service-test-tx", txDriver{})
+	return sqlx.MustOpen("service-test-tx", "")
+}()
+
+func beginTestTx(ctx context.Context) *sqlx.Tx {
+	return testDB.MustBeginTx(ctx, &sql.TxOptions{})
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// Timeouts bounds the database work of a single service call by kind of operation,
+// the database session is the one the transport started for the request
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+	Count time.Duration
+}
+
+func (t Timeouts) read(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, t.Read)
+}
+
+func (t Timeouts) write(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, t.Write)
+}
+
+func (t Timeouts) count(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, t.Count)
+}
+
+// page bounds a paginated listing, the count query gets its own budget derived before ctx is bounded by the read one
+func (t Timeouts) page(ctx context.Context) (countCtx context.Context, readCtx context.Context, cancel context.CancelFunc) {
+	countCtx, cancelCount := t.count(ctx)
+	readCtx, cancelRead := t.read(ctx)
+
+	return countCtx, readCtx, func() {
+		cancelRead()
+		cancelCount()
+	}
+}
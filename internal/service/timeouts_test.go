@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPageBoundsCountAndReadSeparately(t *testing.T) {
+	timeouts := Timeouts{Read: 50 * time.Millisecond, Count: time.Hour}
+
+	start := time.Now()
+	countCtx, readCtx, cancel := timeouts.page(context.Background())
+	defer cancel()
+
+	countDeadline, ok := countCtx.Deadline()
+	if !ok || countDeadline.Sub(start) < 59*time.Minute {
+		t.Fatalf("count deadline %v, want the count budget and not the read one", countDeadline)
+	}
+	readDeadline, ok := readCtx.Deadline()
+	if !ok || readDeadline.Sub(start) > time.Second {
+		t.Fatalf("read deadline %v, want the read budget", readDeadline)
+	}
+
+	// a slow listing doesn't take the count query down with it
+	<-readCtx.Done()
+	if err := countCtx.Err(); err != nil {
+		t.Fatalf("count context %v after the read budget ran out", err)
+	}
+
+	cancel()
+	if countCtx.Err() == nil {
+		t.Fatal("count context alive after cancel")
+	}
+}
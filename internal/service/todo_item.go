@@ -2,47 +2,69 @@ package service
 
 import (
 	"context"
-	"math"
 	"time"
 
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
 	"github.com/Adhiana46/go-restapi-template/internal/dto"
 	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/event"
 	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
 	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
 	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/Adhiana46/go-restapi-template/pkg/rrule"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 )
 
+const todoSeriesBatchSize = 20
+
 type TodoItemService interface {
-	FindByUuid(req dto.TodoItemUuidRequest) (*entity.TodoItem, error)
-	FetchAll(req dto.TodoItemFetchRequest) ([]*entity.TodoItem, *responsePkg.Pagination, error)
-	Create(req dto.TodoItemCreateRequest) (*entity.TodoItem, error)
-	Update(req dto.TodoItemUpdateRequest) (*entity.TodoItem, error)
-	Delete(req dto.TodoItemUuidRequest) error
+	FindByUuid(ctx context.Context, req dto.TodoItemUuidRequest) (*entity.TodoItem, error)
+	FetchAll(ctx context.Context, req dto.TodoItemFetchRequest) ([]*entity.TodoItem, *responsePkg.Pagination, error)
+	CountByActivityIds(ctx context.Context, req dto.TodoItemCountRequest) (map[int]int, error)
+	CountPerActivity(ctx context.Context) (map[string]int, error)
+	Create(ctx context.Context, req dto.TodoItemCreateRequest) (*entity.TodoItem, error)
+	Update(ctx context.Context, req dto.TodoItemUpdateRequest) (*entity.TodoItem, error)
+	Delete(ctx context.Context, req dto.TodoItemUuidRequest) error
+
+	FindSeries(ctx context.Context, req dto.TodoItemUuidRequest) (*entity.TodoSeries, error)
+	UpdateSeries(ctx context.Context, req dto.TodoSeriesUpdateRequest) (*entity.TodoSeries, error)
+	EndSeries(ctx context.Context, req dto.TodoItemUuidRequest) (*entity.TodoSeries, error)
+
+	GenerateOccurrences(ctx context.Context) (int, error)
 }
 
 type todoItemService struct {
 	validate     *validator.Validate
+	timeouts     Timeouts
 	repo         repository.TodoItemRepository
+	repoSeries   repository.TodoSeriesRepository
+	repoReminder repository.TodoReminderRepository
 	repoActivity repository.ActivityGroupRepository
+	dispatcher   event.Dispatcher
 }
 
-func NewTodoItemService(validate *validator.Validate, repo repository.TodoItemRepository, repoActivity repository.ActivityGroupRepository) TodoItemService {
+func NewTodoItemService(validate *validator.Validate, timeouts Timeouts, repo repository.TodoItemRepository, repoSeries repository.TodoSeriesRepository, repoReminder repository.TodoReminderRepository, repoActivity repository.ActivityGroupRepository, dispatcher event.Dispatcher) TodoItemService {
 	return &todoItemService{
 		validate:     validate,
+		timeouts:     timeouts,
 		repo:         repo,
+		repoSeries:   repoSeries,
+		repoReminder: repoReminder,
 		repoActivity: repoActivity,
+		dispatcher:   dispatcher,
 	}
 }
 
-func (s *todoItemService) FindByUuid(req dto.TodoItemUuidRequest) (*entity.TodoItem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *todoItemService) FindByUuid(ctx context.Context, req dto.TodoItemUuidRequest) (*entity.TodoItem, error) {
+	ctx, cancel := s.timeouts.read(ctx)
 	defer cancel()
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
-		return nil, err
+		return nil, apperror.Validation(err)
 	}
 
 	todoItem, err := s.repo.FindByUuid(ctx, req.Uuid)
@@ -59,8 +81,8 @@ func (s *todoItemService) FindByUuid(req dto.TodoItemUuidRequest) (*entity.TodoI
 	return todoItem, nil
 }
 
-func (s *todoItemService) FetchAll(req dto.TodoItemFetchRequest) ([]*entity.TodoItem, *responsePkg.Pagination, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *todoItemService) FetchAll(ctx context.Context, req dto.TodoItemFetchRequest) ([]*entity.TodoItem, *responsePkg.Pagination, error) {
+	countCtx, ctx, cancel := s.timeouts.page(ctx)
 	defer cancel()
 
 	var err error
@@ -78,7 +100,7 @@ func (s *todoItemService) FetchAll(req dto.TodoItemFetchRequest) ([]*entity.Todo
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
-		return nil, nil, err
+		return nil, nil, apperror.Validation(err)
 	}
 
 	activity := &entity.ActivityGroup{}
@@ -89,14 +111,14 @@ func (s *todoItemService) FetchAll(req dto.TodoItemFetchRequest) ([]*entity.Todo
 		}
 	}
 
-	totalRows, err := s.repo.CountAll(ctx, activity.ID, req.Filter)
+	totalRows, err := s.repo.CountAll(countCtx, activity.ID, req.Filter)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	sorts, err := parserPkg.QuerySortToMap(req.SortBy)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, apperror.BadRequest(apperror.CodeInvalidSort, err.Error())
 	}
 
 	todoItemList, err := s.repo.FetchAll(ctx, req.Page, req.Limit, sorts, activity.ID, req.Filter)
@@ -105,25 +127,39 @@ func (s *todoItemService) FetchAll(req dto.TodoItemFetchRequest) ([]*entity.Todo
 	}
 
 	// Create Pagination
-	pagination := responsePkg.Pagination{
-		CurrentPage: req.Page,
-		Total:       totalRows,
-		Size:        len(todoItemList),
-		TotalPages:  int(math.Ceil(float64(totalRows) / float64(req.Limit))),
+	pagination := newPagination(req.Page, req.Limit, totalRows, len(todoItemList))
+
+	return todoItemList, pagination, nil
+}
+
+func (s *todoItemService) CountByActivityIds(ctx context.Context, req dto.TodoItemCountRequest) (map[int]int, error) {
+	ctx, cancel := s.timeouts.count(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
 	}
 
-	return todoItemList, &pagination, nil
+	return s.repo.CountByActivityIds(ctx, req.ActivityIds)
 }
 
-func (s *todoItemService) Create(req dto.TodoItemCreateRequest) (*entity.TodoItem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *todoItemService) CountPerActivity(ctx context.Context) (map[string]int, error) {
+	ctx, cancel := s.timeouts.count(ctx)
+	defer cancel()
+
+	return s.repo.CountPerActivity(ctx)
+}
+
+func (s *todoItemService) Create(ctx context.Context, req dto.TodoItemCreateRequest) (*entity.TodoItem, error) {
+	ctx, cancel := s.timeouts.write(ctx)
 	defer cancel()
 
 	var err error
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
-		return nil, err
+		return nil, apperror.Validation(err)
 	}
 
 	activity := &entity.ActivityGroup{}
@@ -139,13 +175,22 @@ func (s *todoItemService) Create(req dto.TodoItemCreateRequest) (*entity.TodoIte
 		ActivityID:  activity.ID,
 		Name:        req.Name,
 		Description: req.Description,
+		DueAt:       req.DueAt,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
 	// begin transaction
 	tx := s.repo.BeginTx(ctx)
-	insertedRow, err := s.repo.Store(ctx, tx, ent)
+	var insertedRow *entity.TodoItem
+	if req.Recurrence != "" {
+		insertedRow, err = s.storeSeries(ctx, tx, ent, req.Recurrence)
+	} else {
+		insertedRow, err = s.repo.Store(ctx, tx, ent)
+	}
+	if err == nil {
+		err = s.dispatcher.Dispatch(ctx, tx, event.New(event.TodoItemCreated, activity.Uuid, dto.TodoItemToResponse(insertedRow)))
+	}
 
 	// if error rollback, commit otherwise
 	if err != nil {
@@ -158,39 +203,60 @@ func (s *todoItemService) Create(req dto.TodoItemCreateRequest) (*entity.TodoIte
 	return insertedRow, nil
 }
 
-func (s *todoItemService) Update(req dto.TodoItemUpdateRequest) (*entity.TodoItem, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+// Update edits a single todo item, of a series it only edits that occurrence, see UpdateSeries
+func (s *todoItemService) Update(ctx context.Context, req dto.TodoItemUpdateRequest) (*entity.TodoItem, error) {
+	ctx, cancel := s.timeouts.write(ctx)
 	defer cancel()
 
 	var err error
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
-		return nil, err
+		return nil, apperror.Validation(err)
 	}
 
-	ent, err := s.repo.FindByUuid(ctx, req.Uuid)
+	// begin transaction, the item is read on the primary and locked until the update commits,
+	// so the fields the request omits are merged onto its latest state
+	tx := s.repo.BeginTx(ctx)
+	ent, err := s.repo.FindByUuidTx(ctx, tx, req.Uuid)
 	if err != nil {
-		return ent, err
+		tx.Rollback()
+		return nil, err
 	}
 
 	activity := &entity.ActivityGroup{}
 	if req.ActivityUuid != "" {
 		activity, err = s.repoActivity.FindByUuid(ctx, req.ActivityUuid)
 		if err != nil {
+			tx.Rollback()
 			return nil, err
 		}
 	}
 
 	// Update values
+	wasCompleted := ent.CompletedAt != nil
 	ent.ActivityID = activity.ID
 	ent.Name = req.Name
 	ent.Description = req.Description
+	if req.ClearDueAt {
+		ent.DueAt = nil
+	} else if req.DueAt != nil {
+		ent.DueAt = req.DueAt
+	}
 	ent.UpdatedAt = time.Now()
+	if req.IsCompleted != nil && *req.IsCompleted && !wasCompleted {
+		ent.CompletedAt = &ent.UpdatedAt
+	} else if req.IsCompleted != nil && !*req.IsCompleted {
+		ent.CompletedAt = nil
+	}
 
-	// begin transaction
-	tx := s.repo.BeginTx(ctx)
 	updatedRow, err := s.repo.Update(ctx, tx, ent)
+	if err == nil {
+		err = s.dispatcher.Dispatch(ctx, tx, event.New(event.TodoItemUpdated, activity.Uuid, dto.TodoItemToResponse(updatedRow)))
+	}
+	if err == nil && updatedRow.CompletedAt != nil && !wasCompleted {
+		err = s.dispatcher.Dispatch(ctx, tx, event.New(event.TodoItemCompleted, activity.Uuid, dto.TodoItemToResponse(updatedRow)))
+	}
 
 	// if error rollback, commit otherwise
 	if err != nil {
@@ -203,23 +269,33 @@ func (s *todoItemService) Update(req dto.TodoItemUpdateRequest) (*entity.TodoIte
 	return updatedRow, nil
 }
 
-func (s *todoItemService) Delete(req dto.TodoItemUuidRequest) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+func (s *todoItemService) Delete(ctx context.Context, req dto.TodoItemUuidRequest) error {
+	ctx, cancel := s.timeouts.write(ctx)
 	defer cancel()
 
 	// Validate
 	if err := s.validate.Struct(req); err != nil {
+		return apperror.Validation(err)
+	}
+
+	// begin transaction, the item is read on the primary and locked until it is deleted
+	tx := s.repo.BeginTx(ctx)
+	ent, err := s.repo.FindByUuidTx(ctx, tx, req.Uuid)
+	if err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	ent, err := s.repo.FindByUuid(ctx, req.Uuid)
+	activity, err := s.repoActivity.FindById(ctx, ent.ActivityID)
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	// begin transaction
-	tx := s.repo.BeginTx(ctx)
-	err = s.repo.Delete(ctx, tx, ent)
+	err = s.dispatcher.Dispatch(ctx, tx, event.New(event.TodoItemDeleted, activity.Uuid, dto.TodoItemToResponse(ent)))
+	if err == nil {
+		err = s.repo.Delete(ctx, tx, ent)
+	}
 
 	// if error rollback, commit otherwise
 	if err != nil {
@@ -231,3 +307,316 @@ func (s *todoItemService) Delete(req dto.TodoItemUuidRequest) error {
 
 	return nil
 }
+
+// FindSeries finds the series the todo item is an occurrence of
+func (s *todoItemService) FindSeries(ctx context.Context, req dto.TodoItemUuidRequest) (*entity.TodoSeries, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	todoItem, err := s.repo.FindByUuid(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if todoItem.SeriesID == nil {
+		return nil, apperror.NotFound(apperror.CodeTodoSeriesNotFound, "todo item doesn't recur")
+	}
+
+	return s.repoSeries.FindById(ctx, *todoItem.SeriesID)
+}
+
+// UpdateSeries edits the series of the todo item, the open occurrence and the next ones follow it.
+// A new recurrence applies from the due date of the current occurrence on, its COUNT included.
+func (s *todoItemService) UpdateSeries(ctx context.Context, req dto.TodoSeriesUpdateRequest) (*entity.TodoSeries, error) {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	rule, err := rrule.Parse(req.Recurrence)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.CodeValidationFailed, err.Error())
+	}
+
+	todoItem, err := s.repo.FindByUuid(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if todoItem.SeriesID == nil {
+		return nil, apperror.NotFound(apperror.CodeTodoSeriesNotFound, "todo item doesn't recur")
+	}
+
+	// begin transaction, the series stays locked so its next occurrence waits for the edit
+	tx := s.repoSeries.BeginTx(ctx)
+
+	series, err := s.repoSeries.FindByIdTx(ctx, tx, *todoItem.SeriesID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if series.EndedAt != nil {
+		tx.Rollback()
+		return nil, apperror.Conflict(apperror.CodeTodoSeriesEnded, "todo series already ended")
+	}
+
+	// Update values
+	series.Name = req.Name
+	series.Description = req.Description
+	series.UpdatedAt = time.Now()
+	if rule.String() != series.Rrule {
+		series.Rrule = rule.String()
+		series.StartAt = series.CurrentDueAt
+		series.Occurrences = 1
+	}
+
+	updatedRow, err := s.repoSeries.Update(ctx, tx, series)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// completed occurrences keep the name they were done under
+	var current *entity.TodoItem
+	if series.CurrentItemID != nil {
+		current, err = s.repo.FindByIdTx(ctx, tx, *series.CurrentItemID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if current.CompletedAt == nil {
+			current.Name = series.Name
+			current.Description = series.Description
+			current.UpdatedAt = series.UpdatedAt
+			if _, err := s.repo.Update(ctx, tx, current); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			if err := s.dispatchOccurrence(ctx, tx, event.TodoItemUpdated, current); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return updatedRow, nil
+}
+
+// EndSeries stops the series of the todo item from creating occurrences, the existing ones are kept
+func (s *todoItemService) EndSeries(ctx context.Context, req dto.TodoItemUuidRequest) (*entity.TodoSeries, error) {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	todoItem, err := s.repo.FindByUuid(ctx, req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if todoItem.SeriesID == nil {
+		return nil, apperror.NotFound(apperror.CodeTodoSeriesNotFound, "todo item doesn't recur")
+	}
+
+	// begin transaction
+	tx := s.repoSeries.BeginTx(ctx)
+
+	series, err := s.repoSeries.FindByIdTx(ctx, tx, *todoItem.SeriesID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if series.EndedAt != nil {
+		tx.Rollback()
+		return nil, apperror.Conflict(apperror.CodeTodoSeriesEnded, "todo series already ended")
+	}
+
+	now := time.Now()
+	series.EndedAt = &now
+	series.UpdatedAt = now
+
+	updatedRow, err := s.repoSeries.Update(ctx, tx, series)
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	} else {
+		tx.Commit()
+	}
+
+	return updatedRow, nil
+}
+
+// GenerateOccurrences moves one batch of series whose current occurrence is completed, due or deleted to their
+// next occurrence and returns how many series were moved
+func (s *todoItemService) GenerateOccurrences(ctx context.Context) (int, error) {
+	tx := s.repoSeries.BeginTx(ctx)
+	now := time.Now()
+
+	seriesList, err := s.repoSeries.FetchDueTx(ctx, tx, now, todoSeriesBatchSize)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	for _, series := range seriesList {
+		todoItem, err := s.nextOccurrence(ctx, tx, series, now)
+		if err == nil && todoItem != nil {
+			err = s.dispatchOccurrence(ctx, tx, event.TodoItemCreated, todoItem)
+		}
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(seriesList), nil
+}
+
+// storeSeries stores ent as the first occurrence of a new series recurring from its due date
+func (s *todoItemService) storeSeries(ctx context.Context, tx *sqlx.Tx, ent *entity.TodoItem, recurrence string) (*entity.TodoItem, error) {
+	rule, err := rrule.Parse(recurrence)
+	if err != nil {
+		return nil, apperror.BadRequest(apperror.CodeValidationFailed, err.Error())
+	}
+
+	series, err := s.repoSeries.Store(ctx, tx, &entity.TodoSeries{
+		Uuid:         uuid.NewString(),
+		ActivityID:   ent.ActivityID,
+		Name:         ent.Name,
+		Description:  ent.Description,
+		Rrule:        rule.String(),
+		StartAt:      *ent.DueAt,
+		Occurrences:  1,
+		CurrentDueAt: *ent.DueAt,
+		CreatedAt:    ent.CreatedAt,
+		UpdatedAt:    ent.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ent.SeriesID = &series.ID
+	insertedRow, err := s.repo.Store(ctx, tx, ent)
+	if err != nil {
+		return nil, err
+	}
+
+	series.CurrentItemID = &insertedRow.ID
+	if _, err := s.repoSeries.Update(ctx, tx, series); err != nil {
+		return nil, err
+	}
+
+	return insertedRow, nil
+}
+
+// nextOccurrence stores the occurrence of series following the current one with its reminders, or ends the
+// series once its rule does. Occurrences missed while the queue binary was down aren't caught up on, the next one
+// is after now.
+func (s *todoItemService) nextOccurrence(ctx context.Context, tx *sqlx.Tx, series *entity.TodoSeries, now time.Time) (*entity.TodoItem, error) {
+	series.UpdatedAt = now
+
+	dueAt, ok := time.Time{}, false
+	rule, err := rrule.Parse(series.Rrule)
+	if err != nil {
+		logging.FromContext(ctx).WithField("series_uuid", series.Uuid).Warnf("[todo series] ending series with a malformed rule: %s", err)
+	} else if !rule.Exhausted(series.Occurrences) {
+		dueAt, ok = rule.Next(series.StartAt, series.CurrentDueAt)
+		if ok && !dueAt.After(now) {
+			dueAt, ok = rule.Next(series.StartAt, now)
+		}
+	}
+
+	if !ok {
+		series.EndedAt = &now
+		_, err := s.repoSeries.Update(ctx, tx, series)
+		return nil, err
+	}
+
+	todoItem, err := s.repo.Store(ctx, tx, &entity.TodoItem{
+		Uuid:        uuid.NewString(),
+		ActivityID:  series.ActivityID,
+		Name:        series.Name,
+		Description: series.Description,
+		DueAt:       &dueAt,
+		SeriesID:    &series.ID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// reminders of a deleted occurrence went with it
+	if series.CurrentItemID != nil {
+		if err := s.carryReminders(ctx, tx, *series.CurrentItemID, todoItem.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	series.Occurrences++
+	series.CurrentDueAt = dueAt
+	series.CurrentItemID = &todoItem.ID
+	if _, err := s.repoSeries.Update(ctx, tx, series); err != nil {
+		return nil, err
+	}
+
+	return todoItem, nil
+}
+
+// carryReminders copies the reminders of the todo item from to the todo item to, armed for its due date
+func (s *todoItemService) carryReminders(ctx context.Context, tx *sqlx.Tx, from int, to int, now time.Time) error {
+	reminders, err := s.repoReminder.FetchAllByTodoItemTx(ctx, tx, from)
+	if err != nil {
+		return err
+	}
+
+	for _, reminder := range reminders {
+		_, err := s.repoReminder.Store(ctx, tx, &entity.TodoReminder{
+			Uuid:          uuid.NewString(),
+			TodoItemID:    to,
+			User:          reminder.User,
+			OffsetMinutes: reminder.OffsetMinutes,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dispatchOccurrence dispatches an event about an occurrence changed by its series in tx
+func (s *todoItemService) dispatchOccurrence(ctx context.Context, tx *sqlx.Tx, name string, todoItem *entity.TodoItem) error {
+	activity, err := s.repoActivity.FindById(ctx, todoItem.ActivityID)
+	if err != nil {
+		return err
+	}
+
+	return s.dispatcher.Dispatch(ctx, tx, event.New(name, activity.Uuid, dto.TodoItemToResponse(todoItem)))
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/event"
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
+)
+
+var testTimeouts = Timeouts{Read: time.Second, Write: time.Second, Count: time.Second}
+
+type fakeTodoItemRepository struct {
+	repository.TodoItemRepository
+
+	items map[string]*entity.TodoItem
+}
+
+func (r *fakeTodoItemRepository) BeginTx(ctx context.Context) *sqlx.Tx {
+	return beginTestTx(ctx)
+}
+
+// FindByUuidTx is the only lookup by uuid, updates must merge onto the row locked on the primary
+// and not onto a possibly stale replica read
+func (r *fakeTodoItemRepository) FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.TodoItem, error) {
+	item := *r.items[uuid]
+	return &item, nil
+}
+
+func (r *fakeTodoItemRepository) FindByIdTx(ctx context.Context, tx *sqlx.Tx, id int) (*entity.TodoItem, error) {
+	for _, item := range r.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeTodoItemRepository) Store(ctx context.Context, tx *sqlx.Tx, e *entity.TodoItem) (*entity.TodoItem, error) {
+	e.ID = len(r.items) + 1
+	r.items[e.Uuid] = e
+	return e, nil
+}
+
+func (r *fakeTodoItemRepository) Update(ctx context.Context, tx *sqlx.Tx, e *entity.TodoItem) (*entity.TodoItem, error) {
+	r.items[e.Uuid] = e
+	return e, nil
+}
+
+type fakeActivityGroupRepository struct {
+	repository.ActivityGroupRepository
+}
+
+func (r *fakeActivityGroupRepository) FindByUuid(ctx context.Context, uuid string) (*entity.ActivityGroup, error) {
+	return &entity.ActivityGroup{ID: 1, Uuid: uuid}, nil
+}
+
+func (r *fakeActivityGroupRepository) FindById(ctx context.Context, id int) (*entity.ActivityGroup, error) {
+	return &entity.ActivityGroup{ID: id, Uuid: "activity"}, nil
+}
+
+type fakeTodoSeriesRepository struct {
+	repository.TodoSeriesRepository
+
+	series []*entity.TodoSeries
+}
+
+func (r *fakeTodoSeriesRepository) BeginTx(ctx context.Context) *sqlx.Tx {
+	return beginTestTx(ctx)
+}
+
+func (r *fakeTodoSeriesRepository) FetchDueTx(ctx context.Context, tx *sqlx.Tx, now time.Time, limit int) ([]*entity.TodoSeries, error) {
+	return r.series, nil
+}
+
+func (r *fakeTodoSeriesRepository) Update(ctx context.Context, tx *sqlx.Tx, e *entity.TodoSeries) (*entity.TodoSeries, error) {
+	return e, nil
+}
+
+func TestUpdateKeepsOmittedFields(t *testing.T) {
+	dueAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	repo := &fakeTodoItemRepository{items: map[string]*entity.TodoItem{
+		"item": {Uuid: "item", ActivityID: 1, Name: "stand-up", DueAt: &dueAt, CompletedAt: &completedAt},
+	}}
+	svc := NewTodoItemService(validator.New(), testTimeouts, repo, nil, nil, &fakeActivityGroupRepository{}, event.NewDispatcher())
+
+	updated, err := svc.Update(context.Background(), dto.TodoItemUpdateRequest{Uuid: "item", ActivityUuid: "activity", Name: "daily stand-up"})
+	if err != nil {
+		t.Fatalf("updating: %s", err)
+	}
+	if updated.Name != "daily stand-up" {
+		t.Fatalf("name %q, want the new one", updated.Name)
+	}
+	if updated.DueAt == nil || !updated.DueAt.Equal(dueAt) {
+		t.Fatalf("due %v, want it kept", updated.DueAt)
+	}
+	if updated.CompletedAt == nil || !updated.CompletedAt.Equal(completedAt) {
+		t.Fatalf("completed %v, want it kept", updated.CompletedAt)
+	}
+}
+
+func TestUpdateClearsDueAtAndReopens(t *testing.T) {
+	dueAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	repo := &fakeTodoItemRepository{items: map[string]*entity.TodoItem{
+		"item": {Uuid: "item", ActivityID: 1, Name: "stand-up", DueAt: &dueAt, CompletedAt: &completedAt},
+	}}
+	svc := NewTodoItemService(validator.New(), testTimeouts, repo, nil, nil, &fakeActivityGroupRepository{}, event.NewDispatcher())
+
+	reopen := false
+	updated, err := svc.Update(context.Background(), dto.TodoItemUpdateRequest{Uuid: "item", ActivityUuid: "activity", Name: "stand-up", ClearDueAt: true, IsCompleted: &reopen})
+	if err != nil {
+		t.Fatalf("updating: %s", err)
+	}
+	if updated.DueAt != nil || updated.CompletedAt != nil {
+		t.Fatalf("due %v, completed %v, want both cleared", updated.DueAt, updated.CompletedAt)
+	}
+
+	// a due date and clear_due_at contradict each other
+	_, err = svc.Update(context.Background(), dto.TodoItemUpdateRequest{Uuid: "item", ActivityUuid: "activity", Name: "stand-up", DueAt: &dueAt, ClearDueAt: true})
+	if err == nil {
+		t.Fatal("due_at with clear_due_at passed validation")
+	}
+}
+
+func TestGenerateOccurrencesCarriesReminders(t *testing.T) {
+	dueAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	firedAt := dueAt.Add(-10 * time.Minute)
+	current := 1
+	repo := &fakeTodoItemRepository{items: map[string]*entity.TodoItem{
+		"item": {ID: current, Uuid: "item", ActivityID: 1, Name: "stand-up", DueAt: &dueAt},
+	}}
+	repoSeries := &fakeTodoSeriesRepository{series: []*entity.TodoSeries{
+		{ID: 1, ActivityID: 1, Name: "stand-up", Rrule: "FREQ=DAILY", StartAt: dueAt, Occurrences: 1, CurrentDueAt: dueAt, CurrentItemID: &current},
+	}}
+	repoReminder := &fakeTodoReminderRepository{reminders: []*entity.TodoReminder{
+		{ID: 1, Uuid: "alice", TodoItemID: current, User: "alice", OffsetMinutes: 10, FiredDueAt: &dueAt, FiredAt: &firedAt},
+		{ID: 2, Uuid: "bob", TodoItemID: current, User: "bob", OffsetMinutes: 60},
+	}}
+	svc := NewTodoItemService(validator.New(), testTimeouts, repo, repoSeries, repoReminder, &fakeActivityGroupRepository{}, event.NewDispatcher())
+
+	generated, err := svc.GenerateOccurrences(context.Background())
+	if err != nil || generated != 1 {
+		t.Fatalf("generated %d, %v, want 1", generated, err)
+	}
+
+	next := *repoSeries.series[0].CurrentItemID
+	if next == current {
+		t.Fatal("series still points at the old occurrence")
+	}
+
+	carried := map[string]*entity.TodoReminder{}
+	for _, reminder := range repoReminder.reminders {
+		if reminder.TodoItemID == next {
+			carried[reminder.User] = reminder
+		}
+	}
+	if len(carried) != 2 || carried["alice"].OffsetMinutes != 10 || carried["bob"].OffsetMinutes != 60 {
+		t.Fatalf("next occurrence has reminders %v, want those of alice and bob", carried)
+	}
+	if carried["alice"].FiredDueAt != nil || carried["alice"].FiredAt != nil {
+		t.Fatal("carried reminder is already fired")
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/event"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/Adhiana46/go-restapi-template/pkg/tracing"
+	"github.com/jmoiron/sqlx"
+)
+
+// The traced services wrap each call of a transport or listener in a span, background work
+// such as DeliverPending, DispatchDue, GenerateOccurrences, metric scrapes or the event log polling passes through untraced
+
+type tracedActivityGroupService struct {
+	ActivityGroupService
+}
+
+func WithActivityGroupTracing(s ActivityGroupService) ActivityGroupService {
+	return &tracedActivityGroupService{s}
+}
+
+func (s *tracedActivityGroupService) FindByUuid(ctx context.Context, req dto.ActivityGroupUuidRequest) (ent *entity.ActivityGroup, err error) {
+	ctx, span := tracing.Start(ctx, "ActivityGroupService.FindByUuid")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ActivityGroupService.FindByUuid(ctx, req)
+}
+
+func (s *tracedActivityGroupService) FindByIds(ctx context.Context, req dto.ActivityGroupIdsRequest) (ents []*entity.ActivityGroup, err error) {
+	ctx, span := tracing.Start(ctx, "ActivityGroupService.FindByIds")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ActivityGroupService.FindByIds(ctx, req)
+}
+
+func (s *tracedActivityGroupService) FetchAll(ctx context.Context, req dto.ActivityGroupFetchRequest) (ents []*entity.ActivityGroup, pagination *responsePkg.Pagination, err error) {
+	ctx, span := tracing.Start(ctx, "ActivityGroupService.FetchAll")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ActivityGroupService.FetchAll(ctx, req)
+}
+
+func (s *tracedActivityGroupService) Create(ctx context.Context, req dto.ActivityGroupCreateRequest) (ent *entity.ActivityGroup, err error) {
+	ctx, span := tracing.Start(ctx, "ActivityGroupService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ActivityGroupService.Create(ctx, req)
+}
+
+func (s *tracedActivityGroupService) Update(ctx context.Context, req dto.ActivityGroupUpdateRequest) (ent *entity.ActivityGroup, err error) {
+	ctx, span := tracing.Start(ctx, "ActivityGroupService.Update")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ActivityGroupService.Update(ctx, req)
+}
+
+func (s *tracedActivityGroupService) Delete(ctx context.Context, req dto.ActivityGroupUuidRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "ActivityGroupService.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ActivityGroupService.Delete(ctx, req)
+}
+
+type tracedTodoItemService struct {
+	TodoItemService
+}
+
+func WithTodoItemTracing(s TodoItemService) TodoItemService {
+	return &tracedTodoItemService{s}
+}
+
+func (s *tracedTodoItemService) FindByUuid(ctx context.Context, req dto.TodoItemUuidRequest) (ent *entity.TodoItem, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItemService.FindByUuid")
+	defer func() { tracing.End(span, err) }()
+
+	return s.TodoItemService.FindByUuid(ctx, req)
+}
+
+func (s *tracedTodoItemService) FetchAll(ctx context.Context, req dto.TodoItemFetchRequest) (ents []*entity.TodoItem, pagination *responsePkg.Pagination, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItemService.FetchAll")
+	defer func() { tracing.End(span, err) }()
+
+	return s.TodoItemService.FetchAll(ctx, req)
+}
+
+func (s *tracedTodoItemService) CountByActivityIds(ctx context.Context, req dto.TodoItemCountRequest) (totals map[int]int, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItemService.CountByActivityIds")
+	defer func() { tracing.End(span, err) }()
+
+	return s.TodoItemService.CountByActivityIds(ctx, req)
+}
+
+func (s *tracedTodoItemService) Create(ctx context.Context, req dto.TodoItemCreateRequest) (ent *entity.TodoItem, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItemService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	return s.TodoItemService.Create(ctx, req)
+}
+
+func (s *tracedTodoItemService) Update(ctx context.Context, req dto.TodoItemUpdateRequest) (ent *entity.TodoItem, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItemService.Update")
+	defer func() { tracing.End(span, err) }()
+
+	return s.TodoItemService.Update(ctx, req)
+}
+
+func (s *tracedTodoItemService) Delete(ctx context.Context, req dto.TodoItemUuidRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "TodoItemService.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	return s.TodoItemService.Delete(ctx, req)
+}
+
+func (s *tracedTodoItemService) FindSeries(ctx context.Context, req dto.TodoItemUuidRequest) (ent *entity.TodoSeries, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItemService.FindSeries")
+	defer func() { tracing.End(span, err) }()
+
+	return s.TodoItemService.FindSeries(ctx, req)
+}
+
+func (s *tracedTodoItemService) UpdateSeries(ctx context.Context, req dto.TodoSeriesUpdateRequest) (ent *entity.TodoSeries, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItemService.UpdateSeries")
+	defer func() { tracing.End(span, err) }()
+
+	return s.TodoItemService.UpdateSeries(ctx, req)
+}
+
+func (s *tracedTodoItemService) EndSeries(ctx context.Context, req dto.TodoItemUuidRequest) (ent *entity.TodoSeries, err error) {
+	ctx, span := tracing.Start(ctx, "TodoItemService.EndSeries")
+	defer func() { tracing.End(span, err) }()
+
+	return s.TodoItemService.EndSeries(ctx, req)
+}
+
+type tracedWebhookService struct {
+	WebhookService
+}
+
+func WithWebhookTracing(s WebhookService) WebhookService {
+	return &tracedWebhookService{s}
+}
+
+func (s *tracedWebhookService) Handle(ctx context.Context, tx *sqlx.Tx, e event.Event) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Handle")
+	defer func() { tracing.End(span, err) }()
+
+	return s.WebhookService.Handle(ctx, tx, e)
+}
+
+func (s *tracedWebhookService) FindByUuid(ctx context.Context, req dto.WebhookUuidRequest) (ent *entity.WebhookEndpoint, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.FindByUuid")
+	defer func() { tracing.End(span, err) }()
+
+	return s.WebhookService.FindByUuid(ctx, req)
+}
+
+func (s *tracedWebhookService) FetchAll(ctx context.Context, req dto.WebhookFetchRequest) (ents []*entity.WebhookEndpoint, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.FetchAll")
+	defer func() { tracing.End(span, err) }()
+
+	return s.WebhookService.FetchAll(ctx, req)
+}
+
+func (s *tracedWebhookService) Create(ctx context.Context, req dto.WebhookCreateRequest) (ent *entity.WebhookEndpoint, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	return s.WebhookService.Create(ctx, req)
+}
+
+func (s *tracedWebhookService) Update(ctx context.Context, req dto.WebhookUpdateRequest) (ent *entity.WebhookEndpoint, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Update")
+	defer func() { tracing.End(span, err) }()
+
+	return s.WebhookService.Update(ctx, req)
+}
+
+func (s *tracedWebhookService) Delete(ctx context.Context, req dto.WebhookUuidRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	return s.WebhookService.Delete(ctx, req)
+}
+
+func (s *tracedWebhookService) FetchDeliveries(ctx context.Context, req dto.WebhookDeliveryFetchRequest) (ents []*entity.WebhookDelivery, pagination *responsePkg.Pagination, err error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.FetchDeliveries")
+	defer func() { tracing.End(span, err) }()
+
+	return s.WebhookService.FetchDeliveries(ctx, req)
+}
+
+type tracedActivityEventService struct {
+	ActivityEventService
+}
+
+func WithActivityEventTracing(s ActivityEventService) ActivityEventService {
+	return &tracedActivityEventService{s}
+}
+
+func (s *tracedActivityEventService) Handle(ctx context.Context, tx *sqlx.Tx, e event.Event) (err error) {
+	ctx, span := tracing.Start(ctx, "ActivityEventService.Handle")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ActivityEventService.Handle(ctx, tx, e)
+}
+
+type tracedScheduledActionService struct {
+	ScheduledActionService
+}
+
+func WithScheduledActionTracing(s ScheduledActionService) ScheduledActionService {
+	return &tracedScheduledActionService{s}
+}
+
+func (s *tracedScheduledActionService) Schedule(ctx context.Context, req dto.ScheduledActionCreateRequest) (ent *entity.ScheduledAction, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduledActionService.Schedule")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ScheduledActionService.Schedule(ctx, req)
+}
+
+func (s *tracedScheduledActionService) FindByUuid(ctx context.Context, req dto.ScheduledActionUuidRequest) (ent *entity.ScheduledAction, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduledActionService.FindByUuid")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ScheduledActionService.FindByUuid(ctx, req)
+}
+
+func (s *tracedScheduledActionService) FetchAll(ctx context.Context, req dto.ScheduledActionFetchRequest) (ents []*entity.ScheduledAction, pagination *responsePkg.Pagination, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduledActionService.FetchAll")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ScheduledActionService.FetchAll(ctx, req)
+}
+
+func (s *tracedScheduledActionService) Cancel(ctx context.Context, req dto.ScheduledActionUuidRequest) (ent *entity.ScheduledAction, err error) {
+	ctx, span := tracing.Start(ctx, "ScheduledActionService.Cancel")
+	defer func() { tracing.End(span, err) }()
+
+	return s.ScheduledActionService.Cancel(ctx, req)
+}
+
+type tracedNotificationService struct {
+	NotificationService
+}
+
+func WithNotificationTracing(s NotificationService) NotificationService {
+	return &tracedNotificationService{s}
+}
+
+func (s *tracedNotificationService) FetchReminders(ctx context.Context, req dto.TodoReminderFetchRequest) (ents []*entity.TodoReminder, err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.FetchReminders")
+	defer func() { tracing.End(span, err) }()
+
+	return s.NotificationService.FetchReminders(ctx, req)
+}
+
+func (s *tracedNotificationService) CreateReminder(ctx context.Context, req dto.TodoReminderCreateRequest) (ent *entity.TodoReminder, err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.CreateReminder")
+	defer func() { tracing.End(span, err) }()
+
+	return s.NotificationService.CreateReminder(ctx, req)
+}
+
+func (s *tracedNotificationService) DeleteReminder(ctx context.Context, req dto.TodoReminderUuidRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.DeleteReminder")
+	defer func() { tracing.End(span, err) }()
+
+	return s.NotificationService.DeleteReminder(ctx, req)
+}
+
+func (s *tracedNotificationService) FindPreference(ctx context.Context, req dto.NotificationPreferenceFindRequest) (ent *entity.NotificationPreference, err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.FindPreference")
+	defer func() { tracing.End(span, err) }()
+
+	return s.NotificationService.FindPreference(ctx, req)
+}
+
+func (s *tracedNotificationService) UpdatePreference(ctx context.Context, req dto.NotificationPreferenceUpdateRequest) (ent *entity.NotificationPreference, err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.UpdatePreference")
+	defer func() { tracing.End(span, err) }()
+
+	return s.NotificationService.UpdatePreference(ctx, req)
+}
+
+func (s *tracedNotificationService) FetchDeliveries(ctx context.Context, req dto.NotificationDeliveryFetchRequest) (ents []*entity.NotificationDelivery, pagination *responsePkg.Pagination, err error) {
+	ctx, span := tracing.Start(ctx, "NotificationService.FetchDeliveries")
+	defer func() { tracing.End(span, err) }()
+
+	return s.NotificationService.FetchDeliveries(ctx, req)
+}
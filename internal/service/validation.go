@@ -0,0 +1,43 @@
+package service
+
+import (
+	"github.com/Adhiana46/go-restapi-template/internal/event"
+	"github.com/Adhiana46/go-restapi-template/pkg/rrule"
+	"github.com/Adhiana46/go-restapi-template/pkg/webhook"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidations registers the custom validation tags used by the requests of the services, once per validator
+func RegisterValidations(validate *validator.Validate) error {
+	if err := validate.RegisterValidation("rrule", validateRrule); err != nil {
+		return err
+	}
+	if err := validate.RegisterValidation("public_url", validatePublicUrl); err != nil {
+		return err
+	}
+
+	return validate.RegisterValidation("webhook_event", validateWebhookEvent)
+}
+
+func validateRrule(fl validator.FieldLevel) bool {
+	_, err := rrule.Parse(fl.Field().String())
+	return err == nil
+}
+
+func validatePublicUrl(fl validator.FieldLevel) bool {
+	return webhook.PublicUrl(fl.Field().String())
+}
+
+func validateWebhookEvent(fl validator.FieldLevel) bool {
+	name := fl.Field().String()
+	if name == webhookAllEvents {
+		return true
+	}
+	for _, n := range event.Names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
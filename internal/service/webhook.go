@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/event"
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/Adhiana46/go-restapi-template/pkg/webhook"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	webhookAllEvents     = "*"
+	webhookMaxAttempts   = 8
+	webhookDisableAfter  = 20
+	webhookBaseBackoff   = 10 * time.Second
+	webhookMaxBackoff    = 1 * time.Hour
+	webhookDeliveryClaim = 10 * time.Minute
+)
+
+// WebhookDeliveryBatchSize is the most deliveries DeliverPending sends, one after the other
+const WebhookDeliveryBatchSize = 20
+
+type WebhookService interface {
+	event.Listener
+
+	FindByUuid(ctx context.Context, req dto.WebhookUuidRequest) (*entity.WebhookEndpoint, error)
+	FetchAll(ctx context.Context, req dto.WebhookFetchRequest) ([]*entity.WebhookEndpoint, error)
+	Create(ctx context.Context, req dto.WebhookCreateRequest) (*entity.WebhookEndpoint, error)
+	Update(ctx context.Context, req dto.WebhookUpdateRequest) (*entity.WebhookEndpoint, error)
+	Delete(ctx context.Context, req dto.WebhookUuidRequest) error
+	FetchDeliveries(ctx context.Context, req dto.WebhookDeliveryFetchRequest) ([]*entity.WebhookDelivery, *responsePkg.Pagination, error)
+
+	DeliverPending(ctx context.Context) (int, error)
+}
+
+type webhookService struct {
+	validate     *validator.Validate
+	timeouts     Timeouts
+	repo         repository.WebhookRepository
+	repoActivity repository.ActivityGroupRepository
+	sender       webhook.Sender
+}
+
+func NewWebhookService(validate *validator.Validate, timeouts Timeouts, repo repository.WebhookRepository, repoActivity repository.ActivityGroupRepository, sender webhook.Sender) WebhookService {
+	return &webhookService{
+		validate:     validate,
+		timeouts:     timeouts,
+		repo:         repo,
+		repoActivity: repoActivity,
+		sender:       sender,
+	}
+}
+
+func (s *webhookService) FindByUuid(ctx context.Context, req dto.WebhookUuidRequest) (*entity.WebhookEndpoint, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	return s.findInActivity(ctx, req.ActivityUuid, req.Uuid)
+}
+
+func (s *webhookService) FetchAll(ctx context.Context, req dto.WebhookFetchRequest) ([]*entity.WebhookEndpoint, error) {
+	ctx, cancel := s.timeouts.read(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	activity, err := s.repoActivity.FindByUuid(ctx, req.ActivityUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FetchAll(ctx, activity.ID)
+}
+
+func (s *webhookService) Create(ctx context.Context, req dto.WebhookCreateRequest) (*entity.WebhookEndpoint, error) {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	activity, err := s.repoActivity.FindByUuid(ctx, req.ActivityUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	ent := &entity.WebhookEndpoint{
+		Uuid:       uuid.NewString(),
+		ActivityID: activity.ID,
+		Url:        req.Url,
+		Secret:     req.Secret,
+		Events:     webhookEventFilter(req.Events),
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	// begin transaction
+	tx := s.repo.BeginTx(ctx)
+	insertedRow, err := s.repo.Store(ctx, tx, ent)
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	} else {
+		tx.Commit()
+	}
+
+	return insertedRow, nil
+}
+
+func (s *webhookService) Update(ctx context.Context, req dto.WebhookUpdateRequest) (*entity.WebhookEndpoint, error) {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, apperror.Validation(err)
+	}
+
+	// begin transaction, the endpoint is read on the primary and locked until the update commits
+	tx := s.repo.BeginTx(ctx)
+	ent, err := s.findInActivityTx(ctx, tx, req.ActivityUuid, req.Uuid)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Update values
+	ent.Url = req.Url
+	ent.Events = webhookEventFilter(req.Events)
+	if req.Secret != "" {
+		ent.Secret = req.Secret
+	}
+	if req.IsActive != nil {
+		if *req.IsActive && !ent.IsActive {
+			// re-enabling an endpoint gives it a fresh failure budget
+			ent.FailureCount = 0
+			ent.DisabledAt = nil
+		}
+		ent.IsActive = *req.IsActive
+	}
+	ent.UpdatedAt = time.Now()
+
+	updatedRow, err := s.repo.Update(ctx, tx, ent)
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	} else {
+		tx.Commit()
+	}
+
+	return updatedRow, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, req dto.WebhookUuidRequest) error {
+	ctx, cancel := s.timeouts.write(ctx)
+	defer cancel()
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return apperror.Validation(err)
+	}
+
+	// begin transaction, the endpoint is read on the primary and locked until it is deleted
+	tx := s.repo.BeginTx(ctx)
+	ent, err := s.findInActivityTx(ctx, tx, req.ActivityUuid, req.Uuid)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = s.repo.Delete(ctx, tx, ent)
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return err
+	} else {
+		tx.Commit()
+	}
+
+	return nil
+}
+
+func (s *webhookService) FetchDeliveries(ctx context.Context, req dto.WebhookDeliveryFetchRequest) ([]*entity.WebhookDelivery, *responsePkg.Pagination, error) {
+	countCtx, ctx, cancel := s.timeouts.page(ctx)
+	defer cancel()
+
+	// Set Default Value
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	// Validate
+	if err := s.validate.Struct(req); err != nil {
+		return nil, nil, apperror.Validation(err)
+	}
+
+	endpoint, err := s.findInActivity(ctx, req.ActivityUuid, req.Uuid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	totalRows, err := s.repo.CountDeliveries(countCtx, endpoint.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deliveryList, err := s.repo.FetchDeliveries(ctx, endpoint.ID, req.Page, req.Limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Create Pagination
+	pagination := newPagination(req.Page, req.Limit, totalRows, len(deliveryList))
+
+	return deliveryList, pagination, nil
+}
+
+// Handle queues a delivery for every active endpoint of the activity group subscribed to the event, the delivery
+// keeps its own copy of the url and secret so it is still sent after the endpoint or its group is deleted
+func (s *webhookService) Handle(ctx context.Context, tx *sqlx.Tx, e event.Event) error {
+	endpoints, err := s.repo.FetchActiveTx(ctx, tx, e.ActivityUuid)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		if !webhookSubscribed(endpoint, e.Name) {
+			continue
+		}
+
+		err = s.repo.StoreDelivery(ctx, tx, &entity.WebhookDelivery{
+			Uuid:          uuid.NewString(),
+			EndpointID:    &endpoint.ID,
+			Url:           endpoint.Url,
+			Secret:        endpoint.Secret,
+			Event:         e.Name,
+			Payload:       string(payload),
+			Status:        entity.WebhookDeliveryPending,
+			NextAttemptAt: time.Now(),
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeliverPending sends one batch of due deliveries and returns how many were attempted. The batch is claimed up
+// front and every send is recorded in a short transaction of its own, so no lock or connection is held while an
+// endpoint answers; deliveries claimed by a worker that died are sent again once the claim runs out.
+func (s *webhookService) DeliverPending(ctx context.Context) (int, error) {
+	now := time.Now()
+	claimUntil := now.Add(webhookDeliveryClaim)
+	if deadline, ok := ctx.Deadline(); ok && deadline.After(claimUntil) {
+		claimUntil = deadline
+	}
+
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, now, claimUntil, WebhookDeliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if err := s.deliver(ctx, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (s *webhookService) deliver(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	// the endpoint is gone once it or its group is deleted, the delivery is still sent to its snapshot
+	var endpoint *entity.WebhookEndpoint
+	if delivery.EndpointID != nil {
+		found, err := s.repo.FindById(ctx, *delivery.EndpointID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		endpoint = found
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+
+	if endpoint != nil && !endpoint.IsActive {
+		errMsg := "endpoint disabled"
+		delivery.Status = entity.WebhookDeliveryFailed
+		delivery.LastError = &errMsg
+		return s.record(ctx, delivery, nil, nil)
+	}
+
+	started := time.Now()
+	statusCode, sendErr := s.sender.Send(ctx, delivery.Url, delivery.Secret, delivery.Event, delivery.Uuid, []byte(delivery.Payload))
+	duration := time.Since(started)
+
+	attempt := &entity.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		DurationMs: int(duration.Milliseconds()),
+		CreatedAt:  now,
+	}
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+		delivery.LastStatusCode = &statusCode
+	}
+
+	if sendErr == nil {
+		delivery.Status = entity.WebhookDeliverySuccess
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+	} else {
+		errMsg := sendErr.Error()
+		attempt.Error = &errMsg
+		delivery.LastError = &errMsg
+		delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = entity.WebhookDeliveryFailed
+		}
+	}
+
+	return s.record(ctx, delivery, attempt, sendErr)
+}
+
+// record stores the outcome of a delivery, of a send when attempt is set, and counts it against the endpoint
+func (s *webhookService) record(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt, sendErr error) error {
+	tx := s.repo.BeginTx(ctx)
+
+	err := s.repo.UpdateDelivery(ctx, tx, delivery)
+	if err == nil && attempt != nil {
+		err = s.repo.StoreDeliveryAttempt(ctx, tx, attempt)
+	}
+	if err == nil && attempt != nil && delivery.EndpointID != nil {
+		var endpoint *entity.WebhookEndpoint
+		endpoint, err = s.repo.FindByIdTx(ctx, tx, *delivery.EndpointID)
+		if err == nil {
+			webhookCountResult(ctx, endpoint, sendErr, attempt.CreatedAt)
+			_, err = s.repo.Update(ctx, tx, endpoint)
+		} else if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+	}
+
+	// if error rollback, commit otherwise
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// webhookCountResult tracks consecutive failures of the endpoint and disables it after webhookDisableAfter
+func webhookCountResult(ctx context.Context, endpoint *entity.WebhookEndpoint, sendErr error, now time.Time) {
+	if sendErr == nil {
+		endpoint.FailureCount = 0
+	} else {
+		endpoint.FailureCount++
+		if endpoint.FailureCount >= webhookDisableAfter && endpoint.IsActive {
+			logging.FromContext(ctx).WithField("webhook_uuid", endpoint.Uuid).Warnf("[webhook] disabling endpoint %s after %d consecutive failures", endpoint.Uuid, endpoint.FailureCount)
+			endpoint.IsActive = false
+			endpoint.DisabledAt = &now
+		}
+	}
+	endpoint.UpdatedAt = now
+}
+
+func (s *webhookService) findInActivity(ctx context.Context, activityUuid string, webhookUuid string) (*entity.WebhookEndpoint, error) {
+	activity, err := s.repoActivity.FindByUuid(ctx, activityUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	ent, err := s.repo.FindByUuid(ctx, webhookUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if ent.ActivityID != activity.ID {
+		return nil, apperror.NotFound(apperror.CodeWebhookNotFound, "webhook not found")
+	}
+
+	return ent, nil
+}
+
+// findInActivityTx is findInActivity locking the endpoint until tx ends
+func (s *webhookService) findInActivityTx(ctx context.Context, tx *sqlx.Tx, activityUuid string, webhookUuid string) (*entity.WebhookEndpoint, error) {
+	activity, err := s.repoActivity.FindByUuid(ctx, activityUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	ent, err := s.repo.FindByUuidTx(ctx, tx, webhookUuid)
+	if err != nil {
+		return nil, err
+	}
+
+	if ent.ActivityID != activity.ID {
+		return nil, apperror.NotFound(apperror.CodeWebhookNotFound, "webhook not found")
+	}
+
+	return ent, nil
+}
+
+func webhookEventFilter(events []string) string {
+	if len(events) == 0 {
+		return webhookAllEvents
+	}
+
+	return strings.Join(events, ",")
+}
+
+func webhookSubscribed(endpoint *entity.WebhookEndpoint, name string) bool {
+	for _, e := range strings.Split(endpoint.Events, ",") {
+		if e == webhookAllEvents || e == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// webhookBackoff doubles the wait after every failed attempt, capped at webhookMaxBackoff
+func webhookBackoff(attempt int) time.Duration {
+	backoff := time.Duration(math.Pow(2, float64(attempt-1))) * webhookBaseBackoff
+	if backoff > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+
+	return backoff
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/event"
+	"github.com/Adhiana46/go-restapi-template/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/jmoiron/sqlx"
+)
+
+// fakeWebhookRepository keeps endpoints and deliveries in memory, methods a test doesn't need panic through the
+// embedded nil interface
+type fakeWebhookRepository struct {
+	repository.WebhookRepository
+
+	endpoints  map[string][]*entity.WebhookEndpoint
+	deliveries []*entity.WebhookDelivery
+	attempts   []*entity.WebhookDeliveryAttempt
+}
+
+func (r *fakeWebhookRepository) BeginTx(ctx context.Context) *sqlx.Tx {
+	return beginTestTx(ctx)
+}
+
+func (r *fakeWebhookRepository) FindById(ctx context.Context, id int) (*entity.WebhookEndpoint, error) {
+	for _, endpoints := range r.endpoints {
+		for _, endpoint := range endpoints {
+			if endpoint.ID == id {
+				return endpoint, nil
+			}
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeWebhookRepository) FindByIdTx(ctx context.Context, tx *sqlx.Tx, id int) (*entity.WebhookEndpoint, error) {
+	return r.FindById(ctx, id)
+}
+
+func (r *fakeWebhookRepository) FindByUuidTx(ctx context.Context, tx *sqlx.Tx, uuid string) (*entity.WebhookEndpoint, error) {
+	for _, endpoints := range r.endpoints {
+		for _, endpoint := range endpoints {
+			if endpoint.Uuid == uuid {
+				return endpoint, nil
+			}
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeWebhookRepository) Update(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookEndpoint) (*entity.WebhookEndpoint, error) {
+	return e, nil
+}
+
+func (r *fakeWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, claimUntil time.Time, limit int) ([]*entity.WebhookDelivery, error) {
+	claimed := []*entity.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if len(claimed) < limit && delivery.Status == entity.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			delivery.NextAttemptAt = claimUntil
+			claimed = append(claimed, delivery)
+		}
+	}
+
+	return claimed, nil
+}
+
+func (r *fakeWebhookRepository) UpdateDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookDelivery) error {
+	return nil
+}
+
+func (r *fakeWebhookRepository) StoreDeliveryAttempt(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookDeliveryAttempt) error {
+	r.attempts = append(r.attempts, e)
+	return nil
+}
+
+type fakeWebhookSender struct {
+	sent []string
+	err  error
+}
+
+func (s *fakeWebhookSender) Send(ctx context.Context, url string, secret string, event string, deliveryUuid string, body []byte) (int, error) {
+	s.sent = append(s.sent, url)
+	if s.err != nil {
+		return 500, s.err
+	}
+
+	return 200, nil
+}
+
+func (r *fakeWebhookRepository) FetchActiveTx(ctx context.Context, tx *sqlx.Tx, activityUuid string) ([]*entity.WebhookEndpoint, error) {
+	active := []*entity.WebhookEndpoint{}
+	for _, endpoint := range r.endpoints[activityUuid] {
+		if endpoint.IsActive {
+			active = append(active, endpoint)
+		}
+	}
+
+	return active, nil
+}
+
+func (r *fakeWebhookRepository) StoreDelivery(ctx context.Context, tx *sqlx.Tx, e *entity.WebhookDelivery) error {
+	e.ID = len(r.deliveries) + 1
+	r.deliveries = append(r.deliveries, e)
+	return nil
+}
+
+func TestWebhookHandleSnapshotsSubscribedEndpoints(t *testing.T) {
+	repo := &fakeWebhookRepository{
+		endpoints: map[string][]*entity.WebhookEndpoint{
+			"activity": {
+				{ID: 1, Url: "https://all.example.com", Secret: "all", Events: webhookAllEvents, IsActive: true},
+				{ID: 2, Url: "https://deleted.example.com", Secret: "deleted", Events: event.ActivityGroupDeleted, IsActive: true},
+				{ID: 3, Url: "https://created.example.com", Secret: "created", Events: event.TodoItemCreated, IsActive: true},
+				{ID: 4, Url: "https://disabled.example.com", Secret: "disabled", Events: webhookAllEvents, IsActive: false},
+			},
+		},
+	}
+	s := NewWebhookService(validator.New(), Timeouts{}, repo, nil, nil)
+
+	err := s.Handle(context.Background(), nil, event.New(event.ActivityGroupDeleted, "activity", nil))
+	if err != nil {
+		t.Fatalf("handling event: %s", err)
+	}
+
+	if len(repo.deliveries) != 2 {
+		t.Fatalf("queued %d deliveries, want 2", len(repo.deliveries))
+	}
+	for i, want := range []struct {
+		endpointId int
+		url        string
+		secret     string
+	}{
+		{1, "https://all.example.com", "all"},
+		{2, "https://deleted.example.com", "deleted"},
+	} {
+		delivery := repo.deliveries[i]
+		if delivery.EndpointID == nil || *delivery.EndpointID != want.endpointId {
+			t.Errorf("delivery %d: endpoint %v, want %d", i, delivery.EndpointID, want.endpointId)
+		}
+		if delivery.Url != want.url || delivery.Secret != want.secret {
+			t.Errorf("delivery %d: snapshot %s/%s, want %s/%s", i, delivery.Url, delivery.Secret, want.url, want.secret)
+		}
+		if delivery.Status != entity.WebhookDeliveryPending || delivery.Event != event.ActivityGroupDeleted {
+			t.Errorf("delivery %d: %s %s", i, delivery.Status, delivery.Event)
+		}
+	}
+}
+
+func TestWebhookDeliverPendingSendsToSnapshot(t *testing.T) {
+	goneId := 99
+	repo := &fakeWebhookRepository{
+		deliveries: []*entity.WebhookDelivery{
+			{ID: 1, EndpointID: &goneId, Url: "https://gone.example.com", Secret: "gone", Status: entity.WebhookDeliveryPending, NextAttemptAt: time.Now().Add(-time.Minute)},
+			{ID: 2, Url: "https://orphan.example.com", Secret: "orphan", Status: entity.WebhookDeliveryPending, NextAttemptAt: time.Now().Add(-time.Minute)},
+			{ID: 3, Url: "https://later.example.com", Secret: "later", Status: entity.WebhookDeliveryPending, NextAttemptAt: time.Now().Add(time.Hour)},
+		},
+	}
+	sender := &fakeWebhookSender{}
+	s := NewWebhookService(validator.New(), Timeouts{}, repo, nil, sender)
+
+	attempted, err := s.DeliverPending(context.Background())
+	if err != nil {
+		t.Fatalf("delivering: %s", err)
+	}
+	if attempted != 2 {
+		t.Fatalf("attempted %d deliveries, want 2", attempted)
+	}
+	if len(sender.sent) != 2 || sender.sent[0] != "https://gone.example.com" || sender.sent[1] != "https://orphan.example.com" {
+		t.Fatalf("sent to %v", sender.sent)
+	}
+	for _, delivery := range repo.deliveries[:2] {
+		if delivery.Status != entity.WebhookDeliverySuccess || delivery.Attempts != 1 || delivery.DeliveredAt == nil {
+			t.Errorf("delivery %d: status %s after %d attempts", delivery.ID, delivery.Status, delivery.Attempts)
+		}
+	}
+	if len(repo.attempts) != 2 {
+		t.Errorf("recorded %d attempts, want 2", len(repo.attempts))
+	}
+}
+
+func TestWebhookDeliverPendingCountsFailures(t *testing.T) {
+	endpoint := &entity.WebhookEndpoint{ID: 1, Url: "https://down.example.com", Events: webhookAllEvents, IsActive: true, FailureCount: webhookDisableAfter - 1}
+	endpointId := endpoint.ID
+	repo := &fakeWebhookRepository{
+		endpoints: map[string][]*entity.WebhookEndpoint{"activity": {endpoint}},
+		deliveries: []*entity.WebhookDelivery{
+			{ID: 1, EndpointID: &endpointId, Url: endpoint.Url, Status: entity.WebhookDeliveryPending, NextAttemptAt: time.Now().Add(-time.Minute)},
+			{ID: 2, EndpointID: &endpointId, Url: endpoint.Url, Status: entity.WebhookDeliveryPending, NextAttemptAt: time.Now().Add(-time.Minute)},
+		},
+	}
+	sender := &fakeWebhookSender{err: errors.New("unexpected status 500")}
+	s := NewWebhookService(validator.New(), Timeouts{}, repo, nil, sender)
+
+	if _, err := s.DeliverPending(context.Background()); err != nil {
+		t.Fatalf("delivering: %s", err)
+	}
+
+	if endpoint.IsActive || endpoint.DisabledAt == nil {
+		t.Fatalf("endpoint still active after %d failures", endpoint.FailureCount)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d times, want the disabled endpoint skipped", len(sender.sent))
+	}
+
+	retried, skipped := repo.deliveries[0], repo.deliveries[1]
+	if retried.Status != entity.WebhookDeliveryPending || !retried.NextAttemptAt.After(time.Now()) {
+		t.Errorf("failed send: status %s, next attempt %s", retried.Status, retried.NextAttemptAt)
+	}
+	if skipped.Status != entity.WebhookDeliveryFailed {
+		t.Errorf("delivery to disabled endpoint: status %s", skipped.Status)
+	}
+}
+
+func TestWebhookUpdateKeepsActiveWhenOmitted(t *testing.T) {
+	disabledAt := time.Now()
+	endpoint := &entity.WebhookEndpoint{ID: 1, Uuid: "hook", ActivityID: 1, Url: "https://down.example.com", IsActive: false, FailureCount: webhookDisableAfter, DisabledAt: &disabledAt}
+	repo := &fakeWebhookRepository{endpoints: map[string][]*entity.WebhookEndpoint{"activity": {endpoint}}}
+	validate := validator.New()
+	if err := RegisterValidations(validate); err != nil {
+		t.Fatalf("registering validations: %s", err)
+	}
+	s := NewWebhookService(validate, testTimeouts, repo, &fakeActivityGroupRepository{}, nil)
+
+	updated, err := s.Update(context.Background(), dto.WebhookUpdateRequest{Uuid: "hook", ActivityUuid: "activity", Url: "https://up.example.com"})
+	if err != nil {
+		t.Fatalf("updating: %s", err)
+	}
+	if updated.IsActive || updated.DisabledAt == nil || updated.FailureCount != webhookDisableAfter {
+		t.Fatalf("update without is_active changed the endpoint to %+v, want it left disabled", updated)
+	}
+
+	active := true
+	updated, err = s.Update(context.Background(), dto.WebhookUpdateRequest{Uuid: "hook", ActivityUuid: "activity", Url: "https://up.example.com", IsActive: &active})
+	if err != nil {
+		t.Fatalf("updating: %s", err)
+	}
+	if !updated.IsActive || updated.DisabledAt != nil || updated.FailureCount != 0 {
+		t.Fatalf("re-enabled endpoint %+v, want a fresh failure budget", updated)
+	}
+}
+
+func TestWebhookRejectsPrivateUrls(t *testing.T) {
+	validate := validator.New()
+	if err := RegisterValidations(validate); err != nil {
+		t.Fatalf("registering validations: %s", err)
+	}
+	s := NewWebhookService(validate, testTimeouts, &fakeWebhookRepository{}, &fakeActivityGroupRepository{}, nil)
+
+	for _, url := range []string{"http://127.0.0.1:8000/hook", "http://localhost/hook", "http://169.254.169.254/latest/meta-data", "http://10.0.0.5/hook", "http://[::1]/hook"} {
+		_, err := s.Create(context.Background(), dto.WebhookCreateRequest{ActivityUuid: "activity", Url: url, Secret: "0123456789abcdef"})
+		if appErr := apperror.From(err); appErr.Code != apperror.CodeValidationFailed {
+			t.Errorf("creating an endpoint at %s: %v, want a validation error", url, err)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	log "github.com/sirupsen/logrus"
+)
+
+const subscriptionBuffer = 64
+
+// ErrTooSlow is why a subscription is closed when it falls a full buffer behind, the client should resume from
+// the last event id it got
+var ErrTooSlow = errors.New("subscriber too slow, resume from the last event id")
+
+// ErrShutdown is why every subscription is closed when the server shuts down
+var ErrShutdown = errors.New("server shutting down, resume from the last event id")
+
+// Hub polls the activity event log and fans new events out to the subscribers of each activity group
+type Hub interface {
+	Subscribe(activityUuids ...string) *Subscription
+	Run(ctx context.Context) error
+	// Shutdown closes every subscription with ErrShutdown, and later ones right away, so the streams following
+	// the hub end before the server waits for its requests
+	Shutdown()
+}
+
+type hub struct {
+	interval   time.Duration
+	gapTimeout time.Duration
+	// gap is the id after the last broadcast one while it is missing, only the Run loop touches it
+	gap gap
+
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+	shutdown    bool
+
+	svcActivityEvent service.ActivityEventService
+}
+
+// gap is an event id found missing while a higher one is committed
+type gap struct {
+	id     int64
+	seenAt time.Time
+	// xmax is the next transaction id when the gap was seen, the transaction that took id started before it
+	xmax int64
+	// ended is set once every transaction older than xmax has ended, id is given up on if the next poll misses it too
+	ended bool
+}
+
+// NewHub polls every interval, gapTimeout bounds how long an event waits behind an id that isn't committed yet
+// when the transaction horizon can't tell whether it will be
+func NewHub(interval time.Duration, gapTimeout time.Duration, svcActivityEvent service.ActivityEventService) Hub {
+	return &hub{
+		interval:         interval,
+		gapTimeout:       gapTimeout,
+		subscribers:      map[*Subscription]struct{}{},
+		svcActivityEvent: svcActivityEvent,
+	}
+}
+
+func (h *hub) Subscribe(activityUuids ...string) *Subscription {
+	ch := make(chan *entity.ActivityEvent, subscriptionBuffer)
+	sub := &Subscription{
+		C:          ch,
+		ch:         ch,
+		hub:        h,
+		activities: map[string]struct{}{},
+	}
+	for _, activityUuid := range activityUuids {
+		sub.activities[activityUuid] = struct{}{}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.shutdown {
+		sub.err = ErrShutdown
+		close(sub.ch)
+		return sub
+	}
+	h.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+func (h *hub) Shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.shutdown = true
+	for sub := range h.subscribers {
+		delete(h.subscribers, sub)
+		sub.err = ErrShutdown
+		close(sub.ch)
+	}
+}
+
+func (h *hub) Run(ctx context.Context) error {
+	lastId, err := h.svcActivityEvent.LastId(ctx)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			lastId = h.poll(ctx, lastId)
+		}
+	}
+}
+
+// poll broadcasts the events after lastId in id order. An id is taken when the transaction of a change stores
+// its event but only shows up once it commits, so subscribers never see an event before one with a lower id.
+// A missing id is waited for until every transaction running when it was found missing has ended, ids of
+// rolled back transactions and skipped sequence values are then given up on.
+func (h *hub) poll(ctx context.Context, lastId int64) int64 {
+	for {
+		events, err := h.svcActivityEvent.FetchSince(ctx, lastId)
+		if err != nil {
+			log.Errorf("[stream] polling activity events: %s", err)
+			return lastId
+		}
+		if len(events) == 0 {
+			return lastId
+		}
+
+		for _, e := range events {
+			if e.ID != lastId+1 {
+				if !h.gapClosed(ctx, lastId+1) {
+					return lastId
+				}
+				log.Warnf("[stream] skipping activity events %d to %d, never committed", lastId+1, e.ID-1)
+			}
+
+			h.broadcast(e)
+			lastId = e.ID
+		}
+	}
+}
+
+// gapClosed tells whether the missing id can no longer be committed, it is called once per poll while
+// id is missing, right after the fetch that missed it
+func (h *hub) gapClosed(ctx context.Context, id int64) bool {
+	if h.gap.id != id {
+		h.gap = gap{id: id, seenAt: time.Now()}
+		if _, xmax, err := h.svcActivityEvent.TransactionHorizon(ctx); err != nil {
+			log.Errorf("[stream] reading the transaction horizon: %s", err)
+		} else {
+			h.gap.xmax = xmax
+		}
+		return false
+	}
+
+	// the transaction of id had ended before this poll's fetch, which still missed it
+	if h.gap.ended || time.Since(h.gap.seenAt) >= h.gapTimeout {
+		return true
+	}
+
+	if h.gap.xmax != 0 {
+		xmin, _, err := h.svcActivityEvent.TransactionHorizon(ctx)
+		if err != nil {
+			log.Errorf("[stream] reading the transaction horizon: %s", err)
+		} else if xmin >= h.gap.xmax {
+			// it may have committed after the fetch of this poll, the next one settles it
+			h.gap.ended = true
+		}
+	}
+
+	return false
+}
+
+func (h *hub) broadcast(e *entity.ActivityEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers {
+		if !sub.Wants(e.ActivityUuid) {
+			continue
+		}
+
+		select {
+		case sub.ch <- e:
+		default:
+			// a subscriber that can't keep up is closed with ErrTooSlow, its transport tells the client to resume
+			go h.unsubscribe(sub, ErrTooSlow)
+		}
+	}
+}
+
+func (h *hub) unsubscribe(sub *Subscription, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		sub.err = err
+		close(sub.ch)
+	}
+}
+
+type Subscription struct {
+	C <-chan *entity.ActivityEvent
+
+	ch         chan *entity.ActivityEvent
+	hub        *hub
+	err        error
+	mu         sync.RWMutex
+	activities map[string]struct{}
+}
+
+func (s *Subscription) Add(activityUuid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.activities[activityUuid] = struct{}{}
+}
+
+func (s *Subscription) Remove(activityUuid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.activities, activityUuid)
+}
+
+func (s *Subscription) Wants(activityUuid string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.activities[activityUuid]
+	return ok
+}
+
+// Close detaches the subscription from the hub and closes C, it is safe to call more than once
+func (s *Subscription) Close() {
+	s.hub.unsubscribe(s, nil)
+}
+
+// Err tells why the hub closed C, it is nil while C is open or after Close
+func (s *Subscription) Err() error {
+	s.hub.mu.RLock()
+	defer s.hub.mu.RUnlock()
+
+	return s.err
+}
@@ -0,0 +1,249 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+)
+
+// fakeActivityEventService serves whatever events a test commits, in id order like the event log
+type fakeActivityEventService struct {
+	service.ActivityEventService
+
+	mu     sync.Mutex
+	events []*entity.ActivityEvent
+	// xmin and xmax are the transaction horizon, xmin < xmax while a transaction is running
+	xmin, xmax int64
+	horizonErr error
+}
+
+func (s *fakeActivityEventService) endTransactions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.xmin = s.xmax
+}
+
+func (s *fakeActivityEventService) TransactionHorizon(ctx context.Context) (int64, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.xmin, s.xmax, s.horizonErr
+}
+
+func (s *fakeActivityEventService) commit(activityUuid string, ids ...int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		s.events = append(s.events, &entity.ActivityEvent{ID: id, ActivityUuid: activityUuid})
+	}
+	sort.Slice(s.events, func(i, j int) bool { return s.events[i].ID < s.events[j].ID })
+}
+
+func (s *fakeActivityEventService) LastId(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (s *fakeActivityEventService) FetchSince(ctx context.Context, afterId int64) ([]*entity.ActivityEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := []*entity.ActivityEvent{}
+	for _, e := range s.events {
+		if e.ID > afterId {
+			events = append(events, e)
+		}
+	}
+
+	return events, nil
+}
+
+func received(sub *Subscription) []int64 {
+	ids := []int64{}
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				return ids
+			}
+			ids = append(ids, e.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func equalIds(a []int64, b ...int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// TestPollWaitsForUncommittedIds commits event 3 before event 2, like two transactions finishing out of order
+func TestPollWaitsForUncommittedIds(t *testing.T) {
+	events := &fakeActivityEventService{xmin: 10, xmax: 12}
+	h := NewHub(time.Second, time.Hour, events).(*hub)
+	sub := h.Subscribe("activity")
+	defer sub.Close()
+
+	events.commit("activity", 1, 3)
+	lastId := h.poll(context.Background(), 0)
+	if got := received(sub); lastId != 1 || !equalIds(got, 1) {
+		t.Fatalf("broadcast %v up to %d, want event 3 held back behind 2", got, lastId)
+	}
+
+	// the transaction of event 2 is still running
+	lastId = h.poll(context.Background(), lastId)
+	if got := received(sub); lastId != 1 || len(got) != 0 {
+		t.Fatalf("broadcast %v up to %d while event 2 may still commit", got, lastId)
+	}
+
+	events.commit("activity", 2)
+	lastId = h.poll(context.Background(), lastId)
+	if got := received(sub); lastId != 3 || !equalIds(got, 2, 3) {
+		t.Fatalf("broadcast %v up to %d, want 2 then 3", got, lastId)
+	}
+}
+
+// TestPollSkipsRolledBackIds gives up on a missing id once the transactions that could commit it have ended,
+// well before the gap timeout
+func TestPollSkipsRolledBackIds(t *testing.T) {
+	events := &fakeActivityEventService{xmin: 10, xmax: 12}
+	h := NewHub(time.Second, time.Hour, events).(*hub)
+	sub := h.Subscribe("activity")
+	defer sub.Close()
+
+	events.commit("activity", 1, 3)
+	lastId := h.poll(context.Background(), 0)
+	if lastId != 1 {
+		t.Fatalf("polled up to %d with event 2 missing", lastId)
+	}
+
+	// the fetch of this poll ran before the transactions ended, it can't tell whether event 2 committed
+	events.endTransactions()
+	lastId = h.poll(context.Background(), lastId)
+	if lastId != 1 {
+		t.Fatalf("polled up to %d right after the transactions ended", lastId)
+	}
+
+	lastId = h.poll(context.Background(), lastId)
+	if got := received(sub); lastId != 3 || !equalIds(got, 1, 3) {
+		t.Fatalf("broadcast %v up to %d, want id 2 given up on", got, lastId)
+	}
+}
+
+// TestPollKeepsIdsCommittedAfterTheHorizon commits event 2 right after its transaction is seen ended
+func TestPollKeepsIdsCommittedAfterTheHorizon(t *testing.T) {
+	events := &fakeActivityEventService{xmin: 10, xmax: 12}
+	h := NewHub(time.Second, time.Hour, events).(*hub)
+	sub := h.Subscribe("activity")
+	defer sub.Close()
+
+	events.commit("activity", 1, 3)
+	lastId := h.poll(context.Background(), 0)
+
+	events.endTransactions()
+	lastId = h.poll(context.Background(), lastId)
+	events.commit("activity", 2)
+
+	lastId = h.poll(context.Background(), lastId)
+	if got := received(sub); lastId != 3 || !equalIds(got, 1, 2, 3) {
+		t.Fatalf("broadcast %v up to %d, want event 2 kept", got, lastId)
+	}
+}
+
+// TestPollGapTimeout gives up on a missing id after the gap timeout when the horizon can't be read
+func TestPollGapTimeout(t *testing.T) {
+	events := &fakeActivityEventService{horizonErr: errors.New("connection refused")}
+	h := NewHub(time.Second, 20*time.Millisecond, events).(*hub)
+	sub := h.Subscribe("activity")
+	defer sub.Close()
+
+	events.commit("activity", 1, 3)
+	lastId := h.poll(context.Background(), 0)
+	if lastId != 1 {
+		t.Fatalf("polled up to %d before the gap timed out", lastId)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	lastId = h.poll(context.Background(), lastId)
+	if got := received(sub); lastId != 3 || !equalIds(got, 1, 3) {
+		t.Fatalf("broadcast %v up to %d, want id 2 given up on", got, lastId)
+	}
+}
+
+func TestBroadcastClosesSlowSubscriber(t *testing.T) {
+	events := &fakeActivityEventService{}
+	h := NewHub(time.Second, time.Hour, events).(*hub)
+	slow := h.Subscribe("activity")
+	other := h.Subscribe("other")
+	defer other.Close()
+
+	for id := int64(1); id <= subscriptionBuffer+1; id++ {
+		h.broadcast(&entity.ActivityEvent{ID: id, ActivityUuid: "activity"})
+	}
+
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-slow.C:
+			if ok {
+				continue
+			}
+			if !errors.Is(slow.Err(), ErrTooSlow) {
+				t.Fatalf("slow subscriber closed with %v, want ErrTooSlow", slow.Err())
+			}
+			if other.Err() != nil {
+				t.Fatalf("unrelated subscriber closed with %v", other.Err())
+			}
+			return
+		case <-deadline:
+			t.Fatal("slow subscriber was never closed")
+		}
+	}
+}
+
+func TestCloseHasNoErr(t *testing.T) {
+	h := NewHub(time.Second, time.Hour, &fakeActivityEventService{}).(*hub)
+	sub := h.Subscribe("activity")
+	sub.Close()
+	sub.Close()
+
+	if _, ok := <-sub.C; ok {
+		t.Fatal("C still open after Close")
+	}
+	if sub.Err() != nil {
+		t.Fatalf("closed subscription reports %v", sub.Err())
+	}
+}
+
+func TestShutdownClosesSubscriptions(t *testing.T) {
+	h := NewHub(time.Second, time.Hour, &fakeActivityEventService{}).(*hub)
+	before := h.Subscribe("activity")
+
+	h.Shutdown()
+	after := h.Subscribe("activity")
+
+	for _, sub := range []*Subscription{before, after} {
+		if _, ok := <-sub.C; ok {
+			t.Fatal("C still open after Shutdown")
+		}
+		if !errors.Is(sub.Err(), ErrShutdown) {
+			t.Fatalf("subscription closed with %v, want ErrShutdown", sub.Err())
+		}
+	}
+}
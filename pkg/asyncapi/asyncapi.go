@@ -0,0 +1,257 @@
+package asyncapi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+)
+
+// Action describes one value of the action field accepted on a <queue>.request queue
+type Action struct {
+	Name    string
+	Summary string
+	// Payload is the dto decoded from the data field
+	Payload any
+	// Reply is the suffix of the queue the result is published to, e.g. created
+	Reply string
+	// Response is published as is on the reply queue
+	Response any
+	// Version is the version of Payload, 1 when unset
+	Version int
+	// Legacy lists the older versions still accepted, they are upcast to Payload once received
+	Legacy []LegacyVersion
+}
+
+// LegacyVersion is an older payload of an action accepted from producers that haven't upgraded yet
+type LegacyVersion struct {
+	Version int
+	// Payload is the dto of this version, for the documentation
+	Payload any
+	// Upcast converts data of this version to the next one
+	Upcast func(data map[string]any) (map[string]any, error)
+	// Deprecation tells producers until when the version is accepted, e.g. "removed after 2027-06-30"
+	Deprecation string
+}
+
+type Document struct {
+	AsyncAPI           string              `json:"asyncapi"`
+	Info               openapi.Info        `json:"info"`
+	DefaultContentType string              `json:"defaultContentType"`
+	Channels           map[string]*Channel `json:"channels"`
+	Components         Components          `json:"components"`
+
+	registry *openapi.Registry
+	payloads map[string]map[string]*openapi.Schema
+	versions map[string]map[string]actionVersions
+}
+
+type actionVersions struct {
+	current int
+	legacy  map[int]LegacyVersion
+}
+
+type Channel struct {
+	Description string     `json:"description,omitempty"`
+	Publish     *Operation `json:"publish,omitempty"`
+	Subscribe   *Operation `json:"subscribe,omitempty"`
+}
+
+type Operation struct {
+	OperationId string  `json:"operationId"`
+	Summary     string  `json:"summary,omitempty"`
+	Message     Message `json:"message"`
+}
+
+type Message struct {
+	Ref         string          `json:"$ref,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Summary     string          `json:"summary,omitempty"`
+	ContentType string          `json:"contentType,omitempty"`
+	Headers     *openapi.Schema `json:"headers,omitempty"`
+	Payload     *openapi.Schema `json:"payload,omitempty"`
+	OneOf       []Message       `json:"oneOf,omitempty"`
+}
+
+type Components struct {
+	Messages map[string]*Message        `json:"messages"`
+	Schemas  map[string]*openapi.Schema `json:"schemas"`
+}
+
+func NewDocument(title string, version string) *Document {
+	d := &Document{
+		AsyncAPI:           "2.6.0",
+		Info:               openapi.Info{Title: title, Version: version},
+		DefaultContentType: "application/json",
+		Channels:           map[string]*Channel{},
+		Components: Components{
+			Messages: map[string]*Message{},
+			Schemas:  map[string]*openapi.Schema{},
+		},
+		payloads: map[string]map[string]*openapi.Schema{},
+		versions: map[string]map[string]actionVersions{},
+	}
+	d.registry = openapi.NewRegistry(d.Components.Schemas)
+
+	return d
+}
+
+// AddQueue documents the request queue of a worker along with its reply, error and dead letter queues
+func (d *Document) AddQueue(queueName string, actions ...Action) *Document {
+	if d.payloads[queueName] == nil {
+		d.payloads[queueName] = map[string]*openapi.Schema{}
+		d.versions[queueName] = map[string]actionVersions{}
+	}
+
+	requests := []Message{}
+	for _, action := range actions {
+		if action.Version == 0 {
+			action.Version = 1
+		}
+		versions := actionVersions{current: action.Version, legacy: map[int]LegacyVersion{}}
+		for _, legacy := range action.Legacy {
+			versions.legacy[legacy.Version] = legacy
+		}
+		d.versions[queueName][action.Name] = versions
+
+		data := d.registry.SchemaOf(action.Payload)
+		d.payloads[queueName][action.Name] = data
+
+		name := fmt.Sprintf("%s.%s", queueName, action.Name)
+		d.Components.Messages[name] = requestMessage(name, action.Summary, action.Name, action.Version, data)
+		requests = append(requests, Message{Ref: "#/components/messages/" + name})
+
+		for _, legacy := range action.Legacy {
+			legacyName := fmt.Sprintf("%s.v%d", name, legacy.Version)
+			summary := fmt.Sprintf("%s, version %d deprecated", action.Summary, legacy.Version)
+			if legacy.Deprecation != "" {
+				summary += ", " + legacy.Deprecation
+			}
+			d.Components.Messages[legacyName] = requestMessage(legacyName, summary, action.Name, legacy.Version, d.registry.SchemaOf(legacy.Payload))
+			requests = append(requests, Message{Ref: "#/components/messages/" + legacyName})
+		}
+
+		if action.Reply == "" {
+			continue
+		}
+
+		d.AddReply(queueName, action.Reply, fmt.Sprintf("Result of a successful %s action", action.Name), action.Response)
+	}
+
+	d.Channels[queueName+".request"] = &Channel{
+		Description: "Durable queue consumed by the worker, the action and version fields select the payload schema of data",
+		Publish: &Operation{
+			OperationId: operationId(queueName, "request"),
+			Message:     Message{OneOf: requests},
+		},
+	}
+
+	d.Channels[queueName+".dead-letter"] = &Channel{
+		Description: "Durable queue keeping the requests whose handling panicked as they were received, with the panic in the x-panic header",
+		Subscribe: &Operation{
+			OperationId: operationId(queueName, "dead-letter"),
+			Message:     Message{OneOf: requests},
+		},
+	}
+
+	errName := queueName + ".error"
+	d.Components.Messages[errName] = &Message{
+		Name: errName,
+		Payload: &openapi.Schema{
+			Type:     "object",
+			Required: []string{"action", "code", "error"},
+			Properties: map[string]*openapi.Schema{
+				"action": {Type: "string"},
+				"code":   {Type: "string", Description: "stable machine readable error code, e.g. TODO_ITEM_NOT_FOUND"},
+				"error":  {Type: "string"},
+				"errors": {
+					Type:                 "object",
+					Description:          "validation errors keyed by field",
+					AdditionalProperties: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}},
+				},
+			},
+		},
+	}
+	d.Channels[errName] = &Channel{
+		Description: "Failed or rejected requests",
+		Subscribe: &Operation{
+			OperationId: operationId(queueName, "error"),
+			Message:     Message{Ref: "#/components/messages/" + errName},
+		},
+	}
+
+	return d
+}
+
+// AddReply documents a queue the worker of queueName publishes responses to, named <queue>.<reply>
+func (d *Document) AddReply(queueName string, reply string, description string, response any) *Document {
+	name := fmt.Sprintf("%s.%s", queueName, reply)
+	d.Components.Messages[name] = &Message{
+		Name:    name,
+		Payload: d.registry.SchemaOf(response),
+	}
+	d.Channels[name] = &Channel{
+		Description: description,
+		Subscribe: &Operation{
+			OperationId: operationId(queueName, reply),
+			Message:     Message{Ref: "#/components/messages/" + name},
+		},
+	}
+
+	return d
+}
+
+// AddChannel documents a queue the app publishes events to outside of any worker, e.g. notifications
+func (d *Document) AddChannel(queueName string, description string, message any) *Document {
+	d.Components.Messages[queueName] = &Message{
+		Name:    queueName,
+		Payload: d.registry.SchemaOf(message),
+	}
+	d.Channels[queueName] = &Channel{
+		Description: description,
+		Subscribe: &Operation{
+			OperationId: queueName,
+			Message:     Message{Ref: "#/components/messages/" + queueName},
+		},
+	}
+
+	return d
+}
+
+func requestMessage(name string, summary string, action string, version int, data *openapi.Schema) *Message {
+	return &Message{
+		Name:        name,
+		Summary:     summary,
+		ContentType: "application/json",
+		Headers: &openapi.Schema{
+			Type: "object",
+			Properties: map[string]*openapi.Schema{
+				"locale": {Type: "string", Description: "language of the error messages published on the error queue, e.g. en or id"},
+			},
+		},
+		Payload: &openapi.Schema{
+			Type:     "object",
+			Required: []string{"action", "data"},
+			Properties: map[string]*openapi.Schema{
+				"action":  {Type: "string", Enum: []any{action}},
+				"version": {Type: "integer", Enum: []any{version}, Description: "version of the data schema, 1 when omitted"},
+				"data":    data,
+			},
+		},
+	}
+}
+
+// Actions lists the actions accepted on the request queue of queueName
+func (d *Document) Actions(queueName string) []string {
+	actions := []string{}
+	for name := range d.payloads[queueName] {
+		actions = append(actions, name)
+	}
+	sort.Strings(actions)
+
+	return actions
+}
+
+func operationId(queueName string, suffix string) string {
+	return fmt.Sprintf("%s.%s", queueName, suffix)
+}
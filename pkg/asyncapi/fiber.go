@@ -0,0 +1,9 @@
+package asyncapi
+
+import "github.com/gofiber/fiber/v2"
+
+func FiberSpecHandler(d *Document) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(d)
+	}
+}
@@ -0,0 +1,74 @@
+package asyncapi
+
+import (
+	"fmt"
+	"sort"
+)
+
+type VersionError struct {
+	Action    string
+	Version   int
+	Supported []int
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("unsupported %s version %d, supported versions are %v", e.Action, e.Version, e.Supported)
+}
+
+// Version is the current version of the payload of action, 0 for unknown actions
+func (d *Document) Version(queueName string, action string) int {
+	return d.versions[queueName][action].current
+}
+
+// Deprecation describes the deprecation of a legacy version of action, empty when the version isn't legacy
+func (d *Document) Deprecation(queueName string, action string, version int) string {
+	legacy, ok := d.versions[queueName][action].legacy[version]
+	if !ok {
+		return ""
+	}
+	if legacy.Deprecation == "" {
+		return "deprecated"
+	}
+
+	return legacy.Deprecation
+}
+
+// Upcast converts data of version, 1 when 0, to the current payload of action by chaining the upcasters
+// of the versions in between. Unknown actions are returned as is for Validate to reject.
+func (d *Document) Upcast(queueName string, action string, version int, data map[string]any) (map[string]any, error) {
+	versions, ok := d.versions[queueName][action]
+	if !ok {
+		return data, nil
+	}
+
+	if version == 0 {
+		version = 1
+	}
+	if version > versions.current {
+		return nil, d.versionError(action, version, versions)
+	}
+
+	for v := version; v < versions.current; v++ {
+		legacy, ok := versions.legacy[v]
+		if !ok {
+			return nil, d.versionError(action, version, versions)
+		}
+
+		var err error
+		if data, err = legacy.Upcast(data); err != nil {
+			return nil, fmt.Errorf("upcasting %s version %d: %w", action, v, err)
+		}
+	}
+
+	return data, nil
+}
+
+func (d *Document) versionError(action string, version int, versions actionVersions) error {
+	supported := []int{versions.current}
+	for v := range versions.legacy {
+		supported = append(supported, v)
+	}
+	sort.Ints(supported)
+
+	return &VersionError{Action: action, Version: version, Supported: supported}
+}
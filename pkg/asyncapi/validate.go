@@ -0,0 +1,153 @@
+package asyncapi
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+)
+
+type ValidationError struct {
+	Action string
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s payload: %s", e.Action, strings.Join(e.Errors, "; "))
+}
+
+// Validate checks the data field of a request message against the schema of its action
+func (d *Document) Validate(queueName string, action string, data map[string]any) error {
+	schema, ok := d.payloads[queueName][action]
+	if !ok {
+		return &ValidationError{
+			Action: action,
+			Errors: []string{fmt.Sprintf("action must be one of [%s]", strings.Join(d.Actions(queueName), " "))},
+		}
+	}
+
+	if data == nil {
+		return &ValidationError{Action: action, Errors: []string{"data is required"}}
+	}
+
+	errs := d.validate("data", schema, data)
+	if len(errs) > 0 {
+		return &ValidationError{Action: action, Errors: errs}
+	}
+
+	return nil
+}
+
+func (d *Document) validate(path string, s *openapi.Schema, v any) []string {
+	s = d.registry.Resolve(s)
+	if s == nil {
+		return nil
+	}
+
+	if v == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s must not be null", path)}
+	}
+
+	errs := []string{}
+	for _, sub := range s.AllOf {
+		errs = append(errs, d.validate(path, sub, v)...)
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return append(errs, fmt.Sprintf("%s must be an object", path))
+		}
+
+		// encoding/json matches keys case insensitively, so does the validation
+		keys := map[string]any{}
+		for k, val := range obj {
+			keys[strings.ToLower(k)] = val
+		}
+
+		for _, name := range s.Required {
+			if _, ok := keys[strings.ToLower(name)]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.%s is required", path, name))
+			}
+		}
+		for name, prop := range s.Properties {
+			if val, ok := keys[strings.ToLower(name)]; ok {
+				errs = append(errs, d.validate(path+"."+name, prop, val)...)
+			}
+		}
+		if s.AdditionalProperties != nil {
+			for k, val := range obj {
+				errs = append(errs, d.validate(path+"."+k, s.AdditionalProperties, val)...)
+			}
+		}
+	case "array":
+		items, ok := v.([]any)
+		if !ok {
+			return append(errs, fmt.Sprintf("%s must be an array", path))
+		}
+		if s.MinItems != nil && len(items) < *s.MinItems {
+			errs = append(errs, fmt.Sprintf("%s must have at least %d items", path, *s.MinItems))
+		}
+		if s.MaxItems != nil && len(items) > *s.MaxItems {
+			errs = append(errs, fmt.Sprintf("%s must have at most %d items", path, *s.MaxItems))
+		}
+		for i, item := range items {
+			errs = append(errs, d.validate(fmt.Sprintf("%s[%d]", path, i), s.Items, item)...)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return append(errs, fmt.Sprintf("%s must be a string", path))
+		}
+		if s.MinLength != nil && len([]rune(str)) < *s.MinLength {
+			errs = append(errs, fmt.Sprintf("%s must be at least %d characters", path, *s.MinLength))
+		}
+		if s.MaxLength != nil && len([]rune(str)) > *s.MaxLength {
+			errs = append(errs, fmt.Sprintf("%s must be at most %d characters", path, *s.MaxLength))
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				errs = append(errs, fmt.Sprintf("%s must be an RFC 3339 date-time", path))
+			}
+		}
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok {
+			return append(errs, fmt.Sprintf("%s must be a %s", path, s.Type))
+		}
+		if s.Type == "integer" && n != math.Trunc(n) {
+			errs = append(errs, fmt.Sprintf("%s must be an integer", path))
+		}
+		if s.Minimum != nil && n < *s.Minimum {
+			errs = append(errs, fmt.Sprintf("%s must be at least %v", path, *s.Minimum))
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			errs = append(errs, fmt.Sprintf("%s must be at most %v", path, *s.Maximum))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return append(errs, fmt.Sprintf("%s must be a boolean", path))
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s must be one of %v", path, s.Enum))
+		}
+	}
+
+	return errs
+}
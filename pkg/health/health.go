@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check reports a dependency as healthy by returning nil
+type Check func(ctx context.Context) error
+
+// Checker runs its checks concurrently, each bounded by the checker timeout
+type Checker interface {
+	Add(name string, check Check) Checker
+	Run(ctx context.Context) Report
+}
+
+type checker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	names  []string
+	checks map[string]Check
+}
+
+// NewChecker bounds each check by timeout, zero leaves them unbounded
+func NewChecker(timeout time.Duration) Checker {
+	return &checker{
+		timeout: timeout,
+		checks:  map[string]Check{},
+	}
+}
+
+func (c *checker) Add(name string, check Check) Checker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+
+	return c
+}
+
+func (c *checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	report := Report{
+		Status: StatusUp,
+		Checks: map[string]CheckResult{},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range c.names {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			result := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(name, c.checks[name])
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (c *checker) run(ctx context.Context, check Check) CheckResult {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check(ctx)
+	}()
+
+	// a check that ignores its context still can't hold the report past the timeout
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Status:     StatusUp,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// StatusCode is 200 when every check is up and 503 otherwise
+func (r Report) StatusCode() int {
+	if r.Status != StatusUp {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
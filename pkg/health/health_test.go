@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRunReportsEveryCheck(t *testing.T) {
+	c := NewChecker(time.Second).
+		Add("database", func(ctx context.Context) error { return nil }).
+		Add("rabbitmq", func(ctx context.Context) error { return errors.New("connection closed") })
+
+	report := c.Run(context.Background())
+	if report.Status != StatusDown || report.StatusCode() != http.StatusServiceUnavailable {
+		t.Fatalf("status %s, %d, want down", report.Status, report.StatusCode())
+	}
+	if report.Checks["database"].Status != StatusUp {
+		t.Errorf("database is %s", report.Checks["database"].Status)
+	}
+	if result := report.Checks["rabbitmq"]; result.Status != StatusDown || result.Error != "connection closed" {
+		t.Errorf("rabbitmq is %+v", result)
+	}
+}
+
+func TestRunBoundsChecksIgnoringTheirContext(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	c := NewChecker(20*time.Millisecond).Add("stuck", func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	start := time.Now()
+	report := c.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("report took %s", elapsed)
+	}
+	if result := report.Checks["stuck"]; result.Status != StatusDown || result.Error != context.DeadlineExceeded.Error() {
+		t.Fatalf("stuck check is %+v, want down on the timeout", result)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler(NewChecker(time.Second).Add("database", func(ctx context.Context) error { return nil }))(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decoding: %s", err)
+	}
+	if report.Status != StatusUp || report.Checks["database"].Status != StatusUp {
+		t.Fatalf("report %+v", report)
+	}
+}
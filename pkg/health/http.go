@@ -0,0 +1,17 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Handler serves the report of c as JSON, for binaries without a Fiber app
+func Handler(c Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(report.StatusCode())
+		json.NewEncoder(w).Encode(report)
+	}
+}
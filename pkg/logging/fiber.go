@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+// FiberMiddleware tags the request with the X-Request-ID it came with, or a new one, and logs it once done.
+// done is called after the access log line, with the response written.
+func FiberMiddleware(done func(c *fiber.Ctx)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		requestId := RequestId(c.Get(RequestIdHeader))
+		c.Set(RequestIdHeader, requestId)
+		c.Locals(RequestIdLocalsKey, requestId)
+		c.SetUserContext(WithFields(c.UserContext(), log.Fields{"request_id": requestId}))
+
+		// render the error now so the line has the status the client gets
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				c.Status(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		entry := FromFiber(c).WithFields(log.Fields{
+			"method":      c.Method(),
+			"path":        c.Path(),
+			"status":      status,
+			"duration_ms": time.Since(start).Milliseconds(),
+		})
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			entry.Error("request failed")
+		default:
+			entry.Info("request")
+		}
+
+		if done != nil {
+			done(c)
+		}
+
+		return nil
+	}
+}
+
+// FromFiber is the logger of the request, with its route and route parameters such as activity_uuid
+func FromFiber(c *fiber.Ctx) *log.Entry {
+	route := c.Route()
+
+	fields := log.Fields{"route": route.Path}
+	for _, param := range route.Params {
+		fields[param] = c.Params(param)
+	}
+
+	return FromContext(c.UserContext()).WithFields(fields)
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	FormatJson = "json"
+	FormatText = "text"
+
+	RequestIdHeader = "X-Request-ID"
+	// RequestIdLocalsKey keeps the request id in the fiber locals, for handlers such as websockets outliving the request context
+	RequestIdLocalsKey = "request_id"
+)
+
+type fieldsKey struct{}
+
+// WithFields adds fields to the logger of ctx, every later log line on ctx carries them
+func WithFields(ctx context.Context, fields log.Fields) context.Context {
+	merged := log.Fields{}
+	if parent, ok := ctx.Value(fieldsKey{}).(log.Fields); ok {
+		for k, v := range parent {
+			merged[k] = v
+		}
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FromContext is the logger of ctx, with the fields added along the way and the trace id of its span
+func FromContext(ctx context.Context) *log.Entry {
+	entry := log.NewEntry(log.StandardLogger())
+	if fields, ok := ctx.Value(fieldsKey{}).(log.Fields); ok {
+		entry = entry.WithFields(fields)
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		entry = entry.WithFields(log.Fields{
+			"trace_id": sc.TraceID().String(),
+			"span_id":  sc.SpanID().String(),
+		})
+	}
+
+	return entry
+}
+
+// RequestId is id when the caller sent one, a new one otherwise
+func RequestId(id string) string {
+	if id != "" {
+		return id
+	}
+
+	return uuid.NewString()
+}
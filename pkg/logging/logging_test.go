@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestWithFieldsKeepsParent(t *testing.T) {
+	parent := WithFields(context.Background(), log.Fields{"request_id": "request", "user": "alice"})
+	child := WithFields(parent, log.Fields{"user": "bob", "action": "update"})
+
+	if fields := FromContext(parent).Data; fields["user"] != "alice" || fields["action"] != nil {
+		t.Fatalf("parent fields %v changed", fields)
+	}
+	if fields := FromContext(child).Data; fields["request_id"] != "request" || fields["user"] != "bob" || fields["action"] != "update" {
+		t.Fatalf("child fields %v", fields)
+	}
+}
+
+func TestFiberMiddleware(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	done := 0
+	r := fiber.New()
+	r.Use(FiberMiddleware(func(c *fiber.Ctx) { done++ }))
+	r.Get("/activity-groups/:activity_uuid", func(c *fiber.Ctx) error {
+		FromFiber(c).Info("handling")
+		return c.SendStatus(http.StatusNoContent)
+	})
+	r.Get("/failing", func(c *fiber.Ctx) error {
+		return errors.New("database unreachable")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/activity-groups/activity", nil)
+	req.Header.Set(RequestIdHeader, "request")
+	resp, err := r.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Header.Get(RequestIdHeader) != "request" {
+		t.Fatalf("answered request id %q, want the one sent", resp.Header.Get(RequestIdHeader))
+	}
+
+	entries := hook.AllEntries()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d lines, want the handler and the access line", len(entries))
+	}
+	if fields := entries[0].Data; fields["request_id"] != "request" || fields["activity_uuid"] != "activity" || fields["route"] != "/activity-groups/:activity_uuid" {
+		t.Errorf("handler line fields %v", fields)
+	}
+	if access := entries[1]; access.Level != log.InfoLevel || access.Data["status"] != http.StatusNoContent || access.Data["request_id"] != "request" {
+		t.Errorf("access line %s %v", access.Level, access.Data)
+	}
+
+	hook.Reset()
+	resp, err = r.Test(httptest.NewRequest(http.MethodGet, "/failing", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError || resp.Header.Get(RequestIdHeader) == "" {
+		t.Fatalf("status %d, request id %q, want a 500 with a new request id", resp.StatusCode, resp.Header.Get(RequestIdHeader))
+	}
+	if access := hook.LastEntry(); access == nil || access.Level != log.ErrorLevel || access.Data["status"] != http.StatusInternalServerError {
+		t.Fatalf("access line %v, want the failure logged as an error", access)
+	}
+
+	if done != 2 {
+		t.Fatalf("done called %d times, want once per request", done)
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// TodoItemCounter counts the todo items of every activity group by activity group uuid
+type TodoItemCounter func(ctx context.Context) (map[string]int, error)
+
+type domainCollector struct {
+	countTodoItems TodoItemCounter
+
+	activityGroups *prometheus.Desc
+	todoItems      *prometheus.Desc
+}
+
+// NewDomainCollector queries the counts on every scrape, so they're never stale
+func NewDomainCollector(countTodoItems TodoItemCounter) prometheus.Collector {
+	return &domainCollector{
+		countTodoItems: countTodoItems,
+		activityGroups: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "activity_groups"),
+			"Number of activity groups.",
+			nil, nil,
+		),
+		todoItems: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "todo_items"),
+			"Number of todo items by activity group.",
+			[]string{"activity_uuid"}, nil,
+		),
+	}
+}
+
+func (c *domainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.activityGroups
+	ch <- c.todoItems
+}
+
+func (c *domainCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.countTodoItems(context.Background())
+	if err != nil {
+		log.Errorf("[metrics] counting todo items: %s", err)
+		ch <- prometheus.NewInvalidMetric(c.todoItems, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.activityGroups, prometheus.GaugeValue, float64(len(counts)))
+	for activityUuid, total := range counts {
+		ch <- prometheus.MustNewConstMetric(c.todoItems, prometheus.GaugeValue, float64(total), activityUuid)
+	}
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// FiberMiddleware counts requests and observes their latency by method, route pattern and status,
+// the route pattern keeps ids out of the labels
+func FiberMiddleware(reg prometheus.Registerer) fiber.Handler {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	reg.MustRegister(requests, duration)
+
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		// render the error now so the status is the one the client gets
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				c.Status(fiber.StatusInternalServerError)
+			}
+		}
+
+		labels := []string{c.Method(), c.Route().Path, strconv.Itoa(c.Response().StatusCode())}
+		requests.WithLabelValues(labels...).Inc()
+		duration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+		return nil
+	}
+}
+
+// FiberHandler serves the metrics of reg from a Fiber app
+func FiberHandler(reg *prometheus.Registry) fiber.Handler {
+	handler := fasthttpadaptor.NewFastHTTPHandler(Handler(reg))
+
+	return func(c *fiber.Ctx) error {
+		handler(c.Context())
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "todoapp"
+
+// NewRegistry collects the Go runtime and process metrics on top of whatever the app registers
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return reg
+}
+
+// RegisterDB exposes the pool statistics of db labelled with name, e.g. primary or replica
+func RegisterDB(reg prometheus.Registerer, name string, db *sql.DB) {
+	reg.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics of reg in the Prometheus text format
+func Handler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})
+}
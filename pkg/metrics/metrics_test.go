@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestFiberMiddlewareLabelsRoutePattern(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	r := fiber.New()
+	r.Use(FiberMiddleware(reg))
+	r.Get("/activity-groups/:uuid", func(c *fiber.Ctx) error {
+		return fiber.ErrNotFound
+	})
+
+	for _, uuid := range []string{"work", "home"} {
+		if _, err := r.Test(httptest.NewRequest(http.MethodGet, "/activity-groups/"+uuid, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// both requests share one series, labelled with the status the error handler answered
+	want := `
+# HELP todoapp_http_requests_total HTTP requests by method, route and status.
+# TYPE todoapp_http_requests_total counter
+todoapp_http_requests_total{method="GET",route="/activity-groups/:uuid",status="404"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), "todoapp_http_requests_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueueMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewQueueMetrics(reg)
+
+	m.Observe("todo-item", "create", time.Now(), nil)
+	m.Observe("todo-item", "create", time.Now(), errors.New("validation failed"))
+	m.Panicked("todo-item")
+
+	if got := testutil.ToFloat64(m.messages.WithLabelValues("todo-item", "create")); got != 2 {
+		t.Errorf("messages %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.failures.WithLabelValues("todo-item", "create")); got != 1 {
+		t.Errorf("failures %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.panics.WithLabelValues("todo-item")); got != 1 {
+		t.Errorf("panics %v, want 1", got)
+	}
+}
+
+func TestDomainCollector(t *testing.T) {
+	c := NewDomainCollector(func(ctx context.Context) (map[string]int, error) {
+		return map[string]int{"work": 3, "home": 0}, nil
+	})
+
+	want := `
+# HELP todoapp_activity_groups Number of activity groups.
+# TYPE todoapp_activity_groups gauge
+todoapp_activity_groups 2
+# HELP todoapp_todo_items Number of todo items by activity group.
+# TYPE todoapp_todo_items gauge
+todoapp_todo_items{activity_uuid="home"} 0
+todoapp_todo_items{activity_uuid="work"} 3
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Fatal(err)
+	}
+
+	// a failing count fails the scrape instead of reporting stale or zero counts
+	failing := NewDomainCollector(func(ctx context.Context) (map[string]int, error) {
+		return nil, errors.New("database unreachable")
+	})
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(failing)
+	if _, err := reg.Gather(); err == nil {
+		t.Fatal("failing count collected")
+	}
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// QueueMetrics counts the messages handled by the queue workers and their failures by worker and action
+type QueueMetrics struct {
+	messages *prometheus.CounterVec
+	failures *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	panics   *prometheus.CounterVec
+}
+
+func NewQueueMetrics(reg prometheus.Registerer) *QueueMetrics {
+	m := &QueueMetrics{
+		messages: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "queue",
+			Name:      "messages_total",
+			Help:      "Queue messages handled by worker and action.",
+		}, []string{"worker", "action"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "queue",
+			Name:      "message_failures_total",
+			Help:      "Queue messages answered on the error queue by worker and action.",
+		}, []string{"worker", "action"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "queue",
+			Name:      "message_duration_seconds",
+			Help:      "Time spent handling a queue message by worker and action.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"worker", "action"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "queue",
+			Name:      "message_panics_total",
+			Help:      "Queue messages whose handling panicked by worker.",
+		}, []string{"worker"}),
+	}
+	reg.MustRegister(m.messages, m.failures, m.duration, m.panics)
+
+	return m
+}
+
+// Observe records a message handled since start, err is the error answered on the error queue
+func (m *QueueMetrics) Observe(worker string, action string, start time.Time, err error) {
+	m.messages.WithLabelValues(worker, action).Inc()
+	m.duration.WithLabelValues(worker, action).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.failures.WithLabelValues(worker, action).Inc()
+	}
+}
+
+// Panicked records a message whose handling panicked, it is observed as a failure as well
+func (m *QueueMetrics) Panicked(worker string) {
+	m.panics.WithLabelValues(worker).Inc()
+}
@@ -0,0 +1,30 @@
+// Package notify delivers notifications to users over pluggable channels such as email, webhooks and queues.
+package notify
+
+import "context"
+
+// Recipient holds the addresses of a user on every channel, a channel only reads its own
+type Recipient struct {
+	User          string
+	Email         string
+	WebhookUrl    string
+	WebhookSecret string
+}
+
+type Message struct {
+	// Id identifies the notification, it is repeated on every attempt so receivers can drop duplicates
+	Id        string
+	Event     string
+	Recipient Recipient
+	// Subject and Text are the human readable notification, for channels such as email
+	Subject string
+	Text    string
+	// Body is the JSON notification, for machine readable channels such as webhooks and queues
+	Body []byte
+}
+
+type Channel interface {
+	Name() string
+	// Send returns once the notification was handed over to the channel, any error is retried by the caller
+	Send(ctx context.Context, msg Message) error
+}
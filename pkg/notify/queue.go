@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/Adhiana46/go-restapi-template/pkg/rabbitmq"
+	"github.com/Adhiana46/go-restapi-template/pkg/tracing"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// HeaderUser carries the user a queued notification is for, consumers can route on it without decoding the body
+const HeaderUser = "x-notification-user"
+
+type queueChannel struct {
+	publisher rabbitmq.Publisher
+	queue     rabbitmq.Queue
+}
+
+// NewQueueChannel publishes the JSON body of every notification on the durable queue queueName,
+// the message type is the event and the message id the one of the notification
+func NewQueueChannel(publisher rabbitmq.Publisher, queueName string) Channel {
+	return &queueChannel{
+		publisher: publisher,
+		queue:     rabbitmq.Queue{Name: queueName, Durable: true},
+	}
+}
+
+func (c *queueChannel) Name() string {
+	return "queue"
+}
+
+func (c *queueChannel) Send(ctx context.Context, msg Message) (err error) {
+	ctx, span, headers := tracing.StartPublish(ctx, c.queue.Name)
+	defer func() { tracing.End(span, err) }()
+
+	headers[HeaderUser] = msg.Recipient.User
+
+	return c.publisher.Publish(ctx, c.queue, amqp.Publishing{
+		Headers:     headers,
+		ContentType: "application/json",
+		MessageId:   msg.Id,
+		Type:        msg.Event,
+		Body:        msg.Body,
+	})
+}
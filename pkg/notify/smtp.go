@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+type SmtpOptions struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+type smtpChannel struct {
+	opts SmtpOptions
+}
+
+// NewSmtpChannel sends plain text emails, upgrading the connection with STARTTLS when the server offers it
+// and authenticating when a username is set
+func NewSmtpChannel(opts SmtpOptions) Channel {
+	return &smtpChannel{
+		opts: opts,
+	}
+}
+
+func (c *smtpChannel) Name() string {
+	return "email"
+}
+
+func (c *smtpChannel) Send(ctx context.Context, msg Message) error {
+	if msg.Recipient.Email == "" {
+		return errors.New("recipient has no email address")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(c.opts.Host, c.opts.Port))
+	if err != nil {
+		return err
+	}
+	// net/smtp isn't context aware, the deadline bounds the whole conversation instead
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.opts.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.opts.Host}); err != nil {
+			return err
+		}
+	}
+	if c.opts.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.opts.Username, c.opts.Password, c.opts.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(c.opts.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.Recipient.Email); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(c.compose(msg)); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// compose formats msg as a quoted-printable text email, the subject is encoded so it can't inject headers
+func (c *smtpChannel) compose(msg Message) []byte {
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, "From: %s\r\n", c.opts.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.Recipient.Email)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", msg.Id, c.opts.Host)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	qp.Write([]byte(msg.Text))
+	qp.Close()
+
+	return buf.Bytes()
+}
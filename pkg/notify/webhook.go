@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Adhiana46/go-restapi-template/pkg/webhook"
+)
+
+type webhookChannel struct {
+	sender webhook.Sender
+}
+
+// NewWebhookChannel POSTs the JSON body signed with the secret of the recipient, as webhook endpoints are
+func NewWebhookChannel(sender webhook.Sender) Channel {
+	return &webhookChannel{
+		sender: sender,
+	}
+}
+
+func (c *webhookChannel) Name() string {
+	return "webhook"
+}
+
+func (c *webhookChannel) Send(ctx context.Context, msg Message) error {
+	if msg.Recipient.WebhookUrl == "" {
+		return errors.New("recipient has no webhook url")
+	}
+
+	_, err := c.sender.Send(ctx, msg.Recipient.WebhookUrl, msg.Recipient.WebhookSecret, msg.Event, msg.Id, msg.Body)
+
+	return err
+}
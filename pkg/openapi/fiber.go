@@ -0,0 +1,26 @@
+package openapi
+
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:embed swagger.html
+var swaggerHtml string
+
+func FiberSpecHandler(d *Document) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(d)
+	}
+}
+
+func FiberUIHandler(specUrl string) fiber.Handler {
+	page := strings.ReplaceAll(swaggerHtml, "{{SPEC_URL}}", specUrl)
+
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(page)
+	}
+}
@@ -0,0 +1,240 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Operation describes one route, paths use the fiber syntax relative to the router the handler is mounted on
+type Operation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Tags        []string
+	Query       any
+	Body        any
+	Response    any
+	Paginated   bool
+	Status      int
+	ContentType string
+	RawResponse bool
+}
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+
+	registry *Registry
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Server struct {
+	Url string `json:"url"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	Summary     string               `json:"summary,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	OperationId string               `json:"operationId"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+var paramPattern = regexp.MustCompile(`:([A-Za-z0-9_]+)\??`)
+
+func NewDocument(title string, version string) *Document {
+	d := &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: title, Version: version},
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+	}
+	d.registry = NewRegistry(d.Components.Schemas)
+	d.registry.registerEnvelope()
+
+	return d
+}
+
+// AddOperations mounts the operations of one handler under prefix
+func (d *Document) AddOperations(prefix string, ops ...Operation) *Document {
+	for _, op := range ops {
+		d.addOperation(prefix, op)
+	}
+
+	return d
+}
+
+// Routes lists "METHOD /path" for every documented operation, in fiber path syntax
+func (d *Document) Routes() []string {
+	routes := []string{}
+	for path, item := range d.Paths {
+		for method := range *item {
+			routes = append(routes, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(routes)
+
+	return routes
+}
+
+func JoinPath(prefix string, path string) string {
+	full := strings.TrimRight(prefix, "/") + "/" + strings.TrimLeft(path, "/")
+	if len(full) > 1 {
+		full = strings.TrimRight(full, "/")
+	}
+
+	return full
+}
+
+func (d *Document) addOperation(prefix string, op Operation) {
+	fiberPath := JoinPath(prefix, op.Path)
+	path := paramPattern.ReplaceAllString(fiberPath, "{$1}")
+
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+
+	obj := &OperationObject{
+		Summary:     op.Summary,
+		Tags:        op.Tags,
+		OperationId: operationId(op.Method, path),
+		Responses:   map[string]*Response{},
+	}
+
+	for _, match := range paramPattern.FindAllStringSubmatch(fiberPath, -1) {
+		obj.Parameters = append(obj.Parameters, &Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	if op.Query != nil {
+		obj.Parameters = append(obj.Parameters, queryParameters(op.Query)...)
+	}
+
+	if op.Body != nil {
+		obj.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: d.registry.BodySchemaOf(op.Body)},
+			},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	obj.Responses[fmt.Sprint(status)] = d.successResponse(op, status)
+	obj.Responses["default"] = &Response{
+		Description: "Error",
+		Content: map[string]*MediaType{
+			"application/json":         {Schema: Ref("JsonError")},
+			"application/problem+json": {Schema: Ref("Problem")},
+		},
+	}
+
+	(*item)[strings.ToLower(op.Method)] = obj
+}
+
+func (d *Document) successResponse(op Operation, status int) *Response {
+	resp := &Response{Description: http.StatusText(status)}
+
+	if status == http.StatusSwitchingProtocols {
+		return resp
+	}
+
+	if op.ContentType != "" {
+		resp.Content = map[string]*MediaType{
+			op.ContentType: {Schema: &Schema{Type: "string"}},
+		}
+		return resp
+	}
+
+	if op.RawResponse {
+		resp.Content = map[string]*MediaType{
+			"application/json": {Schema: d.registry.SchemaOf(op.Response)},
+		}
+		return resp
+	}
+
+	// every other response is wrapped in the responsePkg.JsonResponse envelope
+	envelope := &Schema{
+		AllOf: []*Schema{Ref("JsonResponse")},
+	}
+	properties := map[string]*Schema{}
+	if op.Response != nil {
+		properties["data"] = d.registry.SchemaOf(op.Response)
+	}
+	if op.Paginated {
+		properties["pagination"] = Ref("Pagination")
+	}
+	if len(properties) > 0 {
+		envelope.AllOf = append(envelope.AllOf, &Schema{Type: "object", Properties: properties})
+	}
+
+	resp.Content = map[string]*MediaType{
+		"application/json": {Schema: envelope},
+	}
+
+	return resp
+}
+
+func operationId(method string, path string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, segment := range strings.Split(path, "/") {
+		segment = strings.Trim(segment, "{}")
+		if segment == "" {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			parts = append(parts, strings.ToUpper(word[:1])+word[1:])
+		}
+	}
+
+	return strings.Join(parts, "")
+}
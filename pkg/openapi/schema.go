@@ -0,0 +1,295 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+)
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Registry collects the named struct schemas referenced from other schemas
+type Registry struct {
+	Schemas map[string]*Schema
+}
+
+func NewRegistry(schemas map[string]*Schema) *Registry {
+	return &Registry{
+		Schemas: schemas,
+	}
+}
+
+// Resolve follows a local $ref to the registered schema
+func (d *Registry) Resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = d.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+
+	return s
+}
+
+func (d *Registry) registerEnvelope() {
+	d.Schemas["Pagination"] = d.structSchema(reflect.TypeOf(responsePkg.Pagination{}), "json", false)
+	d.Schemas["JsonResponse"] = &Schema{
+		Type:     "object",
+		Required: []string{"status", "message"},
+		Properties: map[string]*Schema{
+			"status":  {Type: "integer"},
+			"message": {Type: "string"},
+		},
+	}
+	d.Schemas["Problem"] = d.structSchema(reflect.TypeOf(responsePkg.Problem{}), "json", false)
+	d.Schemas["JsonError"] = &Schema{
+		AllOf: []*Schema{
+			Ref("JsonResponse"),
+			{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"code": {Type: "string", Description: "stable machine readable error code, e.g. TODO_ITEM_NOT_FOUND"},
+					"errors": {
+						Type:                 "object",
+						Description:          "validation errors keyed by field",
+						AdditionalProperties: &Schema{Type: "array", Items: &Schema{Type: "string"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// SchemaOf returns the JSON schema of v, registering named structs as components of d
+func (d *Registry) SchemaOf(v any) *Schema {
+	return d.schemaOf(v, "json")
+}
+
+func (d *Registry) schemaOf(v any, tag string) *Schema {
+	return d.typeSchema(reflect.TypeOf(v), tag, false)
+}
+
+// BodySchemaOf only keeps fields with an explicit json tag, uri and query fields are documented as parameters
+func (d *Registry) BodySchemaOf(v any) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	name := t.Name()
+	if _, ok := d.Schemas[name]; !ok {
+		d.Schemas[name] = &Schema{}
+		*d.Schemas[name] = *d.structSchema(t, "json", true)
+	}
+
+	return Ref(name)
+}
+
+func (d *Registry) typeSchema(t reflect.Type, tag string, explicitOnly bool) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := d.typeSchema(t.Elem(), tag, explicitOnly)
+		if s.Ref != "" {
+			return &Schema{AllOf: []*Schema{s}, Nullable: true}
+		}
+		s.Nullable = true
+		return s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.typeSchema(t.Elem(), tag, explicitOnly)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.typeSchema(t.Elem(), tag, explicitOnly)}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t == timeType {
+			return &Schema{Type: "string", Format: "date-time"}
+		}
+		if t.Name() == "" {
+			return d.structSchema(t, tag, explicitOnly)
+		}
+		if _, ok := d.Schemas[t.Name()]; !ok {
+			// reserve the name first so self referencing types terminate
+			d.Schemas[t.Name()] = &Schema{}
+			*d.Schemas[t.Name()] = *d.structSchema(t, tag, explicitOnly)
+		}
+		return Ref(t.Name())
+	}
+
+	return &Schema{}
+}
+
+func (d *Registry) structSchema(t reflect.Type, tag string, explicitOnly bool) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := fieldName(field, tag)
+		if !ok || (explicitOnly && field.Tag.Get(tag) == "") {
+			continue
+		}
+
+		fieldSchema := d.typeSchema(field.Type, tag, false)
+		if applyValidateTag(fieldSchema, field.Type, field.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fieldSchema
+	}
+
+	return s
+}
+
+func queryParameters(v any) []*Parameter {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	params := []*Parameter{}
+	d := NewRegistry(map[string]*Schema{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("query"), ",")[0]
+		if name == "" || field.Tag.Get("uri") != "" {
+			continue
+		}
+
+		schema := d.typeSchema(field.Type, "query", false)
+		required := applyValidateTag(schema, field.Type, field.Tag.Get("validate"))
+		params = append(params, &Parameter{
+			Name:     name,
+			In:       "query",
+			Required: required,
+			Schema:   schema,
+		})
+	}
+
+	return params
+}
+
+func fieldName(field reflect.StructField, tag string) (string, bool) {
+	name := strings.Split(field.Tag.Get(tag), ",")[0]
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		// path bound fields are still decoded from json objects by their (case insensitive) name
+		name = strings.Split(field.Tag.Get("uri"), ",")[0]
+	}
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, true
+}
+
+// applyValidateTag maps validator rules onto schema constraints and reports whether the field is required
+func applyValidateTag(s *Schema, t reflect.Type, tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	required := false
+	target, targetType := s, t
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			if target == s {
+				required = true
+			}
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target, targetType = target.Items, targetType.Elem()
+		case "min", "max", "len":
+			applyBound(target, targetType, name, param)
+		case "url", "uri":
+			target.Format = "uri"
+		case "email":
+			target.Format = "email"
+		case "uuid", "uuid4":
+			target.Format = "uuid"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				target.Enum = append(target.Enum, v)
+			}
+		}
+	}
+
+	return required
+}
+
+func applyBound(s *Schema, t reflect.Type, name string, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+	i := int(n)
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if name == "min" || name == "len" {
+			s.MinLength = &i
+		}
+		if name == "max" || name == "len" {
+			s.MaxLength = &i
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		if name == "min" || name == "len" {
+			s.MinItems = &i
+		}
+		if name == "max" || name == "len" {
+			s.MaxItems = &i
+		}
+	default:
+		if name == "min" || name == "len" {
+			s.Minimum = &n
+		}
+		if name == "max" || name == "len" {
+			s.Maximum = &n
+		}
+	}
+}
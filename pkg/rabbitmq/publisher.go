@@ -0,0 +1,189 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	ErrNacked      = errors.New("publishing nacked by the broker")
+	ErrUnconfirmed = errors.New("publishing not confirmed in time")
+)
+
+// Queue is declared by the publisher the first time it is published to
+type Queue struct {
+	Name    string
+	Durable bool
+}
+
+type PublisherOptions struct {
+	// Channels is the number of idle confirm mode channels kept for reuse
+	Channels       int
+	ConfirmTimeout time.Duration
+	// Retries is the number of attempts after the first one when a publishing fails or isn't confirmed
+	Retries int
+}
+
+// Publisher publishes persistent messages on channels in confirm mode shared through a pool
+type Publisher interface {
+	// Publish returns once the broker confirmed msg, retrying a failed or unconfirmed publishing
+	Publish(ctx context.Context, q Queue, msg amqp.Publishing) error
+	Close() error
+}
+
+type publisher struct {
+	conn *amqp.Connection
+	opts PublisherOptions
+
+	idle chan *amqp.Channel
+
+	mu       sync.Mutex
+	declared map[string]bool
+}
+
+func NewPublisher(conn *amqp.Connection, opts PublisherOptions) Publisher {
+	return &publisher{
+		conn:     conn,
+		opts:     opts,
+		idle:     make(chan *amqp.Channel, opts.Channels),
+		declared: map[string]bool{},
+	}
+}
+
+func (p *publisher) Publish(ctx context.Context, q Queue, msg amqp.Publishing) error {
+	if msg.DeliveryMode == 0 {
+		msg.DeliveryMode = amqp.Persistent
+	}
+
+	var err error
+	for attempt := 0; attempt <= p.opts.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err = p.publish(ctx, q, msg); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+func (p *publisher) publish(ctx context.Context, q Queue, msg amqp.Publishing) error {
+	ch, err := p.acquire()
+	if err != nil {
+		return err
+	}
+
+	if err := p.declare(ch, q); err != nil {
+		ch.Close()
+		return err
+	}
+
+	confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx, "", q.Name, false, false, msg)
+	if err != nil {
+		ch.Close()
+		return err
+	}
+
+	acked := make(chan bool, 1)
+	go func() {
+		acked <- confirm.Wait()
+	}()
+
+	timer := time.NewTimer(p.opts.ConfirmTimeout)
+	defer timer.Stop()
+
+	select {
+	case ok := <-acked:
+		if !ok {
+			// nacked, or the channel closed before the confirmation
+			ch.Close()
+			return ErrNacked
+		}
+		p.release(ch)
+		return nil
+	case <-timer.C:
+		// the late confirmation would be mistaken for the next publishing, closing the channel also ends the wait
+		ch.Close()
+		return ErrUnconfirmed
+	}
+}
+
+// declare declares q once, queues whose declaration failed are declared again on the next publishing
+func (p *publisher) declare(ch *amqp.Channel, q Queue) error {
+	p.mu.Lock()
+	declared := p.declared[q.Name]
+	p.mu.Unlock()
+	if declared {
+		return nil
+	}
+
+	_, err := ch.QueueDeclare(
+		q.Name,    // name
+		q.Durable, // durable
+		false,     // delete when unused
+		false,     // exclusive
+		false,     // no-wait
+		nil,       // arguments
+	)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.declared[q.Name] = true
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *publisher) acquire() (*amqp.Channel, error) {
+	for {
+		select {
+		case ch := <-p.idle:
+			if ch.IsClosed() {
+				continue
+			}
+			return ch, nil
+		default:
+			ch, err := p.conn.Channel()
+			if err != nil {
+				return nil, err
+			}
+			if err := ch.Confirm(false); err != nil {
+				ch.Close()
+				return nil, err
+			}
+			return ch, nil
+		}
+	}
+}
+
+// release keeps ch for the next publishing unless enough channels are idle
+func (p *publisher) release(ch *amqp.Channel) {
+	select {
+	case p.idle <- ch:
+	default:
+		ch.Close()
+	}
+}
+
+func (p *publisher) Close() error {
+	for {
+		select {
+		case ch := <-p.idle:
+			ch.Close()
+		default:
+			return nil
+		}
+	}
+}
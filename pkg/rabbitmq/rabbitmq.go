@@ -10,17 +10,17 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-func OpenConn(cfg *config.Config) (*amqp.Connection, error) {
+func OpenConn(cfg config.AmqpConfig) (*amqp.Connection, error) {
 	var count int64
 	var backOff = 1 * time.Second
 	var connection *amqp.Connection
 
 	dsn := fmt.Sprintf(
 		"amqp://%s:%s@%s:%s/",
-		cfg.AmqpUser,
-		cfg.AmqpPass,
-		cfg.AmqpHost,
-		cfg.AmqpPort,
+		cfg.User,
+		cfg.Pass,
+		cfg.Host,
+		cfg.Port,
 	)
 
 	// Don't continue until rabbit is ready
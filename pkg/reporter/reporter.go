@@ -0,0 +1,214 @@
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Report describes a failure worth alerting on, a failed response or a recovered panic
+type Report struct {
+	// Title summarises the failure, reports with the same title are deduplicated
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	// Stack is the stack trace of panics, empty otherwise
+	Stack string `json:"stack,omitempty"`
+	// Fields is the request context, such as request_id, route, user or trace_id
+	Fields map[string]any `json:"fields,omitempty"`
+	// Suppressed counts the reports with the same title muted since the last one sent
+	Suppressed int       `json:"suppressed,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Sink delivers a report, e.g. to a chat channel or an incident tracker
+type Sink interface {
+	Send(ctx context.Context, r Report) error
+}
+
+type Reporter interface {
+	// Report queues r for the sink without blocking, it may be dropped as a duplicate or over the rate limit
+	Report(r Report)
+	// Close sends the queued reports until ctx is done, reports made after it are dropped
+	Close(ctx context.Context) error
+}
+
+type Options struct {
+	Timeout      time.Duration
+	DedupeWindow time.Duration
+	// RateLimit is the number of reports sent per minute
+	RateLimit int
+}
+
+const queueSize = 64
+
+type reporter struct {
+	sink Sink
+	opts Options
+
+	mu          sync.Mutex
+	seen        map[string]*seenReport
+	windowStart time.Time
+	windowSent  int
+	// closed stops Report from queueing, the queue itself is never closed so a late Report can't panic
+	closed bool
+
+	queue chan Report
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+type seenReport struct {
+	lastSent   time.Time
+	suppressed int
+}
+
+func New(sink Sink, opts Options) Reporter {
+	r := &reporter{
+		sink:  sink,
+		opts:  opts,
+		seen:  map[string]*seenReport{},
+		queue: make(chan Report, queueSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go r.run()
+
+	return r
+}
+
+func (r *reporter) Report(report Report) {
+	if report.OccurredAt.IsZero() {
+		report.OccurredAt = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		log.Warnf("[reporter] closed, dropping report: %s", report.Title)
+		return
+	}
+	if !r.admit(&report) {
+		return
+	}
+
+	select {
+	case r.queue <- report:
+	default:
+		log.Warnf("[reporter] queue full, dropping report: %s", report.Title)
+	}
+}
+
+// admit deduplicates by title and applies the rate limit, the first report of a title
+// after its window carries the number of duplicates muted in between, r.mu must be held
+func (r *reporter) admit(report *Report) bool {
+	now := report.OccurredAt
+	if now.Sub(r.windowStart) >= time.Minute {
+		r.windowStart, r.windowSent = now, 0
+		r.prune(now)
+	}
+
+	seen, ok := r.seen[report.Title]
+	if ok && now.Sub(seen.lastSent) < r.opts.DedupeWindow {
+		seen.suppressed++
+		return false
+	}
+	if r.windowSent >= r.opts.RateLimit {
+		if ok {
+			seen.suppressed++
+		}
+		return false
+	}
+
+	if !ok {
+		seen = &seenReport{}
+		r.seen[report.Title] = seen
+	}
+	report.Suppressed = seen.suppressed
+	seen.lastSent, seen.suppressed = now, 0
+	r.windowSent++
+
+	return true
+}
+
+// prune forgets the titles whose window is over and nothing was muted since
+func (r *reporter) prune(now time.Time) {
+	for title, seen := range r.seen {
+		if seen.suppressed == 0 && now.Sub(seen.lastSent) >= r.opts.DedupeWindow {
+			delete(r.seen, title)
+		}
+	}
+}
+
+// run sends the queued reports, once stopped it sends what is left in the queue and returns
+func (r *reporter) run() {
+	defer close(r.done)
+
+	for {
+		select {
+		case report := <-r.queue:
+			r.send(report)
+		case <-r.stop:
+			for {
+				select {
+				case report := <-r.queue:
+					r.send(report)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *reporter) send(report Report) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.Timeout)
+	defer cancel()
+
+	if err := r.sink.Send(ctx, report); err != nil {
+		log.Errorf("[reporter] sending %q: %s", report.Title, err)
+	}
+}
+
+func (r *reporter) Close(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.stop)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type nopReporter struct{}
+
+// Nop discards every report, used when no sink is configured
+func Nop() Reporter {
+	return nopReporter{}
+}
+
+func (nopReporter) Report(Report) {}
+
+func (nopReporter) Close(context.Context) error {
+	return nil
+}
+
+// Panic describes a recovered panic with the stack of the goroutine, call it from the deferred recover
+func Panic(title string, recovered any, fields map[string]any) Report {
+	return Report{
+		Title:   title,
+		Message: fmt.Sprint(recovered),
+		Stack:   string(debug.Stack()),
+		Fields:  fields,
+	}
+}
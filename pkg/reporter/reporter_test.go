@@ -0,0 +1,102 @@
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeSink struct {
+	mu     sync.Mutex
+	titles []string
+}
+
+func (s *fakeSink) Send(ctx context.Context, r Report) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.titles = append(s.titles, r.Title)
+	return nil
+}
+
+func (s *fakeSink) sent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.titles...)
+}
+
+func testOptions() Options {
+	return Options{Timeout: time.Second, DedupeWindow: time.Minute, RateLimit: 1000}
+}
+
+func TestCloseSendsQueuedReports(t *testing.T) {
+	sink := &fakeSink{}
+	r := New(sink, testOptions())
+
+	r.Report(Report{Title: "first"})
+	r.Report(Report{Title: "second"})
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("closing: %s", err)
+	}
+
+	if got := sink.sent(); len(got) != 2 {
+		t.Fatalf("sent %v, want both reports queued before Close", got)
+	}
+}
+
+func TestReportAfterCloseIsDropped(t *testing.T) {
+	sink := &fakeSink{}
+	r := New(sink, testOptions())
+
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("closing: %s", err)
+	}
+	r.Report(Report{Title: "late"})
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("closing twice: %s", err)
+	}
+
+	if got := sink.sent(); len(got) != 0 {
+		t.Fatalf("sent %v after Close", got)
+	}
+}
+
+// TestReportDuringClose reports from several goroutines while closing, a report racing Close must not panic
+func TestReportDuringClose(t *testing.T) {
+	r := New(&fakeSink{}, testOptions())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Report(Report{Title: fmt.Sprintf("%d-%d", i, j)})
+			}
+		}(i)
+	}
+
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("closing: %s", err)
+	}
+	wg.Wait()
+}
+
+func TestReportDeduplicatesByTitle(t *testing.T) {
+	sink := &fakeSink{}
+	r := New(sink, testOptions())
+
+	r.Report(Report{Title: "same"})
+	r.Report(Report{Title: "same"})
+	r.Report(Report{Title: "other"})
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("closing: %s", err)
+	}
+
+	if got := sink.sent(); len(got) != 2 || got[0] != "same" || got[1] != "other" {
+		t.Fatalf("sent %v, want the duplicate muted", got)
+	}
+}
@@ -0,0 +1,99 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxStackLength keeps Slack messages under its message size limit
+const maxStackLength = 3000
+
+type httpSink struct {
+	url    string
+	client *http.Client
+	body   func(r Report) any
+}
+
+// NewHttpSink POSTs every report as JSON to url
+func NewHttpSink(url string, timeout time.Duration) Sink {
+	return &httpSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+		body:   func(r Report) any { return r },
+	}
+}
+
+// NewSlackSink posts every report as a message to a Slack compatible incoming webhook
+func NewSlackSink(url string, timeout time.Duration) Sink {
+	return &httpSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+		body:   slackMessage,
+	}
+}
+
+func (s *httpSink) Send(ctx context.Context, r Report) error {
+	body, err := json.Marshal(s.body(r))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-restapi-template-reporter/1.0")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("report sink responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func slackMessage(r Report) any {
+	var text strings.Builder
+	fmt.Fprintf(&text, ":rotating_light: *%s*\n%s\n", r.Title, r.Message)
+	if r.Suppressed > 0 {
+		fmt.Fprintf(&text, "_%d similar reports muted since the last one_\n", r.Suppressed)
+	}
+
+	if len(r.Fields) > 0 {
+		keys := make([]string, 0, len(r.Fields))
+		for key := range r.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		text.WriteString("```")
+		for _, key := range keys {
+			fmt.Fprintf(&text, "%s: %v\n", key, r.Fields[key])
+		}
+		text.WriteString("```\n")
+	}
+
+	if r.Stack != "" {
+		stack := r.Stack
+		if len(stack) > maxStackLength {
+			stack = stack[:maxStackLength] + "\n..."
+		}
+		fmt.Fprintf(&text, "```%s```\n", stack)
+	}
+
+	return map[string]string{"text": text.String()}
+}
@@ -4,6 +4,7 @@ import "net/http"
 
 type JsonResponse struct {
 	Status     int    `json:"status"`
+	Code       string `json:"code,omitempty"`
 	Message    string `json:"message"`
 	Data       any    `json:"data,omitempty"`
 	Errors     any    `json:"errors,omitempty"`
@@ -41,3 +42,28 @@ func JsonError(status int, message string, errs any) JsonResponse {
 		Errors:  errs,
 	}
 }
+
+// Problem is an RFC 7807 problem details object, code and errors are extension members
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Errors   any    `json:"errors,omitempty"`
+}
+
+const ProblemContentType = "application/problem+json"
+
+func JsonProblem(status int, code string, detail string, instance string, errs any) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     code,
+		Errors:   errs,
+	}
+}
@@ -0,0 +1,257 @@
+// Package rrule parses and expands the subset of iCalendar recurrence rules (RFC 5545) todo items recur by:
+// FREQ DAILY, WEEKLY or MONTHLY, INTERVAL, BYDAY without ordinals, UNTIL and COUNT.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// maxPeriods bounds the search of the next occurrence, e.g. a monthly rule on the 31st skips short months
+const maxPeriods = 1000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var untilLayouts = []string{"20060102T150405Z", "20060102T150405", "20060102"}
+
+type Rule struct {
+	Freq Frequency
+	// Interval is the number of periods between occurrences, 1 when unset
+	Interval int
+	// ByDay limits the occurrences to these weekdays, for WEEKLY the weekday of the start when empty
+	ByDay []time.Weekday
+	// Until is the last instant an occurrence can fall on, inclusive
+	Until *time.Time
+	// Count is the number of occurrences of the rule, unlimited when 0
+	Count int
+}
+
+// Parse reads a rule such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10, with or without the RRULE: prefix
+func Parse(s string) (Rule, error) {
+	r := Rule{Interval: 1}
+
+	s = strings.TrimPrefix(strings.TrimSpace(strings.ToUpper(s)), "RRULE:")
+	if s == "" {
+		return r, errors.New("empty recurrence rule")
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return r, fmt.Errorf("malformed recurrence rule part %q, should be KEY=VALUE", part)
+		}
+
+		switch key {
+		case "FREQ":
+			switch Frequency(value) {
+			case Daily, Weekly, Monthly:
+				r.Freq = Frequency(value)
+			default:
+				return r, fmt.Errorf("unsupported FREQ %s, should be DAILY, WEEKLY or MONTHLY", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return r, fmt.Errorf("INTERVAL should be a positive number, got %s", value)
+			}
+			r.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return r, fmt.Errorf("unsupported BYDAY %s, should be one of MO, TU, WE, TH, FR, SA, SU", day)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return r, err
+			}
+			r.Until = &until
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return r, fmt.Errorf("COUNT should be a positive number, got %s", value)
+			}
+			r.Count = count
+		default:
+			return r, fmt.Errorf("unsupported recurrence rule part %s", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return r, errors.New("recurrence rule needs a FREQ")
+	}
+	if r.Until != nil && r.Count != 0 {
+		return r, errors.New("recurrence rule can't have both UNTIL and COUNT")
+	}
+
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range untilLayouts {
+		until, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		if layout == "20060102" {
+			// a date keeps the whole day
+			until = until.Add(24*time.Hour - time.Second)
+		}
+		return until, nil
+	}
+
+	return time.Time{}, fmt.Errorf("malformed UNTIL %s, should be like 20270131 or 20270131T170000Z", value)
+}
+
+// String formats r back to its RRULE value, without the RRULE: prefix
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := []string{}
+		for _, weekday := range r.ByDay {
+			days = append(days, strings.ToUpper(weekday.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayouts[0]))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Exhausted tells whether n occurrences use up the COUNT of r
+func (r Rule) Exhausted(n int) bool {
+	return r.Count > 0 && n >= r.Count
+}
+
+// Next returns the first occurrence after the given time of the rule starting at start, the occurrences keep
+// the time of day of start in its location. It returns false once the occurrences passed UNTIL.
+func (r Rule) Next(start time.Time, after time.Time) (time.Time, bool) {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	first := r.firstPeriod(start, after, interval)
+	for k := first; k < first+maxPeriods; k++ {
+		for _, occurrence := range r.period(start, k*interval) {
+			if occurrence.Before(start) || !occurrence.After(after) {
+				continue
+			}
+			if r.Until != nil && occurrence.After(*r.Until) {
+				return time.Time{}, false
+			}
+			return occurrence, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// firstPeriod skips the periods of r ending before after
+func (r Rule) firstPeriod(start time.Time, after time.Time, interval int) int {
+	if !after.After(start) {
+		return 0
+	}
+
+	var periods int
+	switch r.Freq {
+	case Daily:
+		periods = int(after.Sub(start).Hours()/24) / interval
+	case Weekly:
+		periods = int(after.Sub(start).Hours()/(24*7)) / interval
+	case Monthly:
+		periods = ((after.Year()-start.Year())*12 + int(after.Month()-start.Month())) / interval
+	}
+
+	// a day may lack an hour across a daylight saving change
+	if periods > 0 {
+		periods--
+	}
+
+	return periods
+}
+
+// period lists the occurrences of the period offset periods after the one of start, in order
+func (r Rule) period(start time.Time, offset int) []time.Time {
+	year, month, day := start.Date()
+	hour, min, sec := start.Clock()
+	loc := start.Location()
+
+	occurrences := []time.Time{}
+	switch r.Freq {
+	case Daily:
+		occurrence := time.Date(year, month, day+offset, hour, min, sec, 0, loc)
+		if len(r.ByDay) == 0 || r.onDay(occurrence.Weekday()) {
+			occurrences = append(occurrences, occurrence)
+		}
+	case Weekly:
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{start.Weekday()}
+		}
+		// weeks start on monday, the default WKST
+		monday := day - (int(start.Weekday())+6)%7 + 7*offset
+		for _, weekday := range days {
+			occurrences = append(occurrences, time.Date(year, month, monday+(int(weekday)+6)%7, hour, min, sec, 0, loc))
+		}
+	case Monthly:
+		first := time.Date(year, month+time.Month(offset), 1, hour, min, sec, 0, loc)
+		if len(r.ByDay) == 0 {
+			occurrence := first.AddDate(0, 0, day-1)
+			// months without the day of start are skipped
+			if occurrence.Month() == first.Month() {
+				occurrences = append(occurrences, occurrence)
+			}
+			break
+		}
+		for occurrence := first; occurrence.Month() == first.Month(); occurrence = occurrence.AddDate(0, 0, 1) {
+			if r.onDay(occurrence.Weekday()) {
+				occurrences = append(occurrences, occurrence)
+			}
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Before(occurrences[j]) })
+
+	return occurrences
+}
+
+func (r Rule) onDay(weekday time.Weekday) bool {
+	for _, day := range r.ByDay {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
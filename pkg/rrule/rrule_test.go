@@ -0,0 +1,154 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for _, s := range []string{
+		"FREQ=DAILY",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10",
+		"FREQ=MONTHLY;UNTIL=20270131T170000Z",
+	} {
+		r, err := Parse("RRULE:" + s)
+		if err != nil {
+			t.Fatalf("%s: %s", s, err)
+		}
+		if r.String() != s {
+			t.Errorf("%s formatted back as %s", s, r.String())
+		}
+	}
+
+	r, err := Parse("freq=weekly;until=20270131")
+	if err != nil {
+		t.Fatalf("lower case rule: %s", err)
+	}
+	if want := time.Date(2027, 1, 31, 23, 59, 59, 0, time.UTC); !r.Until.Equal(want) {
+		t.Errorf("UNTIL of a date is %s, want the end of the day", r.Until)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;COUNT=2;UNTIL=20270131",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ",
+	} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("%q parsed", s)
+		}
+	}
+}
+
+// occurrences lists the first n occurrences of rule from start
+func occurrences(t *testing.T, rule string, start time.Time, n int) []time.Time {
+	t.Helper()
+
+	r, err := Parse(rule)
+	if err != nil {
+		t.Fatalf("%s: %s", rule, err)
+	}
+
+	got := []time.Time{}
+	after := start.Add(-time.Second)
+	for len(got) < n {
+		next, ok := r.Next(start, after)
+		if !ok {
+			break
+		}
+		got = append(got, next)
+		after = next
+	}
+
+	return got
+}
+
+func expectDates(t *testing.T, rule string, got []time.Time, want ...string) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %v, want %v", rule, got, want)
+	}
+	for i := range want {
+		if got[i].Format("2006-01-02 15:04") != want[i] {
+			t.Fatalf("%s: occurrence %d is %s, want %s", rule, i, got[i].Format("2006-01-02 15:04"), want[i])
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	// a wednesday
+	start := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC)
+
+	for _, c := range []struct {
+		rule string
+		want []string
+	}{
+		{"FREQ=DAILY;INTERVAL=2", []string{"2026-10-14 09:30", "2026-10-16 09:30", "2026-10-18 09:30"}},
+		{"FREQ=DAILY;BYDAY=SA,SU", []string{"2026-10-17 09:30", "2026-10-18 09:30", "2026-10-24 09:30"}},
+		{"FREQ=WEEKLY", []string{"2026-10-14 09:30", "2026-10-21 09:30", "2026-10-28 09:30"}},
+		{"FREQ=WEEKLY;BYDAY=FR,MO", []string{"2026-10-16 09:30", "2026-10-19 09:30", "2026-10-23 09:30"}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", []string{"2026-10-14 09:30", "2026-10-26 09:30", "2026-10-28 09:30"}},
+		{"FREQ=MONTHLY", []string{"2026-10-14 09:30", "2026-11-14 09:30", "2026-12-14 09:30"}},
+		{"FREQ=MONTHLY;BYDAY=MO", []string{"2026-10-19 09:30", "2026-10-26 09:30", "2026-11-02 09:30"}},
+		{"FREQ=DAILY;UNTIL=20261015", []string{"2026-10-14 09:30", "2026-10-15 09:30"}},
+	} {
+		expectDates(t, c.rule, occurrences(t, c.rule, start, 3), c.want...)
+	}
+}
+
+func TestNextSkipsMonthsWithoutTheDay(t *testing.T) {
+	start := time.Date(2027, 1, 31, 8, 0, 0, 0, time.UTC)
+
+	expectDates(t, "FREQ=MONTHLY", occurrences(t, "FREQ=MONTHLY", start, 3), "2027-01-31 08:00", "2027-03-31 08:00", "2027-05-31 08:00")
+}
+
+func TestNextLongAfterStart(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;BYDAY=TU")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2020, 1, 7, 18, 0, 0, 0, time.UTC)
+	next, ok := r.Next(start, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	if !ok || !next.Equal(time.Date(2026, 10, 20, 18, 0, 0, 0, time.UTC)) {
+		t.Fatalf("next %s, %t, want the coming tuesday", next, ok)
+	}
+}
+
+func TestNextKeepsTimeOfDayAcrossDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skipf("no time zone database: %s", err)
+	}
+
+	// clocks go back on 2026-10-25
+	start := time.Date(2026, 10, 24, 9, 0, 0, 0, loc)
+	expectDates(t, "FREQ=DAILY", occurrences(t, "FREQ=DAILY", start, 3), "2026-10-24 09:00", "2026-10-25 09:00", "2026-10-26 09:00")
+}
+
+func TestExhausted(t *testing.T) {
+	r, err := Parse("FREQ=DAILY;COUNT=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Exhausted(2) || !r.Exhausted(3) {
+		t.Fatal("COUNT=3 should be used up by the third occurrence")
+	}
+
+	r, err = Parse("FREQ=DAILY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Exhausted(1000) {
+		t.Fatal("a rule without COUNT is never used up")
+	}
+}
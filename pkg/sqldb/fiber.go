@@ -0,0 +1,13 @@
+package sqldb
+
+import "github.com/gofiber/fiber/v2"
+
+// FiberMiddleware runs every request as one session, so with read-your-writes
+// its reads after a write are served by the primary
+func FiberMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(WithSession(c.UserContext()))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,113 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DB routes reads to the replica when there is one, transactions and writes always go to the primary
+type DB struct {
+	Primary *sqlx.DB
+	// Replica is nil when no replica is configured
+	Replica *sqlx.DB
+
+	readYourWrites bool
+}
+
+type sessionKey struct{}
+
+// session records whether a unit of work has written to the primary
+type session struct {
+	wrote atomic.Bool
+}
+
+// WithSession starts a unit of work on ctx, with read-your-writes its reads
+// are served by the primary once it has begun a transaction. A ctx already
+// in a session is returned as is, so the unit of work spans the whole request.
+func WithSession(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(sessionKey{}).(*session); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, sessionKey{}, &session{})
+}
+
+// Reader is the pool for queries that tolerate replication lag
+func (db *DB) Reader(ctx context.Context) *sqlx.DB {
+	if db.Replica == nil {
+		return db.Primary
+	}
+
+	if s, ok := ctx.Value(sessionKey{}).(*session); ok && db.readYourWrites && s.wrote.Load() {
+		return db.Primary
+	}
+
+	return db.Replica
+}
+
+// MustBeginTx begins a transaction on the primary and pins the session of ctx to it
+func (db *DB) MustBeginTx(ctx context.Context, opts *sql.TxOptions) *sqlx.Tx {
+	if s, ok := ctx.Value(sessionKey{}).(*session); ok {
+		s.wrote.Store(true)
+	}
+
+	return db.Primary.MustBeginTx(ctx, opts)
+}
+
+// Stats reports the pool statistics of the primary and of the replica, if any
+func (db *DB) Stats() (primary sql.DBStats, replica *sql.DBStats) {
+	primary = db.Primary.Stats()
+	if db.Replica != nil {
+		stats := db.Replica.Stats()
+		replica = &stats
+	}
+
+	return primary, replica
+}
+
+// CheckTables fails on the first of tables missing from the primary
+func (db *DB) CheckTables(ctx context.Context, tables ...string) error {
+	for _, table := range tables {
+		var found sql.NullString
+		if err := db.Primary.GetContext(ctx, &found, "SELECT to_regclass($1)::text", table); err != nil {
+			return err
+		}
+		if !found.Valid {
+			return fmt.Errorf("table %s does not exist", table)
+		}
+	}
+
+	return nil
+}
+
+// CheckVersion fails unless the newest version recorded in table on the primary is at least want, the schema
+// may be ahead of the binary during a rollout as long as the newer scripts keep the older binaries working
+func (db *DB) CheckVersion(ctx context.Context, table string, want int) error {
+	var version sql.NullInt64
+	if err := db.Primary.GetContext(ctx, &version, fmt.Sprintf("SELECT MAX(version) FROM %s", table)); err != nil {
+		return err
+	}
+	if !version.Valid {
+		return fmt.Errorf("%s records no version, want %d", table, want)
+	}
+	if version.Int64 < int64(want) {
+		return fmt.Errorf("schema version %d, want %d", version.Int64, want)
+	}
+
+	return nil
+}
+
+func (db *DB) Close() error {
+	var errs []error
+	if db.Replica != nil {
+		errs = append(errs, db.Replica.Close())
+	}
+	errs = append(errs, db.Primary.Close())
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,198 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// txDriver only begins, commits and rolls back, the tests only look at which pool is picked
+type txDriver struct{}
+
+func (txDriver) Open(name string) (driver.Conn, error) {
+	return txConn{}, nil
+}
+
+type txConn struct{}
+
+func (txConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("txDriver runs no statements")
+}
+
+func (txConn) Close() error {
+	return nil
+}
+
+func (txConn) Begin() (driver.Tx, error) {
+	return txConn{}, nil
+}
+
+func (txConn) Commit() error {
+	return nil
+}
+
+func (txConn) Rollback() error {
+	return nil
+}
+
+func init() {
+	sql.Register("sqldb-test-tx", txDriver{})
+}
+
+// versionDriver answers every query with the version its name holds, an empty name answers NULL
+type versionDriver struct{}
+
+func (versionDriver) Open(name string) (driver.Conn, error) {
+	return versionConn{version: name}, nil
+}
+
+type versionConn struct {
+	txConn
+	version string
+}
+
+func (c versionConn) Prepare(query string) (driver.Stmt, error) {
+	return versionStmt{version: c.version}, nil
+}
+
+type versionStmt struct {
+	version string
+}
+
+func (versionStmt) Close() error {
+	return nil
+}
+
+func (versionStmt) NumInput() int {
+	return -1
+}
+
+func (versionStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("versionDriver only queries")
+}
+
+func (s versionStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &versionRows{version: s.version}, nil
+}
+
+type versionRows struct {
+	version string
+	read    bool
+}
+
+func (*versionRows) Columns() []string {
+	return []string{"max"}
+}
+
+func (*versionRows) Close() error {
+	return nil
+}
+
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	if r.version == "" {
+		dest[0] = nil
+		return nil
+	}
+	version, err := strconv.ParseInt(r.version, 10, 64)
+	dest[0] = version
+	return err
+}
+
+func init() {
+	sql.Register("sqldb-test-version", versionDriver{})
+}
+
+func testDB(readYourWrites bool) *DB {
+	return &DB{
+		Primary:        sqlx.MustOpen("sqldb-test-tx", "primary"),
+		Replica:        sqlx.MustOpen("sqldb-test-tx", "replica"),
+		readYourWrites: readYourWrites,
+	}
+}
+
+func TestReaderWithoutReplica(t *testing.T) {
+	db := &DB{Primary: sqlx.MustOpen("sqldb-test-tx", "primary"), readYourWrites: true}
+
+	if db.Reader(context.Background()) != db.Primary {
+		t.Fatal("reads without a replica should go to the primary")
+	}
+}
+
+func TestReaderPinsSessionAfterWrite(t *testing.T) {
+	db := testDB(true)
+	ctx := WithSession(context.Background())
+
+	if db.Reader(ctx) != db.Replica {
+		t.Fatal("reads before a write should go to the replica")
+	}
+
+	db.MustBeginTx(ctx, nil).Rollback()
+
+	if db.Reader(ctx) != db.Primary {
+		t.Fatal("reads after a write should go to the primary")
+	}
+	if db.Reader(WithSession(context.Background())) != db.Replica {
+		t.Fatal("another session should still read from the replica")
+	}
+}
+
+func TestWithSessionKeepsExistingSession(t *testing.T) {
+	db := testDB(true)
+	ctx := WithSession(context.Background())
+
+	// a service call deriving its own ctx must stay in the session of the request
+	db.MustBeginTx(WithSession(ctx), nil).Rollback()
+
+	if db.Reader(WithSession(ctx)) != db.Primary {
+		t.Fatal("a nested session lost the write of the request")
+	}
+}
+
+func TestReaderWithoutReadYourWrites(t *testing.T) {
+	db := testDB(false)
+	ctx := WithSession(context.Background())
+
+	db.MustBeginTx(ctx, nil).Rollback()
+
+	if db.Reader(ctx) != db.Replica {
+		t.Fatal("without read-your-writes reads should stay on the replica")
+	}
+}
+
+func TestReaderWithoutSession(t *testing.T) {
+	db := testDB(true)
+
+	db.MustBeginTx(context.Background(), nil).Rollback()
+
+	if db.Reader(context.Background()) != db.Replica {
+		t.Fatal("reads outside a session should go to the replica")
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	for _, tc := range []struct {
+		recorded string
+		ok       bool
+	}{
+		{recorded: "", ok: false},
+		{recorded: "2", ok: false},
+		{recorded: "3", ok: true},
+		// the schema of a rollout in progress
+		{recorded: "4", ok: true},
+	} {
+		db := &DB{Primary: sqlx.MustOpen("sqldb-test-version", tc.recorded)}
+		if err := db.CheckVersion(context.Background(), "schema_migration", 3); (err == nil) != tc.ok {
+			t.Errorf("recorded version %q: err %v, want ok %t", tc.recorded, err, tc.ok)
+		}
+	}
+}
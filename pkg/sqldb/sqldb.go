@@ -2,36 +2,77 @@ package sqldb
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/Adhiana46/go-restapi-template/config"
+	"github.com/XSAM/otelsql"
 	"github.com/jmoiron/sqlx"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func OpenConn(cfg *config.Config) (*sqlx.DB, error) {
+// Open connects to the primary and, when configured, to the read replica
+func Open(cfg config.DatabaseConfig) (*DB, error) {
+	primary, err := OpenConn(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{
+		Primary:        primary,
+		readYourWrites: cfg.Replica.ReadYourWrites,
+	}
+
+	if cfg.Replica.Host == "" {
+		return db, nil
+	}
+
+	replicaCfg := cfg
+	replicaCfg.Host = cfg.Replica.Host
+	if cfg.Replica.Port != "" {
+		replicaCfg.Port = cfg.Replica.Port
+	}
+
+	db.Replica, err = OpenConn(replicaCfg)
+	if err != nil {
+		primary.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func OpenConn(cfg config.DatabaseConfig) (*sqlx.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s dbname=%s sslmode=%s password=%s",
-		cfg.DbHost,
-		cfg.DbPort,
-		cfg.DbUser,
-		cfg.DbName,
-		cfg.DbSSL,
-		cfg.DbPass,
+		cfg.Host,
+		cfg.Port,
+		cfg.User,
+		cfg.Name,
+		cfg.SSL,
+		cfg.Pass,
 	)
+	if cfg.StatementTimeout > 0 {
+		// pgx sends unknown keys as run-time parameters of every session
+		dsn += fmt.Sprintf(" statement_timeout=%d", cfg.StatementTimeout.Milliseconds())
+	}
 
-	dbConn, err := sqlx.Connect(cfg.DbDialect, dsn)
+	// every query gets a span carrying its statement
+	sqlDB, err := otelsql.Open(cfg.Dialect, dsn,
+		otelsql.WithAttributes(attribute.String("db.system.name", "postgresql")),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
 		return nil, err
 	}
+	dbConn := sqlx.NewDb(sqlDB, cfg.Dialect)
 
-	dbConn.SetMaxOpenConns(60)
-	dbConn.SetConnMaxLifetime(120 * time.Second)
-	dbConn.SetMaxIdleConns(30)
-	dbConn.SetConnMaxIdleTime(20 * time.Second)
+	dbConn.SetMaxOpenConns(cfg.Pool.MaxOpenConns)
+	dbConn.SetConnMaxLifetime(cfg.Pool.ConnMaxLifetime)
+	dbConn.SetMaxIdleConns(cfg.Pool.MaxIdleConns)
+	dbConn.SetConnMaxIdleTime(cfg.Pool.ConnMaxIdleTime)
 	if err = dbConn.Ping(); err != nil {
 		return nil, err
 	} else {
-		log.Infoln("Pinged database successfully!")
+		log.Infof("Pinged database %s:%s successfully!", cfg.Host, cfg.Port)
 	}
 
 	return dbConn, nil
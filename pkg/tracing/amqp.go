@@ -0,0 +1,62 @@
+package tracing
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// amqpCarrier carries the trace context in the headers of an AMQP message
+type amqpCarrier amqp.Table
+
+func (ac amqpCarrier) Get(key string) string {
+	value, _ := ac[key].(string)
+	return value
+}
+
+func (ac amqpCarrier) Set(key string, value string) {
+	ac[key] = value
+}
+
+func (ac amqpCarrier) Keys() []string {
+	keys := make([]string, 0, len(ac))
+	for key := range ac {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// StartConsume continues the trace of the producer of d with a consumer span
+func StartConsume(d amqp.Delivery, queue string) (context.Context, trace.Span) {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), amqpCarrier(d.Headers))
+
+	return Start(ctx, queue+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.operation.type", "process"),
+			attribute.String("messaging.destination.name", queue),
+		),
+	)
+}
+
+// StartPublish starts a producer span and returns headers carrying it to the consumer
+func StartPublish(ctx context.Context, queue string) (context.Context, trace.Span, amqp.Table) {
+	ctx, span := Start(ctx, queue+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.operation.type", "publish"),
+			attribute.String("messaging.destination.name", queue),
+		),
+	)
+
+	headers := amqp.Table{}
+	otel.GetTextMapPropagator().Inject(ctx, amqpCarrier(headers))
+
+	return ctx, span, headers
+}
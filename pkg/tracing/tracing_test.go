@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Adhiana46/go-restapi-template/config"
+	"github.com/gofiber/fiber/v2"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a provider keeping the ended spans in memory
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+	return recorder
+}
+
+func TestAmqpHeadersCarryTrace(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, publish, headers := StartPublish(context.Background(), "todo-item.request")
+	publish.End()
+	if _, ok := headers["traceparent"].(string); !ok {
+		t.Fatalf("headers %v, want the traceparent", headers)
+	}
+
+	_, consume := StartConsume(amqp.Delivery{Headers: headers}, "todo-item.request")
+	End(consume, errors.New("malformed message"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	producer, consumer := spans[0], spans[1]
+	if consumer.Parent().SpanID() != producer.SpanContext().SpanID() || consumer.SpanContext().TraceID() != producer.SpanContext().TraceID() {
+		t.Fatal("consumer span doesn't continue the trace of the producer")
+	}
+	if producer.SpanKind() != trace.SpanKindProducer || consumer.SpanKind() != trace.SpanKindConsumer {
+		t.Fatalf("kinds %s and %s", producer.SpanKind(), consumer.SpanKind())
+	}
+	if consumer.Status().Code != codes.Error {
+		t.Fatalf("consumer status %v, want the error recorded", consumer.Status())
+	}
+}
+
+func TestFiberMiddlewareContinuesCallerTrace(t *testing.T) {
+	recorder := recordSpans(t)
+
+	app := fiber.New()
+	app.Use(FiberMiddleware())
+	app.Get("/todo-items/:uuid", func(c *fiber.Ctx) error {
+		// spans of the handlers are children of the request span
+		_, span := Start(c.UserContext(), "TodoItemService.FindByUuid")
+		span.End()
+		return errors.New("connection refused")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/todo-items/item", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("requesting: %s", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	child, server := spans[0], spans[1]
+	if server.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || server.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Fatalf("server span in trace %s under %s, want the caller's", server.SpanContext().TraceID(), server.Parent().SpanID())
+	}
+	if child.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Fatal("handler span isn't a child of the request span")
+	}
+	if server.Name() != "GET /todo-items/:uuid" || server.Status().Code != codes.Error {
+		t.Fatalf("server span %q with status %v, want named by route and failed", server.Name(), server.Status())
+	}
+	recorded := false
+	for _, attr := range server.Attributes() {
+		recorded = recorded || attr == attribute.Int("http.response.status_code", http.StatusInternalServerError)
+	}
+	if !recorded {
+		t.Fatalf("attributes %v, want the status of the rendered error", server.Attributes())
+	}
+}
+
+func TestSetupRejectsUnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), config.TracingConfig{Exporter: "jaeger", ServiceName: "todoapp"}, "api"); err == nil {
+		t.Fatal("unknown exporter accepted")
+	}
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign returns the hex encoded HMAC-SHA256 of "timestamp.body", receivers recompute it to verify the payload
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrPrivateAddress is returned by Send when the url resolves to an address of the host or its networks
+var ErrPrivateAddress = errors.New("webhook url resolves to a private address")
+
+// PublicAddr reports whether addr is reachable on the internet, loopback, link-local (cloud metadata
+// included), private and unspecified addresses are not
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return addr.IsValid() && !addr.IsLoopback() && !addr.IsPrivate() && !addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() && !addr.IsInterfaceLocalMulticast() && !addr.IsMulticast() && !addr.IsUnspecified()
+}
+
+// PublicUrl reports whether rawUrl may point to the internet, a host name is only checked for localhost
+// here because what it resolves to changes, Send checks the address it dials
+func PublicUrl(rawUrl string) bool {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return PublicAddr(addr)
+	}
+
+	return host != "" && host != "localhost" && !strings.HasSuffix(host, ".localhost")
+}
+
+// dialPublic refuses connections to addresses PublicAddr rejects, it runs after name resolution
+// and on every redirect, so a host name can't be pointed at the internal network later
+func dialPublic(network, address string, c syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !PublicAddr(addrPort.Addr()) {
+		return ErrPrivateAddress
+	}
+
+	return nil
+}
+
+type Sender interface {
+	Send(ctx context.Context, url string, secret string, event string, deliveryUuid string, body []byte) (int, error)
+}
+
+type sender struct {
+	client *http.Client
+}
+
+func NewSender(timeout time.Duration) Sender {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// proxies would dial the endpoint on our behalf, past the check of the address
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: dialPublic}).DialContext
+
+	return &sender{
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+// Send POSTs the signed body and returns the response status code, any non-2xx status is an error
+func (s *sender) Send(ctx context.Context, url string, secret string, event string, deliveryUuid string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-restapi-template-webhook/1.0")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderDelivery, deliveryUuid)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPublicUrl(t *testing.T) {
+	for url, want := range map[string]bool{
+		"https://hooks.example.com/todo":          true,
+		"http://93.184.215.14/hook":               true,
+		"http://127.0.0.1:8000/hook":              false,
+		"http://localhost/hook":                   false,
+		"http://api.localhost./hook":              false,
+		"http://10.1.2.3/hook":                    false,
+		"http://192.168.1.10/hook":                false,
+		"http://169.254.169.254/latest/meta-data": false,
+		"http://0.0.0.0/hook":                     false,
+		"http://[::1]/hook":                       false,
+		"http://[fe80::1]/hook":                   false,
+		"http://[::ffff:127.0.0.1]/hook":          false,
+	} {
+		if got := PublicUrl(url); got != want {
+			t.Errorf("PublicUrl(%s) %t, want %t", url, got, want)
+		}
+	}
+}
+
+// TestSendRefusesPrivateAddress makes sure a host name resolving to the host itself isn't dialed
+func TestSendRefusesPrivateAddress(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	_, err := NewSender(time.Second).Send(context.Background(), server.URL, "secret", "todo_item.created", "delivery", []byte("{}"))
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("sending to %s: %v, want ErrPrivateAddress", server.URL, err)
+	}
+	if called {
+		t.Fatal("the private endpoint was reached")
+	}
+}
@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+type Handler interface {
+	RegisterRoutes(r fiber.Router) Handler
+	Docs() []openapi.Operation
+
+	query() func(c *fiber.Ctx) error
+}
+
+type handler struct {
+	schema graphql.Schema
+
+	svcActivityGroup service.ActivityGroupService
+	svcTodoItem      service.TodoItemService
+}
+
+func NewHandler(svcActivityGroup service.ActivityGroupService, svcTodoItem service.TodoItemService) (Handler, error) {
+	schema, err := NewSchema(svcActivityGroup, svcTodoItem)
+	if err != nil {
+		return nil, err
+	}
+
+	return &handler{
+		schema:           schema,
+		svcActivityGroup: svcActivityGroup,
+		svcTodoItem:      svcTodoItem,
+	}, nil
+}
+
+func (h *handler) RegisterRoutes(r fiber.Router) Handler {
+	r.Get("/", h.query())
+	r.Post("/", h.query())
+
+	return h
+}
+
+func (h *handler) Docs() []openapi.Operation {
+	tags := []string{"GraphQL"}
+
+	return []openapi.Operation{
+		{Method: "GET", Path: "/", Summary: "Run a GraphQL query", Tags: tags, Query: queryRequest{}, Response: queryResponse{}, RawResponse: true},
+		{Method: "POST", Path: "/", Summary: "Run a GraphQL query or mutation", Tags: tags, Body: queryRequest{}, Response: queryResponse{}, RawResponse: true},
+	}
+}
+
+type queryResponse struct {
+	Data   map[string]any   `json:"data"`
+	Errors []map[string]any `json:"errors,omitempty"`
+}
+
+type queryRequest struct {
+	Query         string         `json:"query" query:"query"`
+	OperationName string         `json:"operationName" query:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+func (h *handler) query() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := queryRequest{}
+		if c.Method() == fiber.MethodGet {
+			if err := c.QueryParser(&req); err != nil {
+				return apperror.BadRequest(apperror.CodeBadRequest, err.Error())
+			}
+			if variables := c.Query("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					return apperror.BadRequest(apperror.CodeBadRequest, "malformed variables query parameter, should be a JSON object")
+				}
+			}
+		} else if err := c.BodyParser(&req); err != nil {
+			return apperror.BadRequest(apperror.CodeBadRequest, err.Error())
+		}
+
+		// malformed queries fall through to graphql.Do, which reports them like any other error
+		if doc, err := parser.Parse(parser.ParseParams{Source: req.Query}); err == nil {
+			op := operation(doc, req.OperationName)
+			// GET is left to queries so links and prefetches can't change anything
+			if op != nil && op.Operation == ast.OperationTypeMutation && c.Method() == fiber.MethodGet {
+				c.Set(fiber.HeaderAllow, fiber.MethodPost)
+				return fiber.NewError(fiber.StatusMethodNotAllowed, "mutations must be sent with POST")
+			}
+			if op != nil && depth(op.SelectionSet, fragments(doc), map[string]bool{}) > maxQueryDepth {
+				return c.Status(http.StatusOK).JSON(graphql.Result{Errors: []gqlerrors.FormattedError{{
+					Message:    fmt.Sprintf("query is nested deeper than %d fields", maxQueryDepth),
+					Extensions: map[string]any{"code": apperror.CodeBadRequest},
+				}}})
+			}
+		}
+
+		ctx := withLoaders(c.UserContext(), newLoaders(h.svcActivityGroup, h.svcTodoItem))
+
+		result := graphql.Do(graphql.Params{
+			Schema:         h.schema,
+			RequestString:  req.Query,
+			OperationName:  req.OperationName,
+			VariableValues: req.Variables,
+			Context:        ctx,
+		})
+
+		// expose the stable code of service errors and hide the cause of internal ones
+		for i, formatted := range result.Errors {
+			var gqlErr *gqlerrors.Error
+			if errors.As(formatted.OriginalError(), &gqlErr) && gqlErr.OriginalError != nil {
+				appErr := apperror.From(gqlErr.OriginalError)
+				result.Errors[i].Message = appErr.Message
+				result.Errors[i].Extensions = map[string]any{"code": appErr.Code}
+			}
+		}
+
+		return c.Status(http.StatusOK).JSON(result)
+	}
+}
@@ -0,0 +1,247 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// fakeActivityGroupService serves two groups and counts the batched lookups
+type fakeActivityGroupService struct {
+	service.ActivityGroupService
+
+	mu        sync.Mutex
+	findByIds [][]int
+}
+
+var testActivityGroups = []*entity.ActivityGroup{
+	{ID: 1, Uuid: "work", Name: "work"},
+	{ID: 2, Uuid: "home", Name: "home"},
+}
+
+func (s *fakeActivityGroupService) FindByUuid(ctx context.Context, req dto.ActivityGroupUuidRequest) (*entity.ActivityGroup, error) {
+	for _, activityGroup := range testActivityGroups {
+		if activityGroup.Uuid == req.Uuid {
+			return activityGroup, nil
+		}
+	}
+
+	return nil, apperror.NotFound(apperror.CodeActivityGroupNotFound, "activity group not found")
+}
+
+func (s *fakeActivityGroupService) FindByIds(ctx context.Context, req dto.ActivityGroupIdsRequest) ([]*entity.ActivityGroup, error) {
+	s.mu.Lock()
+	s.findByIds = append(s.findByIds, req.Ids)
+	s.mu.Unlock()
+
+	return testActivityGroups, nil
+}
+
+func (s *fakeActivityGroupService) FetchAll(ctx context.Context, req dto.ActivityGroupFetchRequest) ([]*entity.ActivityGroup, *responsePkg.Pagination, error) {
+	return testActivityGroups, &responsePkg.Pagination{Size: req.Limit, Total: 2, TotalPages: 1, CurrentPage: req.Page}, nil
+}
+
+type fakeTodoItemService struct {
+	service.TodoItemService
+
+	mu      sync.Mutex
+	counts  [][]int
+	updated []dto.TodoItemUpdateRequest
+}
+
+func (s *fakeTodoItemService) FetchAll(ctx context.Context, req dto.TodoItemFetchRequest) ([]*entity.TodoItem, *responsePkg.Pagination, error) {
+	items := []*entity.TodoItem{}
+	for _, activityGroup := range testActivityGroups {
+		if activityGroup.Uuid == req.ActivityUuid {
+			items = append(items, &entity.TodoItem{Uuid: activityGroup.Uuid + "-item", ActivityID: activityGroup.ID, Name: "todo item"})
+		}
+	}
+
+	return items, &responsePkg.Pagination{Size: req.Limit, Total: len(items), TotalPages: 1, CurrentPage: req.Page}, nil
+}
+
+func (s *fakeTodoItemService) FindByUuid(ctx context.Context, req dto.TodoItemUuidRequest) (*entity.TodoItem, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (s *fakeTodoItemService) CountByActivityIds(ctx context.Context, req dto.TodoItemCountRequest) (map[int]int, error) {
+	s.mu.Lock()
+	s.counts = append(s.counts, req.ActivityIds)
+	s.mu.Unlock()
+
+	totals := map[int]int{}
+	for _, id := range req.ActivityIds {
+		totals[id] = id * 10
+	}
+
+	return totals, nil
+}
+
+func (s *fakeTodoItemService) Update(ctx context.Context, req dto.TodoItemUpdateRequest) (*entity.TodoItem, error) {
+	s.mu.Lock()
+	s.updated = append(s.updated, req)
+	s.mu.Unlock()
+
+	return &entity.TodoItem{Uuid: req.Uuid, Name: req.Name}, nil
+}
+
+func execute(t *testing.T, h Handler, query string) queryResponse {
+	t.Helper()
+
+	r := fiber.New()
+	h.RegisterRoutes(r)
+
+	body, _ := json.Marshal(queryRequest{Query: query})
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.Test(req)
+	if err != nil {
+		t.Fatalf("querying: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+
+	var result queryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decoding: %s", err)
+	}
+
+	return result
+}
+
+func newTestHandler(t *testing.T) (Handler, *fakeActivityGroupService, *fakeTodoItemService) {
+	t.Helper()
+
+	svcActivityGroup, svcTodoItem := &fakeActivityGroupService{}, &fakeTodoItemService{}
+	h, err := NewHandler(svcActivityGroup, svcTodoItem)
+	if err != nil {
+		t.Fatalf("building schema: %s", err)
+	}
+
+	return h, svcActivityGroup, svcTodoItem
+}
+
+func TestNestedQueryBatchesLookups(t *testing.T) {
+	h, svcActivityGroup, svcTodoItem := newTestHandler(t)
+
+	result := execute(t, h, `{ activityGroups { items { uuid todoItemCount todoItems { items { uuid activity { uuid } } } } pagination { total } } }`)
+	if len(result.Errors) > 0 {
+		t.Fatalf("errors %v", result.Errors)
+	}
+
+	got, _ := json.Marshal(result.Data)
+	want := `{"activityGroups":{"items":[` +
+		`{"todoItemCount":10,"todoItems":{"items":[{"activity":{"uuid":"work"},"uuid":"work-item"}]},"uuid":"work"},` +
+		`{"todoItemCount":20,"todoItems":{"items":[{"activity":{"uuid":"home"},"uuid":"home-item"}]},"uuid":"home"}],` +
+		`"pagination":{"total":2}}}`
+	if string(got) != want {
+		t.Fatalf("data\n%s\nwant\n%s", got, want)
+	}
+
+	// one lookup per field for the whole list instead of one per group or item
+	if len(svcTodoItem.counts) != 1 || len(svcTodoItem.counts[0]) != 2 {
+		t.Errorf("counted todo items with %v, want a single batch of both groups", svcTodoItem.counts)
+	}
+	if len(svcActivityGroup.findByIds) != 1 || len(svcActivityGroup.findByIds[0]) != 2 {
+		t.Errorf("looked up activity groups with %v, want a single batch of both groups", svcActivityGroup.findByIds)
+	}
+}
+
+func TestQueryErrorCodes(t *testing.T) {
+	h, _, _ := newTestHandler(t)
+
+	result := execute(t, h, `{ activityGroup(uuid: "missing") { uuid } }`)
+	if len(result.Errors) != 1 || result.Errors[0]["message"] != "activity group not found" {
+		t.Fatalf("errors %v, want the not found error", result.Errors)
+	}
+	if extensions, _ := result.Errors[0]["extensions"].(map[string]any); extensions["code"] != string(apperror.CodeActivityGroupNotFound) {
+		t.Fatalf("extensions %v, want the code of the service error", result.Errors[0]["extensions"])
+	}
+
+	// the cause of internal errors stays on the server
+	result = execute(t, h, `{ todoItem(uuid: "item") { uuid } }`)
+	if len(result.Errors) != 1 || result.Errors[0]["message"] != "internal error" {
+		t.Fatalf("errors %v, want the cause hidden", result.Errors)
+	}
+}
+
+func TestUpdateTodoItemKeepsOmittedFields(t *testing.T) {
+	h, _, svcTodoItem := newTestHandler(t)
+
+	result := execute(t, h, `mutation { updateTodoItem(uuid: "item", activityUuid: "work", name: "stand-up") { uuid name } }`)
+	if len(result.Errors) > 0 {
+		t.Fatalf("errors %v", result.Errors)
+	}
+	if req := svcTodoItem.updated[0]; req.DueAt != nil || req.ClearDueAt || req.IsCompleted != nil {
+		t.Fatalf("update without dueAt and isCompleted sent %+v, want both kept", req)
+	}
+
+	result = execute(t, h, `mutation { updateTodoItem(uuid: "item", activityUuid: "work", name: "stand-up", clearDueAt: true, isCompleted: false) { uuid } }`)
+	if len(result.Errors) > 0 {
+		t.Fatalf("errors %v", result.Errors)
+	}
+	if req := svcTodoItem.updated[1]; !req.ClearDueAt || req.IsCompleted == nil || *req.IsCompleted {
+		t.Fatalf("update sent clear %t, completed %v, want the due date cleared and the item reopened", req.ClearDueAt, req.IsCompleted)
+	}
+}
+
+func TestMutationOverGetIsRejected(t *testing.T) {
+	h, _, svcTodoItem := newTestHandler(t)
+
+	r := fiber.New()
+	h.RegisterRoutes(r)
+
+	query := url.Values{"query": {`mutation { updateTodoItem(uuid: "item", activityUuid: "work", name: "stand-up") { uuid } }`}}
+	resp, err := r.Test(httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil))
+	if err != nil {
+		t.Fatalf("querying: %s", err)
+	}
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != http.MethodPost {
+		t.Fatalf("status %d, allow %q, want 405 pointing at POST", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+	if len(svcTodoItem.updated) > 0 {
+		t.Fatalf("mutation over GET updated %v", svcTodoItem.updated)
+	}
+
+	// queries still run over GET
+	query = url.Values{"query": {`{ activityGroup(uuid: "work") { uuid } }`}}
+	resp, err = r.Test(httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil))
+	if err != nil {
+		t.Fatalf("querying: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("query over GET status %d", resp.StatusCode)
+	}
+}
+
+func TestQueryDepthLimit(t *testing.T) {
+	h, svcActivityGroup, _ := newTestHandler(t)
+
+	result := execute(t, h, `{ activityGroups { items { todoItems { items { activity { todoItems { items { activity { todoItems { items { uuid } } } } } } } } } } }`)
+	if len(result.Errors) != 1 || result.Errors[0]["message"] != "query is nested deeper than 10 fields" {
+		t.Fatalf("errors %v, want the depth limit", result.Errors)
+	}
+	if len(svcActivityGroup.findByIds) > 0 {
+		t.Fatalf("too deep query ran lookups %v", svcActivityGroup.findByIds)
+	}
+
+	// a fragment spread inside itself never ends
+	result = execute(t, h, `{ activityGroups { items { ...group } } } fragment group on ActivityGroup { todoItems { items { activity { ...group } } } }`)
+	if len(result.Errors) != 1 || result.Errors[0]["message"] != "query is nested deeper than 10 fields" {
+		t.Fatalf("errors %v, want the depth limit", result.Errors)
+	}
+}
@@ -0,0 +1,73 @@
+package graphql
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// maxQueryDepth bounds the nesting of fields, the schema is cyclic through todoItems and activity
+const maxQueryDepth = 10
+
+// operation returns the operation of doc a request runs, nil when it's missing or ambiguous so graphql.Do reports it
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		op, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+
+	return found
+}
+
+// fragments indexes the fragment definitions of doc by name
+func fragments(doc *ast.Document) map[string]*ast.FragmentDefinition {
+	byName := map[string]*ast.FragmentDefinition{}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			byName[fragment.Name.Value] = fragment
+		}
+	}
+
+	return byName
+}
+
+// depth returns the deepest nesting of fields in set, spreading a fragment again inside itself counts as too deep
+func depth(set *ast.SelectionSet, byName map[string]*ast.FragmentDefinition, spreading map[string]bool) int {
+	if set == nil {
+		return 0
+	}
+
+	deepest := 0
+	for _, selection := range set.Selections {
+		d := 0
+		switch s := selection.(type) {
+		case *ast.Field:
+			d = 1 + depth(s.SelectionSet, byName, spreading)
+		case *ast.InlineFragment:
+			d = depth(s.SelectionSet, byName, spreading)
+		case *ast.FragmentSpread:
+			fragment := byName[s.Name.Value]
+			if fragment == nil {
+				continue
+			}
+			if spreading[s.Name.Value] {
+				return maxQueryDepth + 1
+			}
+			spreading[s.Name.Value] = true
+			d = depth(fragment.SelectionSet, byName, spreading)
+			delete(spreading, s.Name.Value)
+		}
+		deepest = max(deepest, d)
+	}
+
+	return deepest
+}
@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type loadersKey struct{}
+
+// loaders are built per request so their caches never serve data across requests
+type loaders struct {
+	activityGroupById *dataloader.Loader[int, *entity.ActivityGroup]
+	todoItemCount     *dataloader.Loader[int, int]
+}
+
+func newLoaders(svcActivityGroup service.ActivityGroupService, svcTodoItem service.TodoItemService) *loaders {
+	return &loaders{
+		activityGroupById: dataloader.NewBatchedLoader(
+			activityGroupBatch(svcActivityGroup),
+			dataloader.WithWait[int, *entity.ActivityGroup](2*time.Millisecond),
+		),
+		todoItemCount: dataloader.NewBatchedLoader(
+			todoItemCountBatch(svcTodoItem),
+			dataloader.WithWait[int, int](2*time.Millisecond),
+		),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// activityGroupBatch replaces one FindById per todo item with a single FindByIds for the whole batch
+func activityGroupBatch(svcActivityGroup service.ActivityGroupService) dataloader.BatchFunc[int, *entity.ActivityGroup] {
+	return func(ctx context.Context, ids []int) []*dataloader.Result[*entity.ActivityGroup] {
+		results := make([]*dataloader.Result[*entity.ActivityGroup], len(ids))
+
+		activityGroupList, err := svcActivityGroup.FindByIds(ctx, dto.ActivityGroupIdsRequest{Ids: ids})
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*entity.ActivityGroup]{Error: err}
+			}
+			return results
+		}
+
+		byId := map[int]*entity.ActivityGroup{}
+		for _, activityGroup := range activityGroupList {
+			byId[activityGroup.ID] = activityGroup
+		}
+
+		for i, id := range ids {
+			if activityGroup, ok := byId[id]; ok {
+				results[i] = &dataloader.Result[*entity.ActivityGroup]{Data: activityGroup}
+			} else {
+				results[i] = &dataloader.Result[*entity.ActivityGroup]{Error: apperror.NotFound(apperror.CodeActivityGroupNotFound, "activity group not found")}
+			}
+		}
+
+		return results
+	}
+}
+
+func todoItemCountBatch(svcTodoItem service.TodoItemService) dataloader.BatchFunc[int, int] {
+	return func(ctx context.Context, activityIds []int) []*dataloader.Result[int] {
+		results := make([]*dataloader.Result[int], len(activityIds))
+
+		totals, err := svcTodoItem.CountByActivityIds(ctx, dto.TodoItemCountRequest{ActivityIds: activityIds})
+		for i, activityId := range activityIds {
+			results[i] = &dataloader.Result[int]{Data: totals[activityId], Error: err}
+		}
+
+		return results
+	}
+}
@@ -0,0 +1,304 @@
+package graphql
+
+import (
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/graphql-go/graphql"
+)
+
+type todoItemConnection struct {
+	Items      []*entity.TodoItem
+	Pagination *responsePkg.Pagination
+}
+
+type activityGroupConnection struct {
+	Items      []*entity.ActivityGroup
+	Pagination *responsePkg.Pagination
+}
+
+var paginationType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Pagination",
+	Fields: graphql.Fields{
+		"size":        &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: resolve(func(p *responsePkg.Pagination) any { return p.Size })},
+		"total":       &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: resolve(func(p *responsePkg.Pagination) any { return p.Total })},
+		"totalPages":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: resolve(func(p *responsePkg.Pagination) any { return p.TotalPages })},
+		"currentPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: resolve(func(p *responsePkg.Pagination) any { return p.CurrentPage })},
+	},
+})
+
+var listArgs = graphql.FieldConfigArgument{
+	"page":   &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+	"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 10},
+	"sortBy": &graphql.ArgumentConfig{Type: graphql.String, Description: "field.direction pairs separated by commas, e.g. name.asc,updated_at.desc"},
+	"filter": &graphql.ArgumentConfig{Type: graphql.String},
+}
+
+// resolve adapts a typed accessor into a field resolver over the parent source
+func resolve[T any](fn func(src T) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		src, ok := p.Source.(T)
+		if !ok {
+			return nil, nil
+		}
+		return fn(src), nil
+	}
+}
+
+func timeValue(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// timeArg reads an optional DateTime argument
+func timeArg(arg any) *time.Time {
+	t, ok := arg.(time.Time)
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+func NewSchema(svcActivityGroup service.ActivityGroupService, svcTodoItem service.TodoItemService) (graphql.Schema, error) {
+	activityGroupType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ActivityGroup",
+		Fields: graphql.Fields{
+			"uuid":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolve(func(e *entity.ActivityGroup) any { return e.Uuid })},
+			"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: resolve(func(e *entity.ActivityGroup) any { return e.Name })},
+			"description": &graphql.Field{Type: graphql.String, Resolve: resolve(func(e *entity.ActivityGroup) any { return e.Description })},
+			"createdAt":   &graphql.Field{Type: graphql.DateTime, Resolve: resolve(func(e *entity.ActivityGroup) any { return e.CreatedAt })},
+			"updatedAt":   &graphql.Field{Type: graphql.DateTime, Resolve: resolve(func(e *entity.ActivityGroup) any { return e.UpdatedAt })},
+		},
+	})
+
+	todoItemType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TodoItem",
+		Fields: graphql.Fields{
+			"uuid":        &graphql.Field{Type: graphql.NewNonNull(graphql.ID), Resolve: resolve(func(e *entity.TodoItem) any { return e.Uuid })},
+			"name":        &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: resolve(func(e *entity.TodoItem) any { return e.Name })},
+			"description": &graphql.Field{Type: graphql.String, Resolve: resolve(func(e *entity.TodoItem) any { return e.Description })},
+			"dueAt":       &graphql.Field{Type: graphql.DateTime, Resolve: resolve(func(e *entity.TodoItem) any { return timeValue(e.DueAt) })},
+			"isRecurring": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: resolve(func(e *entity.TodoItem) any { return e.SeriesID != nil })},
+			"isCompleted": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: resolve(func(e *entity.TodoItem) any { return e.CompletedAt != nil })},
+			"completedAt": &graphql.Field{Type: graphql.DateTime, Resolve: resolve(func(e *entity.TodoItem) any { return timeValue(e.CompletedAt) })},
+			"createdAt":   &graphql.Field{Type: graphql.DateTime, Resolve: resolve(func(e *entity.TodoItem) any { return e.CreatedAt })},
+			"updatedAt":   &graphql.Field{Type: graphql.DateTime, Resolve: resolve(func(e *entity.TodoItem) any { return e.UpdatedAt })},
+			"activity": &graphql.Field{
+				Type: activityGroupType,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					todoItem := p.Source.(*entity.TodoItem)
+					if todoItem.Activity != nil {
+						return todoItem.Activity, nil
+					}
+
+					thunk := loadersFrom(p.Context).activityGroupById.Load(p.Context, todoItem.ActivityID)
+					return func() (any, error) {
+						return thunk()
+					}, nil
+				},
+			},
+		},
+	})
+
+	todoItemConnectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TodoItemConnection",
+		Fields: graphql.Fields{
+			"items":      &graphql.Field{Type: graphql.NewList(todoItemType), Resolve: resolve(func(c *todoItemConnection) any { return c.Items })},
+			"pagination": &graphql.Field{Type: paginationType, Resolve: resolve(func(c *todoItemConnection) any { return c.Pagination })},
+		},
+	})
+
+	activityGroupConnectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ActivityGroupConnection",
+		Fields: graphql.Fields{
+			"items":      &graphql.Field{Type: graphql.NewList(activityGroupType), Resolve: resolve(func(c *activityGroupConnection) any { return c.Items })},
+			"pagination": &graphql.Field{Type: paginationType, Resolve: resolve(func(c *activityGroupConnection) any { return c.Pagination })},
+		},
+	})
+
+	activityGroupType.AddFieldConfig("todoItemCount", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.Int),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			activityGroup := p.Source.(*entity.ActivityGroup)
+
+			thunk := loadersFrom(p.Context).todoItemCount.Load(p.Context, activityGroup.ID)
+			return func() (any, error) {
+				return thunk()
+			}, nil
+		},
+	})
+	activityGroupType.AddFieldConfig("todoItems", &graphql.Field{
+		Type: todoItemConnectionType,
+		Args: listArgs,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			activityGroup := p.Source.(*entity.ActivityGroup)
+
+			req := dto.TodoItemFetchRequest{ActivityUuid: activityGroup.Uuid}
+			req.Page, _ = p.Args["page"].(int)
+			req.Limit, _ = p.Args["limit"].(int)
+			req.SortBy, _ = p.Args["sortBy"].(string)
+			req.Filter, _ = p.Args["filter"].(string)
+
+			todoItemList, pagination, err := svcTodoItem.FetchAll(p.Context, req)
+			if err != nil {
+				return nil, err
+			}
+
+			return &todoItemConnection{Items: todoItemList, Pagination: pagination}, nil
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"activityGroup": &graphql.Field{
+				Type: activityGroupType,
+				Args: graphql.FieldConfigArgument{
+					"uuid": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return svcActivityGroup.FindByUuid(p.Context, dto.ActivityGroupUuidRequest{Uuid: p.Args["uuid"].(string)})
+				},
+			},
+			"activityGroups": &graphql.Field{
+				Type: activityGroupConnectionType,
+				Args: listArgs,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					req := dto.ActivityGroupFetchRequest{}
+					req.Page, _ = p.Args["page"].(int)
+					req.Limit, _ = p.Args["limit"].(int)
+					req.SortBy, _ = p.Args["sortBy"].(string)
+					req.Filter, _ = p.Args["filter"].(string)
+
+					activityGroupList, pagination, err := svcActivityGroup.FetchAll(p.Context, req)
+					if err != nil {
+						return nil, err
+					}
+
+					return &activityGroupConnection{Items: activityGroupList, Pagination: pagination}, nil
+				},
+			},
+			"todoItem": &graphql.Field{
+				Type: todoItemType,
+				Args: graphql.FieldConfigArgument{
+					"uuid": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return svcTodoItem.FindByUuid(p.Context, dto.TodoItemUuidRequest{Uuid: p.Args["uuid"].(string)})
+				},
+			},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createActivityGroup": &graphql.Field{
+				Type: activityGroupType,
+				Args: graphql.FieldConfigArgument{
+					"name":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"description": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					req := dto.ActivityGroupCreateRequest{}
+					req.Name, _ = p.Args["name"].(string)
+					req.Description, _ = p.Args["description"].(string)
+
+					return svcActivityGroup.Create(p.Context, req)
+				},
+			},
+			"updateActivityGroup": &graphql.Field{
+				Type: activityGroupType,
+				Args: graphql.FieldConfigArgument{
+					"uuid":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"name":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"description": &graphql.ArgumentConfig{Type: graphql.String},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					req := dto.ActivityGroupUpdateRequest{}
+					req.Uuid, _ = p.Args["uuid"].(string)
+					req.Name, _ = p.Args["name"].(string)
+					req.Description, _ = p.Args["description"].(string)
+
+					return svcActivityGroup.Update(p.Context, req)
+				},
+			},
+			"deleteActivityGroup": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"uuid": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					err := svcActivityGroup.Delete(p.Context, dto.ActivityGroupUuidRequest{Uuid: p.Args["uuid"].(string)})
+					return err == nil, err
+				},
+			},
+			"createTodoItem": &graphql.Field{
+				Type: todoItemType,
+				Args: graphql.FieldConfigArgument{
+					"activityUuid": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"name":         &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"description":  &graphql.ArgumentConfig{Type: graphql.String},
+					"dueAt":        &graphql.ArgumentConfig{Type: graphql.DateTime},
+					"recurrence":   &graphql.ArgumentConfig{Type: graphql.String, Description: "RRULE such as FREQ=WEEKLY;BYDAY=MO, needs dueAt"},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					req := dto.TodoItemCreateRequest{}
+					req.ActivityUuid, _ = p.Args["activityUuid"].(string)
+					req.Name, _ = p.Args["name"].(string)
+					req.Description, _ = p.Args["description"].(string)
+					req.DueAt = timeArg(p.Args["dueAt"])
+					req.Recurrence, _ = p.Args["recurrence"].(string)
+
+					return svcTodoItem.Create(p.Context, req)
+				},
+			},
+			"updateTodoItem": &graphql.Field{
+				Type: todoItemType,
+				Args: graphql.FieldConfigArgument{
+					"uuid":         &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"activityUuid": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"name":         &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"description":  &graphql.ArgumentConfig{Type: graphql.String},
+					"dueAt":        &graphql.ArgumentConfig{Type: graphql.DateTime, Description: "Moves the due date, it is kept when omitted"},
+					"clearDueAt":   &graphql.ArgumentConfig{Type: graphql.Boolean, Description: "Removes the due date"},
+					"isCompleted":  &graphql.ArgumentConfig{Type: graphql.Boolean, Description: "Completes or reopens the item, it keeps its completion when omitted"},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					req := dto.TodoItemUpdateRequest{}
+					req.Uuid, _ = p.Args["uuid"].(string)
+					req.ActivityUuid, _ = p.Args["activityUuid"].(string)
+					req.Name, _ = p.Args["name"].(string)
+					req.Description, _ = p.Args["description"].(string)
+					req.DueAt = timeArg(p.Args["dueAt"])
+					req.ClearDueAt, _ = p.Args["clearDueAt"].(bool)
+					if isCompleted, ok := p.Args["isCompleted"].(bool); ok {
+						req.IsCompleted = &isCompleted
+					}
+
+					return svcTodoItem.Update(p.Context, req)
+				},
+			},
+			"deleteTodoItem": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"uuid": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					err := svcTodoItem.Delete(p.Context, dto.TodoItemUuidRequest{Uuid: p.Args["uuid"].(string)})
+					return err == nil, err
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
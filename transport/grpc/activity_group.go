@@ -0,0 +1,119 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/Adhiana46/go-restapi-template/transport/grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type activityGroupServer struct {
+	pb.UnimplementedActivityGroupServiceServer
+
+	hub stream.Hub
+
+	svcActivityGroup service.ActivityGroupService
+	svcActivityEvent service.ActivityEventService
+}
+
+func RegisterActivityGroupServer(s *grpc.Server, hub stream.Hub, svcActivityGroup service.ActivityGroupService, svcActivityEvent service.ActivityEventService) {
+	pb.RegisterActivityGroupServiceServer(s, &activityGroupServer{
+		hub:              hub,
+		svcActivityGroup: svcActivityGroup,
+		svcActivityEvent: svcActivityEvent,
+	})
+}
+
+func (s *activityGroupServer) Find(ctx context.Context, req *pb.FindActivityGroupRequest) (*pb.ActivityGroup, error) {
+	activityGroup, err := s.svcActivityGroup.FindByUuid(ctx, dto.ActivityGroupUuidRequest{
+		Uuid: req.GetUuid(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return activityGroupToPb(dto.ActivityGroupToResponse(activityGroup)), nil
+}
+
+func (s *activityGroupServer) List(ctx context.Context, req *pb.ListActivityGroupsRequest) (*pb.ListActivityGroupsResponse, error) {
+	activityGroupList, pagination, err := s.svcActivityGroup.FetchAll(ctx, dto.ActivityGroupFetchRequest{
+		Page:   int(req.GetPage()),
+		Limit:  int(req.GetLimit()),
+		SortBy: req.GetSortBy(),
+		Filter: req.GetFilter(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListActivityGroupsResponse{
+		Items:      []*pb.ActivityGroup{},
+		Pagination: paginationToPb(pagination),
+	}
+	for _, activityGroup := range dto.ActivityGroupToResponseList(activityGroupList) {
+		resp.Items = append(resp.Items, activityGroupToPb(activityGroup))
+	}
+
+	return resp, nil
+}
+
+func (s *activityGroupServer) Create(ctx context.Context, req *pb.CreateActivityGroupRequest) (*pb.ActivityGroup, error) {
+	activityGroup, err := s.svcActivityGroup.Create(ctx, dto.ActivityGroupCreateRequest{
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return activityGroupToPb(dto.ActivityGroupToResponse(activityGroup)), nil
+}
+
+func (s *activityGroupServer) Update(ctx context.Context, req *pb.UpdateActivityGroupRequest) (*pb.ActivityGroup, error) {
+	activityGroup, err := s.svcActivityGroup.Update(ctx, dto.ActivityGroupUpdateRequest{
+		Uuid:        req.GetUuid(),
+		Name:        req.GetName(),
+		Description: req.GetDescription(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return activityGroupToPb(dto.ActivityGroupToResponse(activityGroup)), nil
+}
+
+func (s *activityGroupServer) Delete(ctx context.Context, req *pb.DeleteActivityGroupRequest) (*emptypb.Empty, error) {
+	err := s.svcActivityGroup.Delete(ctx, dto.ActivityGroupUuidRequest{
+		Uuid: req.GetUuid(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *activityGroupServer) Watch(req *pb.WatchActivityGroupRequest, stream grpc.ServerStreamingServer[pb.ChangeEvent]) error {
+	activityGroup, err := s.svcActivityGroup.FindByUuid(stream.Context(), dto.ActivityGroupUuidRequest{
+		Uuid: req.GetUuid(),
+	})
+	if err != nil {
+		return toStatus(err)
+	}
+
+	matchAll := func(e *entity.ActivityEvent) bool {
+		return true
+	}
+
+	return watch(stream.Context(), s.hub, s.svcActivityEvent, activityGroup.Uuid, req.GetLastEventId(), matchAll, stream.Send)
+}
+
+func isTodoItemEvent(e *entity.ActivityEvent) bool {
+	return strings.HasPrefix(e.Event, "todo-item.")
+}
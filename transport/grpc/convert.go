@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/Adhiana46/go-restapi-template/transport/grpc/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func activityGroupToPb(e *dto.ActivityGroupResponse) *pb.ActivityGroup {
+	if e == nil {
+		return nil
+	}
+
+	return &pb.ActivityGroup{
+		Uuid:        e.Uuid,
+		Name:        e.Name,
+		Description: e.Description,
+		CreatedAt:   timestamppb.New(e.CreatedAt),
+		UpdatedAt:   timestamppb.New(e.UpdatedAt),
+	}
+}
+
+func todoItemToPb(e *dto.TodoItemResponse) *pb.TodoItem {
+	return &pb.TodoItem{
+		Uuid:        e.Uuid,
+		ActivityId:  int32(e.ActivityID),
+		Name:        e.Name,
+		Description: e.Description,
+		IsCompleted: e.IsCompleted,
+		CompletedAt: timestampToPb(e.CompletedAt),
+		CreatedAt:   timestamppb.New(e.CreatedAt),
+		UpdatedAt:   timestamppb.New(e.UpdatedAt),
+		Activity:    activityGroupToPb(e.Activity),
+		DueAt:       timestampToPb(e.DueAt),
+		IsRecurring: e.IsRecurring,
+	}
+}
+
+func timestampToPb(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestamppb.New(*t)
+}
+
+func timestampFromPb(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	at := t.AsTime()
+	return &at
+}
+
+func paginationToPb(p *responsePkg.Pagination) *pb.Pagination {
+	return &pb.Pagination{
+		Size:        int32(p.Size),
+		Total:       int32(p.Total),
+		TotalPages:  int32(p.TotalPages),
+		CurrentPage: int32(p.CurrentPage),
+	}
+}
+
+// changeEventToPb decodes the event log payload back into the typed resource it carries
+func changeEventToPb(e *entity.ActivityEvent) (*pb.ChangeEvent, error) {
+	payload := struct {
+		Data       json.RawMessage `json:"data"`
+		OccurredAt time.Time       `json:"occurred_at"`
+	}{}
+	if err := json.Unmarshal([]byte(e.Payload), &payload); err != nil {
+		return nil, err
+	}
+
+	changeEvent := &pb.ChangeEvent{
+		Id:           e.ID,
+		Event:        e.Event,
+		ActivityUuid: e.ActivityUuid,
+		OccurredAt:   timestamppb.New(payload.OccurredAt),
+	}
+
+	if strings.HasPrefix(e.Event, "todo-item.") {
+		resp := &dto.TodoItemResponse{}
+		if err := json.Unmarshal(payload.Data, resp); err != nil {
+			return nil, err
+		}
+		changeEvent.Resource = &pb.ChangeEvent_TodoItem{TodoItem: todoItemToPb(resp)}
+	} else {
+		resp := &dto.ActivityGroupResponse{}
+		if err := json.Unmarshal(payload.Data, resp); err != nil {
+			return nil, err
+		}
+		changeEvent.Resource = &pb.ChangeEvent_ActivityGroup{ActivityGroup: activityGroupToPb(resp)}
+	}
+
+	return changeEvent, nil
+}
@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const errorDomain = "todo.v1"
+
+// toStatus maps service errors onto gRPC status codes, the stable error code travels as ErrorInfo reason
+// and validation errors carry their field violations
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	appErr := apperror.From(err)
+	st := status.New(grpcCode(appErr.Kind), appErr.Message)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: string(appErr.Code), Domain: errorDomain}}
+	if validationErrs := appErr.ValidationErrors(); validationErrs != nil {
+		badRequest := &errdetails.BadRequest{}
+		for _, e := range validationErrs {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       e.Field(),
+				Description: e.Error(),
+			})
+		}
+		details = append(details, badRequest)
+	}
+
+	if detailed, detailErr := st.WithDetails(details...); detailErr == nil {
+		return detailed.Err()
+	}
+
+	return st.Err()
+}
+
+func grpcCode(kind apperror.Kind) codes.Code {
+	switch kind {
+	case apperror.KindBadRequest, apperror.KindValidation:
+		return codes.InvalidArgument
+	case apperror.KindUnauthorized:
+		return codes.Unauthenticated
+	case apperror.KindForbidden:
+		return codes.PermissionDenied
+	case apperror.KindNotFound:
+		return codes.NotFound
+	case apperror.KindConflict:
+		return codes.AlreadyExists
+	}
+
+	return codes.Internal
+}
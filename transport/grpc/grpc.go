@@ -0,0 +1,79 @@
+package grpc
+
+//go:generate buf generate
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	log "github.com/sirupsen/logrus"
+)
+
+func NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(unaryLogInterceptor, unarySessionInterceptor, unaryRecoverInterceptor),
+		grpc.ChainStreamInterceptor(streamRecoverInterceptor),
+	)
+
+	return grpc.NewServer(opts...)
+}
+
+// unaryLogInterceptor tags the call with the x-request-id metadata it came with, or a new one, and logs it once done
+func unaryLogInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	started := time.Now()
+
+	requestId := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(strings.ToLower(logging.RequestIdHeader)); len(ids) > 0 {
+			requestId = ids[0]
+		}
+	}
+	requestId = logging.RequestId(requestId)
+	grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(logging.RequestIdHeader), requestId))
+
+	ctx = logging.WithFields(ctx, log.Fields{"request_id": requestId, "method": info.FullMethod})
+	resp, err := handler(ctx, req)
+
+	logging.FromContext(ctx).WithFields(log.Fields{
+		"code":        status.Code(err).String(),
+		"duration_ms": time.Since(started).Milliseconds(),
+	}).Infof("[grpc] %s %s", info.FullMethod, status.Code(err))
+
+	return resp, err
+}
+
+// unarySessionInterceptor runs every call as one database session, so with read-your-writes it reads what it wrote
+func unarySessionInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(sqldb.WithSession(ctx), req)
+}
+
+func unaryRecoverInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.FromContext(ctx).Errorf("[grpc] panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func streamRecoverInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logging.FromContext(ss.Context()).Errorf("[grpc] panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+
+	return handler(srv, ss)
+}
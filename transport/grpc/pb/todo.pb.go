@@ -0,0 +1,1456 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: todo.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Pagination struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Size          int32                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,3,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	CurrentPage   int32                  `protobuf:"varint,4,opt,name=current_page,json=currentPage,proto3" json:"current_page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pagination) Reset() {
+	*x = Pagination{}
+	mi := &file_todo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pagination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pagination) ProtoMessage() {}
+
+func (x *Pagination) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
+func (*Pagination) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Pagination) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Pagination) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Pagination) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *Pagination) GetCurrentPage() int32 {
+	if x != nil {
+		return x.CurrentPage
+	}
+	return 0
+}
+
+type ActivityGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityGroup) Reset() {
+	*x = ActivityGroup{}
+	mi := &file_todo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityGroup) ProtoMessage() {}
+
+func (x *ActivityGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityGroup.ProtoReflect.Descriptor instead.
+func (*ActivityGroup) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ActivityGroup) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ActivityGroup) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ActivityGroup) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ActivityGroup) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ActivityGroup) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type TodoItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	ActivityId    int32                  `protobuf:"varint,2,opt,name=activity_id,json=activityId,proto3" json:"activity_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	IsCompleted   bool                   `protobuf:"varint,5,opt,name=is_completed,json=isCompleted,proto3" json:"is_completed,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Activity      *ActivityGroup         `protobuf:"bytes,9,opt,name=activity,proto3" json:"activity,omitempty"`
+	DueAt         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	IsRecurring   bool                   `protobuf:"varint,11,opt,name=is_recurring,json=isRecurring,proto3" json:"is_recurring,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TodoItem) Reset() {
+	*x = TodoItem{}
+	mi := &file_todo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TodoItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TodoItem) ProtoMessage() {}
+
+func (x *TodoItem) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TodoItem.ProtoReflect.Descriptor instead.
+func (*TodoItem) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TodoItem) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *TodoItem) GetActivityId() int32 {
+	if x != nil {
+		return x.ActivityId
+	}
+	return 0
+}
+
+func (x *TodoItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TodoItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *TodoItem) GetIsCompleted() bool {
+	if x != nil {
+		return x.IsCompleted
+	}
+	return false
+}
+
+func (x *TodoItem) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetActivity() *ActivityGroup {
+	if x != nil {
+		return x.Activity
+	}
+	return nil
+}
+
+func (x *TodoItem) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *TodoItem) GetIsRecurring() bool {
+	if x != nil {
+		return x.IsRecurring
+	}
+	return false
+}
+
+// ChangeEvent is one entry of the activity event log, the same stream served over SSE and WebSocket
+type ChangeEvent struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Event        string                 `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	ActivityUuid string                 `protobuf:"bytes,3,opt,name=activity_uuid,json=activityUuid,proto3" json:"activity_uuid,omitempty"`
+	OccurredAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// Types that are valid to be assigned to Resource:
+	//
+	//	*ChangeEvent_ActivityGroup
+	//	*ChangeEvent_TodoItem
+	Resource      isChangeEvent_Resource `protobuf_oneof:"resource"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeEvent) Reset() {
+	*x = ChangeEvent{}
+	mi := &file_todo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEvent) ProtoMessage() {}
+
+func (x *ChangeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEvent.ProtoReflect.Descriptor instead.
+func (*ChangeEvent) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChangeEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ChangeEvent) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *ChangeEvent) GetActivityUuid() string {
+	if x != nil {
+		return x.ActivityUuid
+	}
+	return ""
+}
+
+func (x *ChangeEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *ChangeEvent) GetResource() isChangeEvent_Resource {
+	if x != nil {
+		return x.Resource
+	}
+	return nil
+}
+
+func (x *ChangeEvent) GetActivityGroup() *ActivityGroup {
+	if x != nil {
+		if x, ok := x.Resource.(*ChangeEvent_ActivityGroup); ok {
+			return x.ActivityGroup
+		}
+	}
+	return nil
+}
+
+func (x *ChangeEvent) GetTodoItem() *TodoItem {
+	if x != nil {
+		if x, ok := x.Resource.(*ChangeEvent_TodoItem); ok {
+			return x.TodoItem
+		}
+	}
+	return nil
+}
+
+type isChangeEvent_Resource interface {
+	isChangeEvent_Resource()
+}
+
+type ChangeEvent_ActivityGroup struct {
+	ActivityGroup *ActivityGroup `protobuf:"bytes,5,opt,name=activity_group,json=activityGroup,proto3,oneof"`
+}
+
+type ChangeEvent_TodoItem struct {
+	TodoItem *TodoItem `protobuf:"bytes,6,opt,name=todo_item,json=todoItem,proto3,oneof"`
+}
+
+func (*ChangeEvent_ActivityGroup) isChangeEvent_Resource() {}
+
+func (*ChangeEvent_TodoItem) isChangeEvent_Resource() {}
+
+type FindActivityGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindActivityGroupRequest) Reset() {
+	*x = FindActivityGroupRequest{}
+	mi := &file_todo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindActivityGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindActivityGroupRequest) ProtoMessage() {}
+
+func (x *FindActivityGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindActivityGroupRequest.ProtoReflect.Descriptor instead.
+func (*FindActivityGroupRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *FindActivityGroupRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type ListActivityGroupsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Page  int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// field.direction pairs separated by commas, e.g. name.asc,updated_at.desc
+	SortBy        string `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Filter        string `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActivityGroupsRequest) Reset() {
+	*x = ListActivityGroupsRequest{}
+	mi := &file_todo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActivityGroupsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActivityGroupsRequest) ProtoMessage() {}
+
+func (x *ListActivityGroupsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActivityGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListActivityGroupsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListActivityGroupsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListActivityGroupsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListActivityGroupsRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListActivityGroupsRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+type ListActivityGroupsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*ActivityGroup       `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Pagination    *Pagination            `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActivityGroupsResponse) Reset() {
+	*x = ListActivityGroupsResponse{}
+	mi := &file_todo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActivityGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActivityGroupsResponse) ProtoMessage() {}
+
+func (x *ListActivityGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActivityGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListActivityGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListActivityGroupsResponse) GetItems() []*ActivityGroup {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListActivityGroupsResponse) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type CreateActivityGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateActivityGroupRequest) Reset() {
+	*x = CreateActivityGroupRequest{}
+	mi := &file_todo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateActivityGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateActivityGroupRequest) ProtoMessage() {}
+
+func (x *CreateActivityGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateActivityGroupRequest.ProtoReflect.Descriptor instead.
+func (*CreateActivityGroupRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateActivityGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateActivityGroupRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type UpdateActivityGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateActivityGroupRequest) Reset() {
+	*x = UpdateActivityGroupRequest{}
+	mi := &file_todo_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateActivityGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateActivityGroupRequest) ProtoMessage() {}
+
+func (x *UpdateActivityGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateActivityGroupRequest.ProtoReflect.Descriptor instead.
+func (*UpdateActivityGroupRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateActivityGroupRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *UpdateActivityGroupRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateActivityGroupRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type DeleteActivityGroupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteActivityGroupRequest) Reset() {
+	*x = DeleteActivityGroupRequest{}
+	mi := &file_todo_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteActivityGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteActivityGroupRequest) ProtoMessage() {}
+
+func (x *DeleteActivityGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteActivityGroupRequest.ProtoReflect.Descriptor instead.
+func (*DeleteActivityGroupRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteActivityGroupRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type WatchActivityGroupRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Uuid  string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	// resume after this event id, zero only streams new events
+	LastEventId   int64 `protobuf:"varint,2,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchActivityGroupRequest) Reset() {
+	*x = WatchActivityGroupRequest{}
+	mi := &file_todo_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchActivityGroupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchActivityGroupRequest) ProtoMessage() {}
+
+func (x *WatchActivityGroupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchActivityGroupRequest.ProtoReflect.Descriptor instead.
+func (*WatchActivityGroupRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchActivityGroupRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *WatchActivityGroupRequest) GetLastEventId() int64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type FindTodoItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindTodoItemRequest) Reset() {
+	*x = FindTodoItemRequest{}
+	mi := &file_todo_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindTodoItemRequest) ProtoMessage() {}
+
+func (x *FindTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*FindTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FindTodoItemRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type ListTodoItemsRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ActivityUuid string                 `protobuf:"bytes,1,opt,name=activity_uuid,json=activityUuid,proto3" json:"activity_uuid,omitempty"`
+	Page         int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	Limit        int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// field.direction pairs separated by commas, e.g. name.asc,updated_at.desc
+	SortBy        string `protobuf:"bytes,4,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Filter        string `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoItemsRequest) Reset() {
+	*x = ListTodoItemsRequest{}
+	mi := &file_todo_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoItemsRequest) ProtoMessage() {}
+
+func (x *ListTodoItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoItemsRequest.ProtoReflect.Descriptor instead.
+func (*ListTodoItemsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListTodoItemsRequest) GetActivityUuid() string {
+	if x != nil {
+		return x.ActivityUuid
+	}
+	return ""
+}
+
+func (x *ListTodoItemsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListTodoItemsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTodoItemsRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListTodoItemsRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+type ListTodoItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TodoItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Pagination    *Pagination            `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTodoItemsResponse) Reset() {
+	*x = ListTodoItemsResponse{}
+	mi := &file_todo_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTodoItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTodoItemsResponse) ProtoMessage() {}
+
+func (x *ListTodoItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTodoItemsResponse.ProtoReflect.Descriptor instead.
+func (*ListTodoItemsResponse) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListTodoItemsResponse) GetItems() []*TodoItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListTodoItemsResponse) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type CreateTodoItemRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ActivityUuid string                 `protobuf:"bytes,1,opt,name=activity_uuid,json=activityUuid,proto3" json:"activity_uuid,omitempty"`
+	Name         string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description  string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	DueAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	// RRULE such as FREQ=WEEKLY;BYDAY=MO, the item is then the first occurrence of a series due from due_at
+	Recurrence    string `protobuf:"bytes,5,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTodoItemRequest) Reset() {
+	*x = CreateTodoItemRequest{}
+	mi := &file_todo_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTodoItemRequest) ProtoMessage() {}
+
+func (x *CreateTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*CreateTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateTodoItemRequest) GetActivityUuid() string {
+	if x != nil {
+		return x.ActivityUuid
+	}
+	return ""
+}
+
+func (x *CreateTodoItemRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateTodoItemRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateTodoItemRequest) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *CreateTodoItemRequest) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+type UpdateTodoItemRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Uuid         string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	ActivityUuid string                 `protobuf:"bytes,2,opt,name=activity_uuid,json=activityUuid,proto3" json:"activity_uuid,omitempty"`
+	Name         string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description  string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	// completes or reopens the item, it keeps its completion when unset
+	IsCompleted *bool `protobuf:"varint,5,opt,name=is_completed,json=isCompleted,proto3,oneof" json:"is_completed,omitempty"`
+	// moves the due date, it is kept when unset and removed with clear_due_at
+	DueAt         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=due_at,json=dueAt,proto3" json:"due_at,omitempty"`
+	ClearDueAt    bool                   `protobuf:"varint,7,opt,name=clear_due_at,json=clearDueAt,proto3" json:"clear_due_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTodoItemRequest) Reset() {
+	*x = UpdateTodoItemRequest{}
+	mi := &file_todo_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTodoItemRequest) ProtoMessage() {}
+
+func (x *UpdateTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateTodoItemRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *UpdateTodoItemRequest) GetActivityUuid() string {
+	if x != nil {
+		return x.ActivityUuid
+	}
+	return ""
+}
+
+func (x *UpdateTodoItemRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateTodoItemRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateTodoItemRequest) GetIsCompleted() bool {
+	if x != nil && x.IsCompleted != nil {
+		return *x.IsCompleted
+	}
+	return false
+}
+
+func (x *UpdateTodoItemRequest) GetDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueAt
+	}
+	return nil
+}
+
+func (x *UpdateTodoItemRequest) GetClearDueAt() bool {
+	if x != nil {
+		return x.ClearDueAt
+	}
+	return false
+}
+
+type DeleteTodoItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTodoItemRequest) Reset() {
+	*x = DeleteTodoItemRequest{}
+	mi := &file_todo_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTodoItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTodoItemRequest) ProtoMessage() {}
+
+func (x *DeleteTodoItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTodoItemRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTodoItemRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteTodoItemRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+type WatchTodoItemsRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ActivityUuid string                 `protobuf:"bytes,1,opt,name=activity_uuid,json=activityUuid,proto3" json:"activity_uuid,omitempty"`
+	// resume after this event id, zero only streams new events
+	LastEventId   int64 `protobuf:"varint,2,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTodoItemsRequest) Reset() {
+	*x = WatchTodoItemsRequest{}
+	mi := &file_todo_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTodoItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTodoItemsRequest) ProtoMessage() {}
+
+func (x *WatchTodoItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_todo_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTodoItemsRequest.ProtoReflect.Descriptor instead.
+func (*WatchTodoItemsRequest) Descriptor() ([]byte, []int) {
+	return file_todo_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WatchTodoItemsRequest) GetActivityUuid() string {
+	if x != nil {
+		return x.ActivityUuid
+	}
+	return ""
+}
+
+func (x *WatchTodoItemsRequest) GetLastEventId() int64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+var File_todo_proto protoreflect.FileDescriptor
+
+const file_todo_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"todo.proto\x12\atodo.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"z\n" +
+	"\n" +
+	"Pagination\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x05R\x04size\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x1f\n" +
+	"\vtotal_pages\x18\x03 \x01(\x05R\n" +
+	"totalPages\x12!\n" +
+	"\fcurrent_page\x18\x04 \x01(\x05R\vcurrentPage\"\xcf\x01\n" +
+	"\rActivityGroup\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xd7\x03\n" +
+	"\bTodoItem\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x1f\n" +
+	"\vactivity_id\x18\x02 \x01(\x05R\n" +
+	"activityId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12!\n" +
+	"\fis_completed\x18\x05 \x01(\bR\visCompleted\x12=\n" +
+	"\fcompleted_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x122\n" +
+	"\bactivity\x18\t \x01(\v2\x16.todo.v1.ActivityGroupR\bactivity\x121\n" +
+	"\x06due_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12!\n" +
+	"\fis_recurring\x18\v \x01(\bR\visRecurring\"\x94\x02\n" +
+	"\vChangeEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05event\x18\x02 \x01(\tR\x05event\x12#\n" +
+	"\ractivity_uuid\x18\x03 \x01(\tR\factivityUuid\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12?\n" +
+	"\x0eactivity_group\x18\x05 \x01(\v2\x16.todo.v1.ActivityGroupH\x00R\ractivityGroup\x120\n" +
+	"\ttodo_item\x18\x06 \x01(\v2\x11.todo.v1.TodoItemH\x00R\btodoItemB\n" +
+	"\n" +
+	"\bresource\".\n" +
+	"\x18FindActivityGroupRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"v\n" +
+	"\x19ListActivityGroupsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\x12\x16\n" +
+	"\x06filter\x18\x04 \x01(\tR\x06filter\"\x7f\n" +
+	"\x1aListActivityGroupsResponse\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.todo.v1.ActivityGroupR\x05items\x123\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x13.todo.v1.PaginationR\n" +
+	"pagination\"R\n" +
+	"\x1aCreateActivityGroupRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\"f\n" +
+	"\x1aUpdateActivityGroupRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"0\n" +
+	"\x1aDeleteActivityGroupRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"S\n" +
+	"\x19WatchActivityGroupRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12\"\n" +
+	"\rlast_event_id\x18\x02 \x01(\x03R\vlastEventId\")\n" +
+	"\x13FindTodoItemRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"\x96\x01\n" +
+	"\x14ListTodoItemsRequest\x12#\n" +
+	"\ractivity_uuid\x18\x01 \x01(\tR\factivityUuid\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x17\n" +
+	"\asort_by\x18\x04 \x01(\tR\x06sortBy\x12\x16\n" +
+	"\x06filter\x18\x05 \x01(\tR\x06filter\"u\n" +
+	"\x15ListTodoItemsResponse\x12'\n" +
+	"\x05items\x18\x01 \x03(\v2\x11.todo.v1.TodoItemR\x05items\x123\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x13.todo.v1.PaginationR\n" +
+	"pagination\"\xc5\x01\n" +
+	"\x15CreateTodoItemRequest\x12#\n" +
+	"\ractivity_uuid\x18\x01 \x01(\tR\factivityUuid\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x121\n" +
+	"\x06due_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12\x1e\n" +
+	"\n" +
+	"recurrence\x18\x05 \x01(\tR\n" +
+	"recurrence\"\x94\x02\n" +
+	"\x15UpdateTodoItemRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12#\n" +
+	"\ractivity_uuid\x18\x02 \x01(\tR\factivityUuid\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12&\n" +
+	"\fis_completed\x18\x05 \x01(\bH\x00R\visCompleted\x88\x01\x01\x121\n" +
+	"\x06due_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x05dueAt\x12 \n" +
+	"\fclear_due_at\x18\a \x01(\bR\n" +
+	"clearDueAtB\x0f\n" +
+	"\r_is_completed\"+\n" +
+	"\x15DeleteTodoItemRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"`\n" +
+	"\x15WatchTodoItemsRequest\x12#\n" +
+	"\ractivity_uuid\x18\x01 \x01(\tR\factivityUuid\x12\"\n" +
+	"\rlast_event_id\x18\x02 \x01(\x03R\vlastEventId2\xc4\x03\n" +
+	"\x14ActivityGroupService\x12A\n" +
+	"\x04Find\x12!.todo.v1.FindActivityGroupRequest\x1a\x16.todo.v1.ActivityGroup\x12O\n" +
+	"\x04List\x12\".todo.v1.ListActivityGroupsRequest\x1a#.todo.v1.ListActivityGroupsResponse\x12E\n" +
+	"\x06Create\x12#.todo.v1.CreateActivityGroupRequest\x1a\x16.todo.v1.ActivityGroup\x12E\n" +
+	"\x06Update\x12#.todo.v1.UpdateActivityGroupRequest\x1a\x16.todo.v1.ActivityGroup\x12E\n" +
+	"\x06Delete\x12#.todo.v1.DeleteActivityGroupRequest\x1a\x16.google.protobuf.Empty\x12C\n" +
+	"\x05Watch\x12\".todo.v1.WatchActivityGroupRequest\x1a\x14.todo.v1.ChangeEvent0\x012\x8e\x03\n" +
+	"\x0fTodoItemService\x127\n" +
+	"\x04Find\x12\x1c.todo.v1.FindTodoItemRequest\x1a\x11.todo.v1.TodoItem\x12E\n" +
+	"\x04List\x12\x1d.todo.v1.ListTodoItemsRequest\x1a\x1e.todo.v1.ListTodoItemsResponse\x12;\n" +
+	"\x06Create\x12\x1e.todo.v1.CreateTodoItemRequest\x1a\x11.todo.v1.TodoItem\x12;\n" +
+	"\x06Update\x12\x1e.todo.v1.UpdateTodoItemRequest\x1a\x11.todo.v1.TodoItem\x12@\n" +
+	"\x06Delete\x12\x1e.todo.v1.DeleteTodoItemRequest\x1a\x16.google.protobuf.Empty\x12?\n" +
+	"\x05Watch\x12\x1e.todo.v1.WatchTodoItemsRequest\x1a\x14.todo.v1.ChangeEvent0\x01B?Z=github.com/Adhiana46/go-restapi-template/transport/grpc/pb;pbb\x06proto3"
+
+var (
+	file_todo_proto_rawDescOnce sync.Once
+	file_todo_proto_rawDescData []byte
+)
+
+func file_todo_proto_rawDescGZIP() []byte {
+	file_todo_proto_rawDescOnce.Do(func() {
+		file_todo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_todo_proto_rawDesc), len(file_todo_proto_rawDesc)))
+	})
+	return file_todo_proto_rawDescData
+}
+
+var file_todo_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_todo_proto_goTypes = []any{
+	(*Pagination)(nil),                 // 0: todo.v1.Pagination
+	(*ActivityGroup)(nil),              // 1: todo.v1.ActivityGroup
+	(*TodoItem)(nil),                   // 2: todo.v1.TodoItem
+	(*ChangeEvent)(nil),                // 3: todo.v1.ChangeEvent
+	(*FindActivityGroupRequest)(nil),   // 4: todo.v1.FindActivityGroupRequest
+	(*ListActivityGroupsRequest)(nil),  // 5: todo.v1.ListActivityGroupsRequest
+	(*ListActivityGroupsResponse)(nil), // 6: todo.v1.ListActivityGroupsResponse
+	(*CreateActivityGroupRequest)(nil), // 7: todo.v1.CreateActivityGroupRequest
+	(*UpdateActivityGroupRequest)(nil), // 8: todo.v1.UpdateActivityGroupRequest
+	(*DeleteActivityGroupRequest)(nil), // 9: todo.v1.DeleteActivityGroupRequest
+	(*WatchActivityGroupRequest)(nil),  // 10: todo.v1.WatchActivityGroupRequest
+	(*FindTodoItemRequest)(nil),        // 11: todo.v1.FindTodoItemRequest
+	(*ListTodoItemsRequest)(nil),       // 12: todo.v1.ListTodoItemsRequest
+	(*ListTodoItemsResponse)(nil),      // 13: todo.v1.ListTodoItemsResponse
+	(*CreateTodoItemRequest)(nil),      // 14: todo.v1.CreateTodoItemRequest
+	(*UpdateTodoItemRequest)(nil),      // 15: todo.v1.UpdateTodoItemRequest
+	(*DeleteTodoItemRequest)(nil),      // 16: todo.v1.DeleteTodoItemRequest
+	(*WatchTodoItemsRequest)(nil),      // 17: todo.v1.WatchTodoItemsRequest
+	(*timestamppb.Timestamp)(nil),      // 18: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),              // 19: google.protobuf.Empty
+}
+var file_todo_proto_depIdxs = []int32{
+	18, // 0: todo.v1.ActivityGroup.created_at:type_name -> google.protobuf.Timestamp
+	18, // 1: todo.v1.ActivityGroup.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 2: todo.v1.TodoItem.completed_at:type_name -> google.protobuf.Timestamp
+	18, // 3: todo.v1.TodoItem.created_at:type_name -> google.protobuf.Timestamp
+	18, // 4: todo.v1.TodoItem.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 5: todo.v1.TodoItem.activity:type_name -> todo.v1.ActivityGroup
+	18, // 6: todo.v1.TodoItem.due_at:type_name -> google.protobuf.Timestamp
+	18, // 7: todo.v1.ChangeEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	1,  // 8: todo.v1.ChangeEvent.activity_group:type_name -> todo.v1.ActivityGroup
+	2,  // 9: todo.v1.ChangeEvent.todo_item:type_name -> todo.v1.TodoItem
+	1,  // 10: todo.v1.ListActivityGroupsResponse.items:type_name -> todo.v1.ActivityGroup
+	0,  // 11: todo.v1.ListActivityGroupsResponse.pagination:type_name -> todo.v1.Pagination
+	2,  // 12: todo.v1.ListTodoItemsResponse.items:type_name -> todo.v1.TodoItem
+	0,  // 13: todo.v1.ListTodoItemsResponse.pagination:type_name -> todo.v1.Pagination
+	18, // 14: todo.v1.CreateTodoItemRequest.due_at:type_name -> google.protobuf.Timestamp
+	18, // 15: todo.v1.UpdateTodoItemRequest.due_at:type_name -> google.protobuf.Timestamp
+	4,  // 16: todo.v1.ActivityGroupService.Find:input_type -> todo.v1.FindActivityGroupRequest
+	5,  // 17: todo.v1.ActivityGroupService.List:input_type -> todo.v1.ListActivityGroupsRequest
+	7,  // 18: todo.v1.ActivityGroupService.Create:input_type -> todo.v1.CreateActivityGroupRequest
+	8,  // 19: todo.v1.ActivityGroupService.Update:input_type -> todo.v1.UpdateActivityGroupRequest
+	9,  // 20: todo.v1.ActivityGroupService.Delete:input_type -> todo.v1.DeleteActivityGroupRequest
+	10, // 21: todo.v1.ActivityGroupService.Watch:input_type -> todo.v1.WatchActivityGroupRequest
+	11, // 22: todo.v1.TodoItemService.Find:input_type -> todo.v1.FindTodoItemRequest
+	12, // 23: todo.v1.TodoItemService.List:input_type -> todo.v1.ListTodoItemsRequest
+	14, // 24: todo.v1.TodoItemService.Create:input_type -> todo.v1.CreateTodoItemRequest
+	15, // 25: todo.v1.TodoItemService.Update:input_type -> todo.v1.UpdateTodoItemRequest
+	16, // 26: todo.v1.TodoItemService.Delete:input_type -> todo.v1.DeleteTodoItemRequest
+	17, // 27: todo.v1.TodoItemService.Watch:input_type -> todo.v1.WatchTodoItemsRequest
+	1,  // 28: todo.v1.ActivityGroupService.Find:output_type -> todo.v1.ActivityGroup
+	6,  // 29: todo.v1.ActivityGroupService.List:output_type -> todo.v1.ListActivityGroupsResponse
+	1,  // 30: todo.v1.ActivityGroupService.Create:output_type -> todo.v1.ActivityGroup
+	1,  // 31: todo.v1.ActivityGroupService.Update:output_type -> todo.v1.ActivityGroup
+	19, // 32: todo.v1.ActivityGroupService.Delete:output_type -> google.protobuf.Empty
+	3,  // 33: todo.v1.ActivityGroupService.Watch:output_type -> todo.v1.ChangeEvent
+	2,  // 34: todo.v1.TodoItemService.Find:output_type -> todo.v1.TodoItem
+	13, // 35: todo.v1.TodoItemService.List:output_type -> todo.v1.ListTodoItemsResponse
+	2,  // 36: todo.v1.TodoItemService.Create:output_type -> todo.v1.TodoItem
+	2,  // 37: todo.v1.TodoItemService.Update:output_type -> todo.v1.TodoItem
+	19, // 38: todo.v1.TodoItemService.Delete:output_type -> google.protobuf.Empty
+	3,  // 39: todo.v1.TodoItemService.Watch:output_type -> todo.v1.ChangeEvent
+	28, // [28:40] is the sub-list for method output_type
+	16, // [16:28] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_todo_proto_init() }
+func file_todo_proto_init() {
+	if File_todo_proto != nil {
+		return
+	}
+	file_todo_proto_msgTypes[3].OneofWrappers = []any{
+		(*ChangeEvent_ActivityGroup)(nil),
+		(*ChangeEvent_TodoItem)(nil),
+	}
+	file_todo_proto_msgTypes[15].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_todo_proto_rawDesc), len(file_todo_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_todo_proto_goTypes,
+		DependencyIndexes: file_todo_proto_depIdxs,
+		MessageInfos:      file_todo_proto_msgTypes,
+	}.Build()
+	File_todo_proto = out.File
+	file_todo_proto_goTypes = nil
+	file_todo_proto_depIdxs = nil
+}
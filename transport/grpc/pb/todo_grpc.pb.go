@@ -0,0 +1,612 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: todo.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ActivityGroupService_Find_FullMethodName   = "/todo.v1.ActivityGroupService/Find"
+	ActivityGroupService_List_FullMethodName   = "/todo.v1.ActivityGroupService/List"
+	ActivityGroupService_Create_FullMethodName = "/todo.v1.ActivityGroupService/Create"
+	ActivityGroupService_Update_FullMethodName = "/todo.v1.ActivityGroupService/Update"
+	ActivityGroupService_Delete_FullMethodName = "/todo.v1.ActivityGroupService/Delete"
+	ActivityGroupService_Watch_FullMethodName  = "/todo.v1.ActivityGroupService/Watch"
+)
+
+// ActivityGroupServiceClient is the client API for ActivityGroupService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ActivityGroupServiceClient interface {
+	Find(ctx context.Context, in *FindActivityGroupRequest, opts ...grpc.CallOption) (*ActivityGroup, error)
+	List(ctx context.Context, in *ListActivityGroupsRequest, opts ...grpc.CallOption) (*ListActivityGroupsResponse, error)
+	Create(ctx context.Context, in *CreateActivityGroupRequest, opts ...grpc.CallOption) (*ActivityGroup, error)
+	Update(ctx context.Context, in *UpdateActivityGroupRequest, opts ...grpc.CallOption) (*ActivityGroup, error)
+	Delete(ctx context.Context, in *DeleteActivityGroupRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Watch(ctx context.Context, in *WatchActivityGroupRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChangeEvent], error)
+}
+
+type activityGroupServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewActivityGroupServiceClient(cc grpc.ClientConnInterface) ActivityGroupServiceClient {
+	return &activityGroupServiceClient{cc}
+}
+
+func (c *activityGroupServiceClient) Find(ctx context.Context, in *FindActivityGroupRequest, opts ...grpc.CallOption) (*ActivityGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActivityGroup)
+	err := c.cc.Invoke(ctx, ActivityGroupService_Find_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *activityGroupServiceClient) List(ctx context.Context, in *ListActivityGroupsRequest, opts ...grpc.CallOption) (*ListActivityGroupsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListActivityGroupsResponse)
+	err := c.cc.Invoke(ctx, ActivityGroupService_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *activityGroupServiceClient) Create(ctx context.Context, in *CreateActivityGroupRequest, opts ...grpc.CallOption) (*ActivityGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActivityGroup)
+	err := c.cc.Invoke(ctx, ActivityGroupService_Create_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *activityGroupServiceClient) Update(ctx context.Context, in *UpdateActivityGroupRequest, opts ...grpc.CallOption) (*ActivityGroup, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ActivityGroup)
+	err := c.cc.Invoke(ctx, ActivityGroupService_Update_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *activityGroupServiceClient) Delete(ctx context.Context, in *DeleteActivityGroupRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ActivityGroupService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *activityGroupServiceClient) Watch(ctx context.Context, in *WatchActivityGroupRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChangeEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ActivityGroupService_ServiceDesc.Streams[0], ActivityGroupService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchActivityGroupRequest, ChangeEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ActivityGroupService_WatchClient = grpc.ServerStreamingClient[ChangeEvent]
+
+// ActivityGroupServiceServer is the server API for ActivityGroupService service.
+// All implementations must embed UnimplementedActivityGroupServiceServer
+// for forward compatibility.
+type ActivityGroupServiceServer interface {
+	Find(context.Context, *FindActivityGroupRequest) (*ActivityGroup, error)
+	List(context.Context, *ListActivityGroupsRequest) (*ListActivityGroupsResponse, error)
+	Create(context.Context, *CreateActivityGroupRequest) (*ActivityGroup, error)
+	Update(context.Context, *UpdateActivityGroupRequest) (*ActivityGroup, error)
+	Delete(context.Context, *DeleteActivityGroupRequest) (*emptypb.Empty, error)
+	Watch(*WatchActivityGroupRequest, grpc.ServerStreamingServer[ChangeEvent]) error
+	mustEmbedUnimplementedActivityGroupServiceServer()
+}
+
+// UnimplementedActivityGroupServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedActivityGroupServiceServer struct{}
+
+func (UnimplementedActivityGroupServiceServer) Find(context.Context, *FindActivityGroupRequest) (*ActivityGroup, error) {
+	return nil, status.Error(codes.Unimplemented, "method Find not implemented")
+}
+func (UnimplementedActivityGroupServiceServer) List(context.Context, *ListActivityGroupsRequest) (*ListActivityGroupsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedActivityGroupServiceServer) Create(context.Context, *CreateActivityGroupRequest) (*ActivityGroup, error) {
+	return nil, status.Error(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedActivityGroupServiceServer) Update(context.Context, *UpdateActivityGroupRequest) (*ActivityGroup, error) {
+	return nil, status.Error(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedActivityGroupServiceServer) Delete(context.Context, *DeleteActivityGroupRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedActivityGroupServiceServer) Watch(*WatchActivityGroupRequest, grpc.ServerStreamingServer[ChangeEvent]) error {
+	return status.Error(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedActivityGroupServiceServer) mustEmbedUnimplementedActivityGroupServiceServer() {}
+func (UnimplementedActivityGroupServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeActivityGroupServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ActivityGroupServiceServer will
+// result in compilation errors.
+type UnsafeActivityGroupServiceServer interface {
+	mustEmbedUnimplementedActivityGroupServiceServer()
+}
+
+func RegisterActivityGroupServiceServer(s grpc.ServiceRegistrar, srv ActivityGroupServiceServer) {
+	// If the following call panics, it indicates UnimplementedActivityGroupServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ActivityGroupService_ServiceDesc, srv)
+}
+
+func _ActivityGroupService_Find_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindActivityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivityGroupServiceServer).Find(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActivityGroupService_Find_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivityGroupServiceServer).Find(ctx, req.(*FindActivityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActivityGroupService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActivityGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivityGroupServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActivityGroupService_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivityGroupServiceServer).List(ctx, req.(*ListActivityGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActivityGroupService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateActivityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivityGroupServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActivityGroupService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivityGroupServiceServer).Create(ctx, req.(*CreateActivityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActivityGroupService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateActivityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivityGroupServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActivityGroupService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivityGroupServiceServer).Update(ctx, req.(*UpdateActivityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActivityGroupService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteActivityGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActivityGroupServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActivityGroupService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActivityGroupServiceServer).Delete(ctx, req.(*DeleteActivityGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActivityGroupService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchActivityGroupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActivityGroupServiceServer).Watch(m, &grpc.GenericServerStream[WatchActivityGroupRequest, ChangeEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ActivityGroupService_WatchServer = grpc.ServerStreamingServer[ChangeEvent]
+
+// ActivityGroupService_ServiceDesc is the grpc.ServiceDesc for ActivityGroupService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ActivityGroupService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todo.v1.ActivityGroupService",
+	HandlerType: (*ActivityGroupServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Find",
+			Handler:    _ActivityGroupService_Find_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _ActivityGroupService_List_Handler,
+		},
+		{
+			MethodName: "Create",
+			Handler:    _ActivityGroupService_Create_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _ActivityGroupService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _ActivityGroupService_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _ActivityGroupService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "todo.proto",
+}
+
+const (
+	TodoItemService_Find_FullMethodName   = "/todo.v1.TodoItemService/Find"
+	TodoItemService_List_FullMethodName   = "/todo.v1.TodoItemService/List"
+	TodoItemService_Create_FullMethodName = "/todo.v1.TodoItemService/Create"
+	TodoItemService_Update_FullMethodName = "/todo.v1.TodoItemService/Update"
+	TodoItemService_Delete_FullMethodName = "/todo.v1.TodoItemService/Delete"
+	TodoItemService_Watch_FullMethodName  = "/todo.v1.TodoItemService/Watch"
+)
+
+// TodoItemServiceClient is the client API for TodoItemService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TodoItemServiceClient interface {
+	Find(ctx context.Context, in *FindTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	List(ctx context.Context, in *ListTodoItemsRequest, opts ...grpc.CallOption) (*ListTodoItemsResponse, error)
+	Create(ctx context.Context, in *CreateTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	Update(ctx context.Context, in *UpdateTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error)
+	Delete(ctx context.Context, in *DeleteTodoItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Watch(ctx context.Context, in *WatchTodoItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChangeEvent], error)
+}
+
+type todoItemServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTodoItemServiceClient(cc grpc.ClientConnInterface) TodoItemServiceClient {
+	return &todoItemServiceClient{cc}
+}
+
+func (c *todoItemServiceClient) Find(ctx context.Context, in *FindTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodoItemService_Find_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoItemServiceClient) List(ctx context.Context, in *ListTodoItemsRequest, opts ...grpc.CallOption) (*ListTodoItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTodoItemsResponse)
+	err := c.cc.Invoke(ctx, TodoItemService_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoItemServiceClient) Create(ctx context.Context, in *CreateTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodoItemService_Create_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoItemServiceClient) Update(ctx context.Context, in *UpdateTodoItemRequest, opts ...grpc.CallOption) (*TodoItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TodoItem)
+	err := c.cc.Invoke(ctx, TodoItemService_Update_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoItemServiceClient) Delete(ctx context.Context, in *DeleteTodoItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, TodoItemService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoItemServiceClient) Watch(ctx context.Context, in *WatchTodoItemsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChangeEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TodoItemService_ServiceDesc.Streams[0], TodoItemService_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTodoItemsRequest, ChangeEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoItemService_WatchClient = grpc.ServerStreamingClient[ChangeEvent]
+
+// TodoItemServiceServer is the server API for TodoItemService service.
+// All implementations must embed UnimplementedTodoItemServiceServer
+// for forward compatibility.
+type TodoItemServiceServer interface {
+	Find(context.Context, *FindTodoItemRequest) (*TodoItem, error)
+	List(context.Context, *ListTodoItemsRequest) (*ListTodoItemsResponse, error)
+	Create(context.Context, *CreateTodoItemRequest) (*TodoItem, error)
+	Update(context.Context, *UpdateTodoItemRequest) (*TodoItem, error)
+	Delete(context.Context, *DeleteTodoItemRequest) (*emptypb.Empty, error)
+	Watch(*WatchTodoItemsRequest, grpc.ServerStreamingServer[ChangeEvent]) error
+	mustEmbedUnimplementedTodoItemServiceServer()
+}
+
+// UnimplementedTodoItemServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTodoItemServiceServer struct{}
+
+func (UnimplementedTodoItemServiceServer) Find(context.Context, *FindTodoItemRequest) (*TodoItem, error) {
+	return nil, status.Error(codes.Unimplemented, "method Find not implemented")
+}
+func (UnimplementedTodoItemServiceServer) List(context.Context, *ListTodoItemsRequest) (*ListTodoItemsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedTodoItemServiceServer) Create(context.Context, *CreateTodoItemRequest) (*TodoItem, error) {
+	return nil, status.Error(codes.Unimplemented, "method Create not implemented")
+}
+func (UnimplementedTodoItemServiceServer) Update(context.Context, *UpdateTodoItemRequest) (*TodoItem, error) {
+	return nil, status.Error(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedTodoItemServiceServer) Delete(context.Context, *DeleteTodoItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedTodoItemServiceServer) Watch(*WatchTodoItemsRequest, grpc.ServerStreamingServer[ChangeEvent]) error {
+	return status.Error(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedTodoItemServiceServer) mustEmbedUnimplementedTodoItemServiceServer() {}
+func (UnimplementedTodoItemServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeTodoItemServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TodoItemServiceServer will
+// result in compilation errors.
+type UnsafeTodoItemServiceServer interface {
+	mustEmbedUnimplementedTodoItemServiceServer()
+}
+
+func RegisterTodoItemServiceServer(s grpc.ServiceRegistrar, srv TodoItemServiceServer) {
+	// If the following call panics, it indicates UnimplementedTodoItemServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TodoItemService_ServiceDesc, srv)
+}
+
+func _TodoItemService_Find_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoItemServiceServer).Find(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoItemService_Find_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoItemServiceServer).Find(ctx, req.(*FindTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoItemService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoItemServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoItemService_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoItemServiceServer).List(ctx, req.(*ListTodoItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoItemService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoItemServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoItemService_Create_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoItemServiceServer).Create(ctx, req.(*CreateTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoItemService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoItemServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoItemService_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoItemServiceServer).Update(ctx, req.(*UpdateTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoItemService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTodoItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoItemServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TodoItemService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoItemServiceServer).Delete(ctx, req.(*DeleteTodoItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoItemService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTodoItemsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoItemServiceServer).Watch(m, &grpc.GenericServerStream[WatchTodoItemsRequest, ChangeEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TodoItemService_WatchServer = grpc.ServerStreamingServer[ChangeEvent]
+
+// TodoItemService_ServiceDesc is the grpc.ServiceDesc for TodoItemService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TodoItemService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "todo.v1.TodoItemService",
+	HandlerType: (*TodoItemServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Find",
+			Handler:    _TodoItemService_Find_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _TodoItemService_List_Handler,
+		},
+		{
+			MethodName: "Create",
+			Handler:    _TodoItemService_Create_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _TodoItemService_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _TodoItemService_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _TodoItemService_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "todo.proto",
+}
@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/Adhiana46/go-restapi-template/transport/grpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type todoItemServer struct {
+	pb.UnimplementedTodoItemServiceServer
+
+	hub stream.Hub
+
+	svcActivityGroup service.ActivityGroupService
+	svcTodoItem      service.TodoItemService
+	svcActivityEvent service.ActivityEventService
+}
+
+func RegisterTodoItemServer(s *grpc.Server, hub stream.Hub, svcActivityGroup service.ActivityGroupService, svcTodoItem service.TodoItemService, svcActivityEvent service.ActivityEventService) {
+	pb.RegisterTodoItemServiceServer(s, &todoItemServer{
+		hub:              hub,
+		svcActivityGroup: svcActivityGroup,
+		svcTodoItem:      svcTodoItem,
+		svcActivityEvent: svcActivityEvent,
+	})
+}
+
+func (s *todoItemServer) Find(ctx context.Context, req *pb.FindTodoItemRequest) (*pb.TodoItem, error) {
+	todoItem, err := s.svcTodoItem.FindByUuid(ctx, dto.TodoItemUuidRequest{
+		Uuid: req.GetUuid(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return todoItemToPb(dto.TodoItemToResponse(todoItem)), nil
+}
+
+func (s *todoItemServer) List(ctx context.Context, req *pb.ListTodoItemsRequest) (*pb.ListTodoItemsResponse, error) {
+	todoItemList, pagination, err := s.svcTodoItem.FetchAll(ctx, dto.TodoItemFetchRequest{
+		ActivityUuid: req.GetActivityUuid(),
+		Page:         int(req.GetPage()),
+		Limit:        int(req.GetLimit()),
+		SortBy:       req.GetSortBy(),
+		Filter:       req.GetFilter(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListTodoItemsResponse{
+		Items:      []*pb.TodoItem{},
+		Pagination: paginationToPb(pagination),
+	}
+	for _, todoItem := range dto.TodoItemToResponseList(todoItemList) {
+		resp.Items = append(resp.Items, todoItemToPb(todoItem))
+	}
+
+	return resp, nil
+}
+
+func (s *todoItemServer) Create(ctx context.Context, req *pb.CreateTodoItemRequest) (*pb.TodoItem, error) {
+	todoItem, err := s.svcTodoItem.Create(ctx, dto.TodoItemCreateRequest{
+		ActivityUuid: req.GetActivityUuid(),
+		Name:         req.GetName(),
+		Description:  req.GetDescription(),
+		DueAt:        timestampFromPb(req.GetDueAt()),
+		Recurrence:   req.GetRecurrence(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return todoItemToPb(dto.TodoItemToResponse(todoItem)), nil
+}
+
+func (s *todoItemServer) Update(ctx context.Context, req *pb.UpdateTodoItemRequest) (*pb.TodoItem, error) {
+	// unset is_completed and due_at keep the stored completion and due date
+	todoItem, err := s.svcTodoItem.Update(ctx, dto.TodoItemUpdateRequest{
+		Uuid:         req.GetUuid(),
+		ActivityUuid: req.GetActivityUuid(),
+		Name:         req.GetName(),
+		Description:  req.GetDescription(),
+		DueAt:        timestampFromPb(req.GetDueAt()),
+		ClearDueAt:   req.GetClearDueAt(),
+		IsCompleted:  req.IsCompleted,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return todoItemToPb(dto.TodoItemToResponse(todoItem)), nil
+}
+
+func (s *todoItemServer) Delete(ctx context.Context, req *pb.DeleteTodoItemRequest) (*emptypb.Empty, error) {
+	err := s.svcTodoItem.Delete(ctx, dto.TodoItemUuidRequest{
+		Uuid: req.GetUuid(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *todoItemServer) Watch(req *pb.WatchTodoItemsRequest, stream grpc.ServerStreamingServer[pb.ChangeEvent]) error {
+	activityGroup, err := s.svcActivityGroup.FindByUuid(stream.Context(), dto.ActivityGroupUuidRequest{
+		Uuid: req.GetActivityUuid(),
+	})
+	if err != nil {
+		return toStatus(err)
+	}
+
+	return watch(stream.Context(), s.hub, s.svcActivityEvent, activityGroup.Uuid, req.GetLastEventId(), isTodoItemEvent, stream.Send)
+}
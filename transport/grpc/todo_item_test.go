@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/transport/grpc/pb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeTodoItemService records the requests it gets, methods a test doesn't need panic through the embedded nil interface
+type fakeTodoItemService struct {
+	service.TodoItemService
+
+	created []dto.TodoItemCreateRequest
+	updated []dto.TodoItemUpdateRequest
+}
+
+func (s *fakeTodoItemService) Create(ctx context.Context, req dto.TodoItemCreateRequest) (*entity.TodoItem, error) {
+	s.created = append(s.created, req)
+
+	seriesId := 1
+	return &entity.TodoItem{Uuid: "item", Name: req.Name, DueAt: req.DueAt, SeriesID: &seriesId}, nil
+}
+
+func (s *fakeTodoItemService) Update(ctx context.Context, req dto.TodoItemUpdateRequest) (*entity.TodoItem, error) {
+	s.updated = append(s.updated, req)
+
+	return &entity.TodoItem{Uuid: req.Uuid, Name: req.Name}, nil
+}
+
+func TestCreateTodoItemWithRecurrence(t *testing.T) {
+	svc := &fakeTodoItemService{}
+	s := &todoItemServer{svcTodoItem: svc}
+
+	dueAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	todoItem, err := s.Create(context.Background(), &pb.CreateTodoItemRequest{
+		ActivityUuid: "activity",
+		Name:         "stand-up",
+		DueAt:        timestamppb.New(dueAt),
+		Recurrence:   "FREQ=WEEKLY;BYDAY=TU",
+	})
+	if err != nil {
+		t.Fatalf("creating: %s", err)
+	}
+
+	req := svc.created[0]
+	if req.DueAt == nil || !req.DueAt.Equal(dueAt) || req.Recurrence != "FREQ=WEEKLY;BYDAY=TU" {
+		t.Fatalf("service got due %v, recurrence %q", req.DueAt, req.Recurrence)
+	}
+	if !todoItem.GetDueAt().AsTime().Equal(dueAt) || !todoItem.GetIsRecurring() {
+		t.Fatalf("replied due %v, recurring %t", todoItem.GetDueAt(), todoItem.GetIsRecurring())
+	}
+}
+
+// TestUpdateTodoItemKeepsOmittedFields makes sure an update without is_completed and due_at
+// keeps the stored completion and due date
+func TestUpdateTodoItemKeepsOmittedFields(t *testing.T) {
+	svc := &fakeTodoItemService{}
+	s := &todoItemServer{svcTodoItem: svc}
+
+	if _, err := s.Update(context.Background(), &pb.UpdateTodoItemRequest{Uuid: "item", ActivityUuid: "activity", Name: "stand-up"}); err != nil {
+		t.Fatalf("updating: %s", err)
+	}
+
+	req := svc.updated[0]
+	if req.DueAt != nil || req.ClearDueAt {
+		t.Fatalf("update touched the due date: %v, clear %t", req.DueAt, req.ClearDueAt)
+	}
+	if req.IsCompleted != nil {
+		t.Fatalf("update set is_completed %t, want the completion kept", *req.IsCompleted)
+	}
+}
+
+func TestUpdateTodoItemSetsCompletionAndDueAt(t *testing.T) {
+	svc := &fakeTodoItemService{}
+	s := &todoItemServer{svcTodoItem: svc}
+
+	reopen := false
+	dueAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	if _, err := s.Update(context.Background(), &pb.UpdateTodoItemRequest{Uuid: "item", ActivityUuid: "activity", Name: "stand-up", IsCompleted: &reopen, DueAt: timestamppb.New(dueAt)}); err != nil {
+		t.Fatalf("updating: %s", err)
+	}
+	if req := svc.updated[0]; req.IsCompleted == nil || *req.IsCompleted || req.DueAt == nil || !req.DueAt.Equal(dueAt) {
+		t.Fatalf("update sent completed %v, due %v, want the item reopened and due at %s", req.IsCompleted, req.DueAt, dueAt)
+	}
+
+	if _, err := s.Update(context.Background(), &pb.UpdateTodoItemRequest{Uuid: "item", ActivityUuid: "activity", Name: "stand-up", ClearDueAt: true}); err != nil {
+		t.Fatalf("updating: %s", err)
+	}
+	if req := svc.updated[1]; !req.ClearDueAt || req.DueAt != nil {
+		t.Fatalf("update sent due %v, clear %t, want the due date removed", req.DueAt, req.ClearDueAt)
+	}
+}
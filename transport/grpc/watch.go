@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/Adhiana46/go-restapi-template/transport/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watch replays the activity event log after lastId and then follows the live hub until the client leaves
+func watch(ctx context.Context, hub stream.Hub, svcActivityEvent service.ActivityEventService, activityUuid string, lastId int64, match func(e *entity.ActivityEvent) bool, send func(*pb.ChangeEvent) error) error {
+	sub := hub.Subscribe(activityUuid)
+	defer sub.Close()
+
+	emit := func(e *entity.ActivityEvent) error {
+		lastId = e.ID
+		if !match(e) {
+			return nil
+		}
+
+		changeEvent, err := changeEventToPb(e)
+		if err != nil {
+			return status.Error(codes.Internal, "malformed event")
+		}
+
+		return send(changeEvent)
+	}
+
+	if lastId > 0 {
+		for {
+			events, err := svcActivityEvent.FetchSinceByActivity(ctx, activityUuid, lastId)
+			if err != nil {
+				return toStatus(err)
+			}
+			if len(events) == 0 {
+				break
+			}
+
+			for _, e := range events {
+				if err := emit(e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.C:
+			if !ok {
+				if errors.Is(sub.Err(), stream.ErrShutdown) {
+					return status.Error(codes.Unavailable, "server shutting down, resume with last_event_id")
+				}
+				return status.Error(codes.ResourceExhausted, "client too slow, resume with last_event_id")
+			}
+			if e.ID <= lastId {
+				continue
+			}
+			if err := emit(e); err != nil {
+				return err
+			}
+		}
+	}
+}
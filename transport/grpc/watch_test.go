@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/Adhiana46/go-restapi-template/transport/grpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeActivityEventService struct {
+	service.ActivityEventService
+
+	events []*entity.ActivityEvent
+}
+
+func (s *fakeActivityEventService) FetchSinceByActivity(ctx context.Context, activityUuid string, afterId int64) ([]*entity.ActivityEvent, error) {
+	events := []*entity.ActivityEvent{}
+	for _, e := range s.events {
+		if e.ActivityUuid == activityUuid && e.ID > afterId {
+			events = append(events, e)
+		}
+	}
+
+	return events, nil
+}
+
+// TestWatchEndsOnShutdown makes sure an open Watch doesn't keep GracefulStop waiting once the hub shuts down
+func TestWatchEndsOnShutdown(t *testing.T) {
+	events := &fakeActivityEventService{
+		events: []*entity.ActivityEvent{
+			{ID: 4, ActivityUuid: "activity", Event: "activity-group.updated", Payload: `{"event":"activity-group.updated","data":{}}`},
+		},
+	}
+	hub := stream.NewHub(time.Second, time.Second, events)
+
+	done := make(chan error, 1)
+	sent := make(chan *pb.ChangeEvent, 1)
+	go func() {
+		done <- watch(context.Background(), hub, events, "activity", 3, func(e *entity.ActivityEvent) bool {
+			return true
+		}, func(e *pb.ChangeEvent) error {
+			sent <- e
+			return nil
+		})
+	}()
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("replayed event never sent")
+	}
+
+	hub.Shutdown()
+
+	select {
+	case err := <-done:
+		if status.Code(err) != codes.Unavailable {
+			t.Fatalf("watch ended with %v, want Unavailable", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watch still running after the hub shut down")
+	}
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+type ActivityEventHandler interface {
+	RegisterRoutes(r fiber.Router) ActivityEventHandler
+	Docs() []openapi.Operation
+
+	stream() func(c *fiber.Ctx) error
+}
+
+type activityEventHandler struct {
+	heartbeat time.Duration
+	hub       stream.Hub
+
+	svcActivityGroup service.ActivityGroupService
+	svcActivityEvent service.ActivityEventService
+}
+
+func NewActivityEventHandler(heartbeat time.Duration, hub stream.Hub, svcActivityGroup service.ActivityGroupService, svcActivityEvent service.ActivityEventService) ActivityEventHandler {
+	return &activityEventHandler{
+		heartbeat:        heartbeat,
+		hub:              hub,
+		svcActivityGroup: svcActivityGroup,
+		svcActivityEvent: svcActivityEvent,
+	}
+}
+
+func (h *activityEventHandler) RegisterRoutes(r fiber.Router) ActivityEventHandler {
+	r.Get("/:uuid/events", h.stream())
+
+	return h
+}
+
+func (h *activityEventHandler) Docs() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/:uuid/events", Summary: "Stream live changes of an activity group as Server-Sent Events", Tags: []string{"Activity Group"}, ContentType: "text/event-stream"},
+	}
+}
+
+func (h *activityEventHandler) stream() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.ActivityGroupUuidRequest{Uuid: c.Params("uuid")}
+
+		activityGroup, err := h.svcActivityGroup.FindByUuid(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		// resume from the Last-Event-ID header, or the query string for clients that can't set headers
+		lastEventId := c.Get("Last-Event-ID", c.Query("lastEventId"))
+		var lastId int64
+		if lastEventId != "" {
+			lastId, err = strconv.ParseInt(lastEventId, 10, 64)
+			if err != nil {
+				return apperror.BadRequest(apperror.CodeInvalidLastEventId, "malformed Last-Event-ID, should be a numeric event id")
+			}
+		}
+
+		c.Set("Content-Type", "text/event-stream")
+		c.Set("Cache-Control", "no-cache")
+		c.Set("Connection", "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+
+		// subscribe before replaying so nothing committed in between is missed
+		sub := h.hub.Subscribe(activityGroup.Uuid)
+
+		// the stream writer outlives the handler and its fiber.Ctx, it keeps the request context
+		ctx := c.UserContext()
+
+		c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+			defer sub.Close()
+
+			fmt.Fprintf(w, "retry: %d\n\n", 3000)
+
+			if lastEventId != "" {
+				lastId, err = h.replay(ctx, w, activityGroup.Uuid, lastId)
+				if err != nil {
+					return
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			ticker := time.NewTicker(h.heartbeat)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case e, ok := <-sub.C:
+					if !ok {
+						if sub.Err() != nil {
+							// EventSource reconnects on its own, sending the last id it got as Last-Event-ID
+							fmt.Fprintf(w, "event: reset\ndata: {\"code\":%q,\"last_event_id\":%d}\n\n", apperror.CodeStreamReset, lastId)
+							w.Flush()
+						}
+						return
+					}
+					if e.ID <= lastId {
+						continue
+					}
+					writeEvent(w, e)
+					lastId = e.ID
+				case <-ticker.C:
+					fmt.Fprint(w, ": heartbeat\n\n")
+				}
+
+				// a failing flush means the client went away
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}))
+
+		return nil
+	}
+}
+
+func (h *activityEventHandler) replay(ctx context.Context, w *bufio.Writer, activityUuid string, lastId int64) (int64, error) {
+	for {
+		events, err := h.svcActivityEvent.FetchSinceByActivity(ctx, activityUuid, lastId)
+		if err != nil {
+			return lastId, err
+		}
+		if len(events) == 0 {
+			return lastId, nil
+		}
+
+		for _, e := range events {
+			writeEvent(w, e)
+			lastId = e.ID
+		}
+	}
+}
+
+func writeEvent(w *bufio.Writer, e *entity.ActivityEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Event, e.Payload)
+}
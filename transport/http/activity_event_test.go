@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeActivityGroupService struct {
+	service.ActivityGroupService
+}
+
+func (s *fakeActivityGroupService) FindByUuid(ctx context.Context, req dto.ActivityGroupUuidRequest) (*entity.ActivityGroup, error) {
+	return &entity.ActivityGroup{ID: 1, Uuid: req.Uuid}, nil
+}
+
+type fakeActivityEventService struct {
+	service.ActivityEventService
+
+	events []*entity.ActivityEvent
+}
+
+func (s *fakeActivityEventService) FetchSinceByActivity(ctx context.Context, activityUuid string, afterId int64) ([]*entity.ActivityEvent, error) {
+	events := []*entity.ActivityEvent{}
+	for _, e := range s.events {
+		if e.ActivityUuid == activityUuid && e.ID > afterId {
+			events = append(events, e)
+		}
+	}
+
+	return events, nil
+}
+
+// TestStreamEndsOnShutdown replays the log after Last-Event-ID and makes sure a shut down hub ends the stream
+// with a reset instead of keeping the request, and the server shutdown, waiting
+func TestStreamEndsOnShutdown(t *testing.T) {
+	events := &fakeActivityEventService{
+		events: []*entity.ActivityEvent{
+			{ID: 1, ActivityUuid: "activity", Event: "todo-item.created", Payload: `{"event":"todo-item.created"}`},
+			{ID: 2, ActivityUuid: "other", Event: "todo-item.created", Payload: `{"event":"todo-item.created"}`},
+			{ID: 3, ActivityUuid: "activity", Event: "todo-item.deleted", Payload: `{"event":"todo-item.deleted"}`},
+		},
+	}
+	hub := stream.NewHub(time.Second, time.Second, events)
+	hub.Shutdown()
+
+	r := fiber.New()
+	NewActivityEventHandler(time.Minute, hub, &fakeActivityGroupService{}, events).RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/activity/events", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	resp, err := r.Test(req, 2000)
+	if err != nil {
+		t.Fatalf("streaming: %s", err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("content type %q", ct)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading stream: %s", err)
+	}
+
+	want := "retry: 3000\n\n" +
+		"id: 1\nevent: todo-item.created\ndata: {\"event\":\"todo-item.created\"}\n\n" +
+		"id: 3\nevent: todo-item.deleted\ndata: {\"event\":\"todo-item.deleted\"}\n\n" +
+		"event: reset\ndata: {\"code\":\"STREAM_RESET\",\"last_event_id\":3}\n\n"
+	if string(body) != want {
+		t.Fatalf("stream:\n%s\nwant:\n%s", body, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Adhiana46/go-restapi-template/internal/dto"
 	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
 	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
 	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
 	"github.com/gofiber/fiber/v2"
@@ -12,6 +13,7 @@ import (
 
 type ActivityGroupHandler interface {
 	RegisterRoutes(r fiber.Router) ActivityGroupHandler
+	Docs() []openapi.Operation
 
 	findByUuid() func(c *fiber.Ctx) error
 	fetchAll() func(c *fiber.Ctx) error
@@ -40,6 +42,18 @@ func (h *activityGroupHandler) RegisterRoutes(r fiber.Router) ActivityGroupHandl
 	return h
 }
 
+func (h *activityGroupHandler) Docs() []openapi.Operation {
+	tags := []string{"Activity Group"}
+
+	return []openapi.Operation{
+		{Method: "GET", Path: "/:uuid", Summary: "Find an activity group", Tags: tags, Response: dto.ActivityGroupResponse{}},
+		{Method: "GET", Path: "/", Summary: "List activity groups", Tags: tags, Query: dto.ActivityGroupFetchRequest{}, Response: []dto.ActivityGroupResponse{}, Paginated: true},
+		{Method: "POST", Path: "/", Summary: "Create an activity group", Tags: tags, Body: dto.ActivityGroupCreateRequest{}, Response: dto.ActivityGroupResponse{}},
+		{Method: "PUT", Path: "/:uuid", Summary: "Update an activity group", Tags: tags, Body: dto.ActivityGroupUpdateRequest{}, Response: dto.ActivityGroupResponse{}},
+		{Method: "DELETE", Path: "/:uuid", Summary: "Delete an activity group", Tags: tags},
+	}
+}
+
 func (h *activityGroupHandler) findByUuid() func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		req := dto.ActivityGroupUuidRequest{}
@@ -47,7 +61,7 @@ func (h *activityGroupHandler) findByUuid() func(c *fiber.Ctx) error {
 			panic(err)
 		}
 
-		activityGroup, err := h.svcActivityGroup.FindByUuid(req)
+		activityGroup, err := h.svcActivityGroup.FindByUuid(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
@@ -66,7 +80,7 @@ func (h *activityGroupHandler) fetchAll() func(c *fiber.Ctx) error {
 			panic(err)
 		}
 
-		activityGroupList, pagination, err := h.svcActivityGroup.FetchAll(req)
+		activityGroupList, pagination, err := h.svcActivityGroup.FetchAll(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
@@ -85,7 +99,7 @@ func (h *activityGroupHandler) create() func(c *fiber.Ctx) error {
 			panic(err)
 		}
 
-		activityGroup, err := h.svcActivityGroup.Create(req)
+		activityGroup, err := h.svcActivityGroup.Create(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
@@ -104,7 +118,7 @@ func (h *activityGroupHandler) update() func(c *fiber.Ctx) error {
 			panic(err)
 		}
 
-		activityGroup, err := h.svcActivityGroup.Update(req)
+		activityGroup, err := h.svcActivityGroup.Update(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
@@ -123,7 +137,7 @@ func (h *activityGroupHandler) delete() func(c *fiber.Ctx) error {
 			panic(err)
 		}
 
-		err := h.svcActivityGroup.Delete(req)
+		err := h.svcActivityGroup.Delete(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
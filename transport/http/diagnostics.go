@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	"github.com/gofiber/fiber/v2"
+)
+
+type DiagnosticsHandler interface {
+	RegisterRoutes(r fiber.Router) DiagnosticsHandler
+	Docs() []openapi.Operation
+
+	database() func(c *fiber.Ctx) error
+}
+
+type diagnosticsHandler struct {
+	db *sqldb.DB
+}
+
+// NewDiagnosticsHandler reports on db, which may be nil when the binary has no database connection
+func NewDiagnosticsHandler(db *sqldb.DB) DiagnosticsHandler {
+	return &diagnosticsHandler{
+		db: db,
+	}
+}
+
+func (h *diagnosticsHandler) RegisterRoutes(r fiber.Router) DiagnosticsHandler {
+	r.Get("/database", h.database())
+
+	return h
+}
+
+func (h *diagnosticsHandler) Docs() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/database", Summary: "Connection pool statistics of the database primary and replica", Tags: []string{"Diagnostics"}, Response: dto.DatabasePoolsResponse{}},
+	}
+}
+
+func (h *diagnosticsHandler) database() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		resp := &dto.DatabasePoolsResponse{
+			Primary: &dto.DatabaseStatsResponse{},
+		}
+		if h.db != nil {
+			primary, replica := h.db.Stats()
+			resp.Primary = dto.DatabaseStatsToResponse(primary)
+			if replica != nil {
+				resp.Replica = dto.DatabaseStatsToResponse(*replica)
+			}
+		}
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"github.com/Adhiana46/go-restapi-template/pkg/health"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthHandler interface {
+	RegisterRoutes(r fiber.Router) HealthHandler
+	Docs() []openapi.Operation
+
+	liveness() func(c *fiber.Ctx) error
+	readiness() func(c *fiber.Ctx) error
+}
+
+type healthHandler struct {
+	live  health.Checker
+	ready health.Checker
+}
+
+func NewHealthHandler(live health.Checker, ready health.Checker) HealthHandler {
+	return &healthHandler{
+		live:  live,
+		ready: ready,
+	}
+}
+
+func (h *healthHandler) RegisterRoutes(r fiber.Router) HealthHandler {
+	r.Get("/healthz", h.liveness())
+	r.Get("/readyz", h.readiness())
+
+	return h
+}
+
+func (h *healthHandler) Docs() []openapi.Operation {
+	tags := []string{"Health"}
+
+	return []openapi.Operation{
+		{Method: "GET", Path: "/healthz", Summary: "Report whether the process is alive", Tags: tags, Response: health.Report{}, RawResponse: true},
+		{Method: "GET", Path: "/readyz", Summary: "Report whether the database, broker and schema are ready, 503 otherwise", Tags: tags, Response: health.Report{}, RawResponse: true},
+	}
+}
+
+func (h *healthHandler) liveness() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		report := h.live.Run(c.UserContext())
+
+		return c.Status(report.StatusCode()).JSON(report)
+	}
+}
+
+func (h *healthHandler) readiness() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		report := h.ready.Run(c.UserContext())
+
+		return c.Status(report.StatusCode()).JSON(report)
+	}
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Adhiana46/go-restapi-template/internal/auth"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationHandler interface {
+	RegisterRoutes(r fiber.Router) NotificationHandler
+	Docs() []openapi.Operation
+
+	findPreference() func(c *fiber.Ctx) error
+	updatePreference() func(c *fiber.Ctx) error
+	fetchDeliveries() func(c *fiber.Ctx) error
+}
+
+type notificationHandler struct {
+	authenticator   auth.Authenticator
+	svcNotification service.NotificationService
+}
+
+func NewNotificationHandler(authenticator auth.Authenticator, svcNotification service.NotificationService) NotificationHandler {
+	return &notificationHandler{
+		authenticator:   authenticator,
+		svcNotification: svcNotification,
+	}
+}
+
+// RegisterRoutes serves the preferences and deliveries of the authenticated user only
+func (h *notificationHandler) RegisterRoutes(r fiber.Router) NotificationHandler {
+	r.Use("/", auth.FiberMiddleware(h.authenticator))
+	r.Get("/preferences", h.findPreference())
+	r.Put("/preferences", h.updatePreference())
+	r.Get("/deliveries", h.fetchDeliveries())
+
+	return h
+}
+
+func (h *notificationHandler) Docs() []openapi.Operation {
+	tags := []string{"Notification"}
+
+	return []openapi.Operation{
+		{Method: "GET", Path: "/preferences", Summary: "Find the channels your notifications are delivered on", Tags: tags, Response: dto.NotificationPreferenceResponse{}},
+		{Method: "PUT", Path: "/preferences", Summary: "Pick the channels your notifications are delivered on", Tags: tags, Body: dto.NotificationPreferenceUpdateRequest{}, Response: dto.NotificationPreferenceResponse{}},
+		{Method: "GET", Path: "/deliveries", Summary: "List the deliveries of your notifications", Tags: tags, Query: dto.NotificationDeliveryFetchRequest{}, Response: []dto.NotificationDeliveryResponse{}, Paginated: true},
+	}
+}
+
+func (h *notificationHandler) findPreference() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.NotificationPreferenceFindRequest{}
+		req.User = auth.FiberUser(c)
+
+		preference, err := h.svcNotification.FindPreference(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.NotificationPreferenceToResponse(preference)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *notificationHandler) updatePreference() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.NotificationPreferenceUpdateRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		req.User = auth.FiberUser(c)
+
+		preference, err := h.svcNotification.UpdatePreference(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.NotificationPreferenceToResponse(preference)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *notificationHandler) fetchDeliveries() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.NotificationDeliveryFetchRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		req.User = auth.FiberUser(c)
+
+		deliveryList, pagination, err := h.svcNotification.FetchDeliveries(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.NotificationDeliveryToResponseList(deliveryList)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, pagination))
+	}
+}
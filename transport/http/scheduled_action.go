@@ -0,0 +1,106 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ScheduledActionHandler interface {
+	RegisterRoutes(r fiber.Router) ScheduledActionHandler
+	Docs() []openapi.Operation
+
+	findByUuid() func(c *fiber.Ctx) error
+	fetchAll() func(c *fiber.Ctx) error
+	cancel() func(c *fiber.Ctx) error
+}
+
+type scheduledActionHandler struct {
+	svcScheduledAction service.ScheduledActionService
+}
+
+func NewScheduledActionHandler(svcScheduledAction service.ScheduledActionService) ScheduledActionHandler {
+	return &scheduledActionHandler{
+		svcScheduledAction: svcScheduledAction,
+	}
+}
+
+func (h *scheduledActionHandler) RegisterRoutes(r fiber.Router) ScheduledActionHandler {
+	r.Get("/:uuid", h.findByUuid())
+	r.Get("/", h.fetchAll())
+	r.Delete("/:uuid", h.cancel())
+
+	return h
+}
+
+func (h *scheduledActionHandler) Docs() []openapi.Operation {
+	tags := []string{"Scheduled Action"}
+
+	return []openapi.Operation{
+		{Method: "GET", Path: "/:uuid", Summary: "Find a scheduled queue action", Tags: tags, Response: dto.ScheduledActionResponse{}},
+		{Method: "GET", Path: "/", Summary: "List the scheduled queue actions", Tags: tags, Query: dto.ScheduledActionFetchRequest{}, Response: []dto.ScheduledActionResponse{}, Paginated: true},
+		{Method: "DELETE", Path: "/:uuid", Summary: "Cancel a pending scheduled queue action", Tags: tags, Response: dto.ScheduledActionResponse{}},
+	}
+}
+
+func (h *scheduledActionHandler) findByUuid() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.ScheduledActionUuidRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		scheduledAction, err := h.svcScheduledAction.FindByUuid(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.ScheduledActionToResponse(scheduledAction)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *scheduledActionHandler) fetchAll() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.ScheduledActionFetchRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		scheduledActionList, pagination, err := h.svcScheduledAction.FetchAll(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.ScheduledActionToResponseList(scheduledActionList)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, pagination))
+	}
+}
+
+func (h *scheduledActionHandler) cancel() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.ScheduledActionUuidRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		scheduledAction, err := h.svcScheduledAction.Cancel(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.ScheduledActionToResponse(scheduledAction)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Adhiana46/go-restapi-template/internal/dto"
 	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
 	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
 	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
 	"github.com/gofiber/fiber/v2"
@@ -12,12 +13,16 @@ import (
 
 type TodoItemHandler interface {
 	RegisterRoutes(r fiber.Router) TodoItemHandler
+	Docs() []openapi.Operation
 
 	findByUuid() func(c *fiber.Ctx) error
 	fetchAll() func(c *fiber.Ctx) error
 	create() func(c *fiber.Ctx) error
 	update() func(c *fiber.Ctx) error
 	delete() func(c *fiber.Ctx) error
+	findSeries() func(c *fiber.Ctx) error
+	updateSeries() func(c *fiber.Ctx) error
+	endSeries() func(c *fiber.Ctx) error
 }
 
 type todoItemHandler struct {
@@ -36,10 +41,28 @@ func (h *todoItemHandler) RegisterRoutes(r fiber.Router) TodoItemHandler {
 	r.Post("/", h.create())
 	r.Put("/:uuid", h.update())
 	r.Delete("/:uuid", h.delete())
+	r.Get("/:uuid/series", h.findSeries())
+	r.Put("/:uuid/series", h.updateSeries())
+	r.Delete("/:uuid/series", h.endSeries())
 
 	return h
 }
 
+func (h *todoItemHandler) Docs() []openapi.Operation {
+	tags := []string{"Todo Item"}
+
+	return []openapi.Operation{
+		{Method: "GET", Path: "/:uuid", Summary: "Find a todo item", Tags: tags, Response: dto.TodoItemResponse{}},
+		{Method: "GET", Path: "/", Summary: "List the todo items of an activity group", Tags: tags, Query: dto.TodoItemFetchRequest{}, Response: []dto.TodoItemResponse{}, Paginated: true},
+		{Method: "POST", Path: "/", Summary: "Create a todo item", Tags: tags, Body: dto.TodoItemCreateRequest{}, Response: dto.TodoItemResponse{}},
+		{Method: "PUT", Path: "/:uuid", Summary: "Update a todo item, only this occurrence of a recurring one", Tags: tags, Body: dto.TodoItemUpdateRequest{}, Response: dto.TodoItemResponse{}},
+		{Method: "DELETE", Path: "/:uuid", Summary: "Delete a todo item", Tags: tags},
+		{Method: "GET", Path: "/:uuid/series", Summary: "Find the series of a recurring todo item", Tags: tags, Response: dto.TodoSeriesResponse{}},
+		{Method: "PUT", Path: "/:uuid/series", Summary: "Update the series of a recurring todo item, its open occurrence and the next ones", Tags: tags, Body: dto.TodoSeriesUpdateRequest{}, Response: dto.TodoSeriesResponse{}},
+		{Method: "DELETE", Path: "/:uuid/series", Summary: "Stop a recurring todo item from creating occurrences", Tags: tags, Response: dto.TodoSeriesResponse{}},
+	}
+}
+
 func (h *todoItemHandler) findByUuid() func(c *fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
 		req := dto.TodoItemUuidRequest{}
@@ -47,7 +70,7 @@ func (h *todoItemHandler) findByUuid() func(c *fiber.Ctx) error {
 			panic(err)
 		}
 
-		todoItem, err := h.svcTodoItem.FindByUuid(req)
+		todoItem, err := h.svcTodoItem.FindByUuid(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
@@ -68,7 +91,7 @@ func (h *todoItemHandler) fetchAll() func(c *fiber.Ctx) error {
 
 		req.ActivityUuid = c.Params("activity_uuid")
 
-		todoItemList, pagination, err := h.svcTodoItem.FetchAll(req)
+		todoItemList, pagination, err := h.svcTodoItem.FetchAll(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
@@ -89,7 +112,7 @@ func (h *todoItemHandler) create() func(c *fiber.Ctx) error {
 
 		req.ActivityUuid = c.Params("activity_uuid")
 
-		todoItem, err := h.svcTodoItem.Create(req)
+		todoItem, err := h.svcTodoItem.Create(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
@@ -110,7 +133,7 @@ func (h *todoItemHandler) update() func(c *fiber.Ctx) error {
 
 		req.ActivityUuid = c.Params("activity_uuid")
 
-		todoItem, err := h.svcTodoItem.Update(req)
+		todoItem, err := h.svcTodoItem.Update(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
@@ -129,7 +152,7 @@ func (h *todoItemHandler) delete() func(c *fiber.Ctx) error {
 			panic(err)
 		}
 
-		err := h.svcTodoItem.Delete(req)
+		err := h.svcTodoItem.Delete(c.UserContext(), req)
 		if err != nil {
 			return err
 		}
@@ -138,3 +161,60 @@ func (h *todoItemHandler) delete() func(c *fiber.Ctx) error {
 		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", nil, nil))
 	}
 }
+
+func (h *todoItemHandler) findSeries() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.TodoItemUuidRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		series, err := h.svcTodoItem.FindSeries(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.TodoSeriesToResponse(series)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *todoItemHandler) updateSeries() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.TodoSeriesUpdateRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		series, err := h.svcTodoItem.UpdateSeries(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.TodoSeriesToResponse(series)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *todoItemHandler) endSeries() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.TodoItemUuidRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		series, err := h.svcTodoItem.EndSeries(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.TodoSeriesToResponse(series)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Adhiana46/go-restapi-template/internal/auth"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TodoReminderHandler interface {
+	RegisterRoutes(r fiber.Router) TodoReminderHandler
+	Docs() []openapi.Operation
+
+	fetchAll() func(c *fiber.Ctx) error
+	create() func(c *fiber.Ctx) error
+	delete() func(c *fiber.Ctx) error
+}
+
+type todoReminderHandler struct {
+	authenticator   auth.Authenticator
+	svcNotification service.NotificationService
+}
+
+func NewTodoReminderHandler(authenticator auth.Authenticator, svcNotification service.NotificationService) TodoReminderHandler {
+	return &todoReminderHandler{
+		authenticator:   authenticator,
+		svcNotification: svcNotification,
+	}
+}
+
+// RegisterRoutes serves the reminders of the authenticated user only
+func (h *todoReminderHandler) RegisterRoutes(r fiber.Router) TodoReminderHandler {
+	r.Use("/", auth.FiberMiddleware(h.authenticator))
+	r.Get("/", h.fetchAll())
+	r.Post("/", h.create())
+	r.Delete("/:uuid", h.delete())
+
+	return h
+}
+
+func (h *todoReminderHandler) Docs() []openapi.Operation {
+	tags := []string{"Notification"}
+
+	return []openapi.Operation{
+		{Method: "GET", Path: "/", Summary: "List your reminders of a todo item", Tags: tags, Response: []dto.TodoReminderResponse{}},
+		{Method: "POST", Path: "/", Summary: "Get reminded offset_minutes before the due date of a todo item", Tags: tags, Body: dto.TodoReminderCreateRequest{}, Response: dto.TodoReminderResponse{}},
+		{Method: "DELETE", Path: "/:uuid", Summary: "Delete a reminder", Tags: tags},
+	}
+}
+
+func (h *todoReminderHandler) fetchAll() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.TodoReminderFetchRequest{}
+		req.ActivityUuid = c.Params("activity_uuid")
+		req.TodoItemUuid = c.Params("todo_item_uuid")
+		req.User = auth.FiberUser(c)
+
+		reminderList, err := h.svcNotification.FetchReminders(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.TodoReminderToResponseList(reminderList)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *todoReminderHandler) create() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.TodoReminderCreateRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		req.ActivityUuid = c.Params("activity_uuid")
+		req.TodoItemUuid = c.Params("todo_item_uuid")
+		req.User = auth.FiberUser(c)
+
+		reminder, err := h.svcNotification.CreateReminder(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.TodoReminderToResponse(reminder)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *todoReminderHandler) delete() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.TodoReminderUuidRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		req.ActivityUuid = c.Params("activity_uuid")
+		req.TodoItemUuid = c.Params("todo_item_uuid")
+		req.User = auth.FiberUser(c)
+
+		err := h.svcNotification.DeleteReminder(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", nil, nil))
+	}
+}
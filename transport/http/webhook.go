@@ -0,0 +1,178 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
+	responsePkg "github.com/Adhiana46/go-restapi-template/pkg/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandler interface {
+	RegisterRoutes(r fiber.Router) WebhookHandler
+	Docs() []openapi.Operation
+
+	findByUuid() func(c *fiber.Ctx) error
+	fetchAll() func(c *fiber.Ctx) error
+	create() func(c *fiber.Ctx) error
+	update() func(c *fiber.Ctx) error
+	delete() func(c *fiber.Ctx) error
+	fetchDeliveries() func(c *fiber.Ctx) error
+}
+
+type webhookHandler struct {
+	svcWebhook service.WebhookService
+}
+
+func NewWebhookHandler(svcWebhook service.WebhookService) WebhookHandler {
+	return &webhookHandler{
+		svcWebhook: svcWebhook,
+	}
+}
+
+func (h *webhookHandler) RegisterRoutes(r fiber.Router) WebhookHandler {
+	r.Get("/:uuid", h.findByUuid())
+	r.Get("/", h.fetchAll())
+	r.Post("/", h.create())
+	r.Put("/:uuid", h.update())
+	r.Delete("/:uuid", h.delete())
+	r.Get("/:uuid/deliveries", h.fetchDeliveries())
+
+	return h
+}
+
+func (h *webhookHandler) Docs() []openapi.Operation {
+	tags := []string{"Webhook"}
+
+	return []openapi.Operation{
+		{Method: "GET", Path: "/:uuid", Summary: "Find a webhook endpoint", Tags: tags, Response: dto.WebhookResponse{}},
+		{Method: "GET", Path: "/", Summary: "List the webhook endpoints of an activity group", Tags: tags, Response: []dto.WebhookResponse{}},
+		{Method: "POST", Path: "/", Summary: "Subscribe a webhook endpoint", Tags: tags, Body: dto.WebhookCreateRequest{}, Response: dto.WebhookResponse{}},
+		{Method: "PUT", Path: "/:uuid", Summary: "Update a webhook endpoint", Tags: tags, Body: dto.WebhookUpdateRequest{}, Response: dto.WebhookResponse{}},
+		{Method: "DELETE", Path: "/:uuid", Summary: "Delete a webhook endpoint", Tags: tags},
+		{Method: "GET", Path: "/:uuid/deliveries", Summary: "List the deliveries of a webhook endpoint", Tags: tags, Query: dto.WebhookDeliveryFetchRequest{}, Response: []dto.WebhookDeliveryResponse{}, Paginated: true},
+	}
+}
+
+func (h *webhookHandler) findByUuid() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.WebhookUuidRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		req.ActivityUuid = c.Params("activity_uuid")
+
+		webhook, err := h.svcWebhook.FindByUuid(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.WebhookToResponse(webhook)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *webhookHandler) fetchAll() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.WebhookFetchRequest{}
+		req.ActivityUuid = c.Params("activity_uuid")
+
+		webhookList, err := h.svcWebhook.FetchAll(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.WebhookToResponseList(webhookList)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *webhookHandler) create() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.WebhookCreateRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		req.ActivityUuid = c.Params("activity_uuid")
+
+		webhook, err := h.svcWebhook.Create(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.WebhookToResponse(webhook)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *webhookHandler) update() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.WebhookUpdateRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		req.ActivityUuid = c.Params("activity_uuid")
+
+		webhook, err := h.svcWebhook.Update(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.WebhookToResponse(webhook)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, nil))
+	}
+}
+
+func (h *webhookHandler) delete() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.WebhookUuidRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		req.ActivityUuid = c.Params("activity_uuid")
+
+		err := h.svcWebhook.Delete(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", nil, nil))
+	}
+}
+
+func (h *webhookHandler) fetchDeliveries() func(c *fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		req := dto.WebhookDeliveryFetchRequest{}
+		if err := parserPkg.FiberShouldBindRequest(c, &req); err != nil {
+			panic(err)
+		}
+
+		req.ActivityUuid = c.Params("activity_uuid")
+
+		deliveryList, pagination, err := h.svcWebhook.FetchDeliveries(c.UserContext(), req)
+		if err != nil {
+			return err
+		}
+
+		resp := dto.WebhookDeliveryToResponseList(deliveryList)
+
+		statusCode := http.StatusOK
+		return c.Status(statusCode).JSON(responsePkg.JsonSuccess(statusCode, "", resp, pagination))
+	}
+}
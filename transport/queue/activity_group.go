@@ -1,28 +1,54 @@
 package queue
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/Adhiana46/go-restapi-template/config"
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
 	"github.com/Adhiana46/go-restapi-template/internal/dto"
 	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/i18n"
 	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/asyncapi"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
+	"github.com/Adhiana46/go-restapi-template/pkg/reporter"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	"github.com/Adhiana46/go-restapi-template/pkg/tracing"
 	amqp "github.com/rabbitmq/amqp091-go"
-	log "github.com/sirupsen/logrus"
 )
 
 type activityGroupWorker struct {
-	conn      *amqp.Connection
-	queueName string
+	conn       *amqp.Connection
+	replies    *Replies
+	queueName  string
+	cfg        config.WorkerConfig
+	spec       *asyncapi.Document
+	translator i18n.Translator
+	metrics    *metrics.QueueMetrics
+	reporter   reporter.Reporter
+	*consumerState
 
-	svcActivityGroup service.ActivityGroupService
+	svcActivityGroup   service.ActivityGroupService
+	svcScheduledAction service.ScheduledActionService
 }
 
-func NewActivityGroupWorker(conn *amqp.Connection, queueName string, svcActivityGroup service.ActivityGroupService) QueueWorker {
+func NewActivityGroupWorker(conn *amqp.Connection, replies *Replies, queueName string, cfg config.WorkerConfig, translator i18n.Translator, queueMetrics *metrics.QueueMetrics, errReporter reporter.Reporter, svcActivityGroup service.ActivityGroupService, svcScheduledAction service.ScheduledActionService) QueueWorker {
 	return &activityGroupWorker{
-		conn:             conn,
-		queueName:        queueName,
-		svcActivityGroup: svcActivityGroup,
+		conn:               conn,
+		replies:            replies,
+		queueName:          queueName,
+		cfg:                cfg,
+		spec:               asyncapi.NewDocument(queueName, "").AddQueue(queueName, activityGroupActions()...),
+		translator:         translator,
+		metrics:            queueMetrics,
+		reporter:           errReporter,
+		consumerState:      newConsumerState(),
+		svcActivityGroup:   svcActivityGroup,
+		svcScheduledAction: svcScheduledAction,
 	}
 }
 
@@ -30,7 +56,20 @@ func (w *activityGroupWorker) GetWorkerName() string {
 	return w.queueName
 }
 
-func (w *activityGroupWorker) Listen() error {
+// Listen consumes the request queue until ctx is cancelled, the messages delivered until then are handled
+// before it returns, so the publisher and the connection must outlive it
+func (w *activityGroupWorker) Listen(ctx context.Context) error {
+	err := w.listen(ctx)
+	if err == nil {
+		w.setStatus(errStopped)
+	} else {
+		w.setStatus(err)
+	}
+
+	return err
+}
+
+func (w *activityGroupWorker) listen(ctx context.Context) error {
 	ch, err := w.conn.Channel()
 	if err != nil {
 		return err
@@ -51,88 +90,146 @@ func (w *activityGroupWorker) Listen() error {
 
 	// set Qos
 	err = ch.Qos(
-		1,     // prefetch count
-		0,     // prefetch size
-		false, // global
+		w.cfg.Prefetch, // prefetch count
+		0,              // prefetch size
+		false,          // global
 	)
 	if err != nil {
 		return err
 	}
 
 	msgs, err := ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		false,  // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
+		q.Name,      // queue
+		w.queueName, // consumer
+		false,       // auto-ack
+		false,       // exclusive
+		false,       // no-local
+		false,       // no-wait
+		nil,         // args
 	)
 	if err != nil {
 		return err
 	}
 
-	var forever chan struct{}
-	go func() {
-		for d := range msgs {
-			var payload queueRequestPayload
-			_ = json.Unmarshal(d.Body, &payload)
+	w.setStatus(nil)
 
-			go w.handlePayload(d, payload)
-		}
-	}()
-
-	<-forever
+	p := newPool(w.cfg.Concurrency, w.cfg.Ordered, w.handlePayload)
+	return consume(ctx, msgs, func() error {
+		return ch.Cancel(w.queueName, false)
+	}, p, activityGroupPartitionKey)
+}
 
-	return nil
+// activityGroupPartitionKey orders the messages per activity group
+func activityGroupPartitionKey(payload queueRequestPayload) string {
+	return partitionKey(payload, "uuid")
 }
 
 func (w *activityGroupWorker) handlePayload(d amqp.Delivery, payload queueRequestPayload) {
+	start := time.Now()
+	action := "invalid"
+	var failure error
+	defer func() {
+		w.metrics.Observe(w.queueName, action, start, failure)
+	}()
+
+	ctx, span := tracing.StartConsume(d, fmt.Sprintf("%s.request", w.queueName))
+	defer func() {
+		tracing.End(span, failure)
+	}()
+
+	ctx = withMessageFields(ctx, d, w.queueName, payload)
+	ctx = sqldb.WithSession(ctx)
+	defer func() {
+		entry := logging.FromContext(ctx).WithField("duration_ms", time.Since(start).Milliseconds())
+		if failure != nil {
+			entry.WithError(failure).Warnf("[%s] Handled message: %s", w.queueName, payload.Action)
+		} else {
+			entry.Infof("[%s] Handled message: %s", w.queueName, payload.Action)
+		}
+	}()
+	defer recoverPayload(ctx, w.replies, w.translator, w.metrics, w.reporter, w.queueName, payload.Action, d, &failure)
+
+	payload, err := upcastPayload(ctx, w.spec, w.queueName, d, payload)
+	if err != nil {
+		failure = err
+		errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
+		d.Ack(false)
+		return
+	}
+
 	dataJson, err := json.Marshal(payload.Data)
 	if err != nil {
-		errorResponse(w.conn, w.queueName, payload.Action, err)
+		failure = err
+		errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
 		d.Ack(false)
 		return
 	}
 
-	log.Infof("[%s] Receiving message: %s -> %s", w.queueName, payload.Action, string(dataJson))
+	logging.FromContext(ctx).Infof("[%s] Receiving message: %s -> %s", w.queueName, payload.Action, string(dataJson))
+
+	// reject messages that don't match the published contract before dispatching them
+	if err := w.spec.Validate(w.queueName, payload.Action, payload.Data); err != nil {
+		failure = err
+		errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), apperror.BadRequest(apperror.CodeInvalidMessage, err.Error()))
+		d.Ack(false)
+		return
+	}
+
+	// the action is one of the contract once validated, safe to use as a label
+	action = payload.Action
+
+	// requests due later wait in the scheduler, which publishes them back on this queue at execute_at
+	if delayed(payload) {
+		scheduled, err := schedulePayload(ctx, w.svcScheduledAction, w.spec, w.queueName, d, payload, "uuid")
+		if err != nil {
+			failure = err
+			errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
+		} else {
+			successResponse(ctx, w.replies, w.queueName, "scheduled", scheduled)
+		}
+		d.Ack(false)
+		return
+	}
 
 	switch payload.Action {
 	case "create":
-		activityGroup, err := w.handleCreate(dataJson)
+		activityGroup, err := w.handleCreate(ctx, dataJson)
 		if err != nil {
-			errorResponse(w.conn, w.queueName, payload.Action, err)
+			failure = err
+			errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
 		} else {
-			successResponse(w.conn, w.queueName, "created", activityGroup)
+			successResponse(ctx, w.replies, w.queueName, "created", activityGroup)
 		}
 	case "update":
-		activityGroup, err := w.handleUpdate(dataJson)
+		activityGroup, err := w.handleUpdate(ctx, dataJson)
 		if err != nil {
-			errorResponse(w.conn, w.queueName, payload.Action, err)
+			failure = err
+			errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
 		} else {
-			successResponse(w.conn, w.queueName, "updated", activityGroup)
+			successResponse(ctx, w.replies, w.queueName, "updated", activityGroup)
 		}
 	case "delete":
-		activityGroup, err := w.handleDelete(dataJson)
+		activityGroup, err := w.handleDelete(ctx, dataJson)
 		if err != nil {
-			errorResponse(w.conn, w.queueName, payload.Action, err)
+			failure = err
+			errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
 		} else {
-			successResponse(w.conn, w.queueName, "deleted", activityGroup)
+			successResponse(ctx, w.replies, w.queueName, "deleted", activityGroup)
 		}
 	}
 
 	d.Ack(false)
 }
 
-func (w *activityGroupWorker) handleCreate(data []byte) (*entity.ActivityGroup, error) {
+func (w *activityGroupWorker) handleCreate(ctx context.Context, data []byte) (*entity.ActivityGroup, error) {
 	reqDto := dto.ActivityGroupCreateRequest{}
 
 	err := json.Unmarshal(data, &reqDto)
 	if err != nil {
-		return nil, err
+		return nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
 	}
 
-	activityGroup, err := w.svcActivityGroup.Create(reqDto)
+	activityGroup, err := w.svcActivityGroup.Create(ctx, reqDto)
 	if err != nil {
 		return nil, err
 	}
@@ -140,15 +237,15 @@ func (w *activityGroupWorker) handleCreate(data []byte) (*entity.ActivityGroup,
 	return activityGroup, nil
 }
 
-func (w *activityGroupWorker) handleUpdate(data []byte) (*entity.ActivityGroup, error) {
+func (w *activityGroupWorker) handleUpdate(ctx context.Context, data []byte) (*entity.ActivityGroup, error) {
 	reqDto := dto.ActivityGroupUpdateRequest{}
 
 	err := json.Unmarshal(data, &reqDto)
 	if err != nil {
-		return nil, err
+		return nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
 	}
 
-	activityGroup, err := w.svcActivityGroup.Update(reqDto)
+	activityGroup, err := w.svcActivityGroup.Update(ctx, reqDto)
 	if err != nil {
 		return nil, err
 	}
@@ -156,20 +253,20 @@ func (w *activityGroupWorker) handleUpdate(data []byte) (*entity.ActivityGroup,
 	return activityGroup, nil
 }
 
-func (w *activityGroupWorker) handleDelete(data []byte) (*entity.ActivityGroup, error) {
+func (w *activityGroupWorker) handleDelete(ctx context.Context, data []byte) (*entity.ActivityGroup, error) {
 	reqDto := dto.ActivityGroupUuidRequest{}
 
 	err := json.Unmarshal(data, &reqDto)
 	if err != nil {
-		return nil, err
+		return nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
 	}
 
-	activityGroup, err := w.svcActivityGroup.FindByUuid(reqDto)
+	activityGroup, err := w.svcActivityGroup.FindByUuid(ctx, reqDto)
 	if err != nil {
 		return nil, err
 	}
 
-	err = w.svcActivityGroup.Delete(reqDto)
+	err = w.svcActivityGroup.Delete(ctx, reqDto)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,53 @@
+package queue
+
+import (
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/pkg/asyncapi"
+)
+
+// NewAsyncAPIDocument describes the queues consumed by the workers of this package
+func NewAsyncAPIDocument(activityGroupQueue string, todoItemQueue string, notificationQueue string) *asyncapi.Document {
+	return asyncapi.NewDocument("Todo Queue API", "1.0.0").
+		AddQueue(activityGroupQueue, activityGroupActions()...).
+		AddReply(activityGroupQueue, "scheduled", scheduledDescription, dto.ScheduledActionResponse{}).
+		AddQueue(todoItemQueue, todoItemActions()...).
+		AddReply(todoItemQueue, "scheduled", scheduledDescription, dto.ScheduledActionResponse{}).
+		AddChannel(notificationQueue, notificationDescription, dto.ReminderNotification{})
+}
+
+const scheduledDescription = "Requests held until their execute_at, listed and cancelled on /api/v1/scheduled-actions"
+
+const notificationDescription = "Reminders of the users with the queue channel enabled on /api/v1/notifications/preferences, " +
+	"the x-notification-user header names the user and the message id is the uuid of the delivery"
+
+// Changing the payload of an action bumps its Version and moves the previous dto to Legacy with an upcaster
+// to the new one, producers keep sending the previous version until its deprecation ends
+
+// upcastTodoItemUpdateV1 leaves is_completed, due_at and clear_due_at unset, version 1 predates them
+// and its updates keep the stored completion and due date
+func upcastTodoItemUpdateV1(data map[string]any) (map[string]any, error) {
+	delete(data, "is_completed")
+	delete(data, "due_at")
+	delete(data, "clear_due_at")
+
+	return data, nil
+}
+
+func activityGroupActions() []asyncapi.Action {
+	return []asyncapi.Action{
+		{Name: "create", Summary: "Create an activity group", Payload: dto.ActivityGroupCreateRequest{}, Reply: "created", Response: entity.ActivityGroup{}},
+		{Name: "update", Summary: "Update an activity group", Payload: dto.ActivityGroupUpdateRequest{}, Reply: "updated", Response: entity.ActivityGroup{}},
+		{Name: "delete", Summary: "Delete an activity group", Payload: dto.ActivityGroupUuidRequest{}, Reply: "deleted", Response: entity.ActivityGroup{}},
+	}
+}
+
+func todoItemActions() []asyncapi.Action {
+	return []asyncapi.Action{
+		{Name: "create", Summary: "Create a todo item", Payload: dto.TodoItemCreateRequest{}, Reply: "created", Response: entity.TodoItem{}},
+		{Name: "update", Summary: "Update a todo item", Payload: dto.TodoItemUpdateRequest{}, Reply: "updated", Response: entity.TodoItem{}, Version: 2, Legacy: []asyncapi.LegacyVersion{
+			{Version: 1, Payload: dto.TodoItemUpdateRequestV1{}, Upcast: upcastTodoItemUpdateV1, Deprecation: "removed after 2027-06-30"},
+		}},
+		{Name: "delete", Summary: "Delete a todo item", Payload: dto.TodoItemUuidRequest{}, Reply: "deleted", Response: entity.TodoItem{}},
+	}
+}
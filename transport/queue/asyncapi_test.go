@@ -0,0 +1,49 @@
+package queue
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Adhiana46/go-restapi-template/pkg/asyncapi"
+)
+
+func TestValidateAgainstContract(t *testing.T) {
+	spec, queueName := testSpec()
+
+	if err := spec.Validate(queueName, "create", map[string]any{"activity_uuid": "activity", "name": "todo item", "due_at": "2026-10-20T09:00:00Z"}); err != nil {
+		t.Fatalf("valid create rejected: %s", err)
+	}
+
+	err := spec.Validate(queueName, "create", map[string]any{"activity_uuid": 1, "name": "x", "due_at": "tomorrow"})
+	var validationErr *asyncapi.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("invalid create returned %v, want a validation error", err)
+	}
+	for _, want := range []string{"data.activity_uuid must be a string", "data.name must be at least 3 characters", "data.due_at must be an RFC 3339 date-time"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%q misses %q", err, want)
+		}
+	}
+
+	if err := spec.Validate(queueName, "archive", map[string]any{}); err == nil || !strings.Contains(err.Error(), "action must be one of [create delete update]") {
+		t.Errorf("unknown action returned %v", err)
+	}
+	if err := spec.Validate(queueName, "delete", nil); err == nil || !strings.Contains(err.Error(), "data is required") {
+		t.Errorf("missing data returned %v", err)
+	}
+}
+
+func TestDocumentVersions(t *testing.T) {
+	spec, queueName := testSpec()
+
+	if actions := spec.Actions(queueName); strings.Join(actions, ",") != "create,delete,update" {
+		t.Fatalf("actions %v", actions)
+	}
+	if version := spec.Version(queueName, "update"); version != 2 {
+		t.Fatalf("update is version %d, want 2", version)
+	}
+	if deprecation := spec.Deprecation(queueName, "update", 1); deprecation != "removed after 2027-06-30" {
+		t.Fatalf("deprecation of update version 1 is %q", deprecation)
+	}
+}
@@ -0,0 +1,121 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type poolJob struct {
+	d       amqp.Delivery
+	payload queueRequestPayload
+}
+
+// pool handles deliveries on a fixed number of goroutines. Submit blocks while they are all busy,
+// so a worker never holds more unacknowledged messages than its prefetch.
+// Ordered pools hand the messages sharing a key to the same goroutine, keeping their delivery order.
+type pool struct {
+	shared     chan poolJob
+	partitions []chan poolJob
+	wg         sync.WaitGroup
+}
+
+func newPool(size int, ordered bool, handle func(d amqp.Delivery, payload queueRequestPayload)) *pool {
+	p := &pool{
+		shared: make(chan poolJob),
+	}
+
+	for i := 0; i < size; i++ {
+		// a nil channel is never ready, goroutines of unordered pools only take shared jobs
+		var own chan poolJob
+		if ordered {
+			own = make(chan poolJob)
+			p.partitions = append(p.partitions, own)
+		}
+
+		p.wg.Add(1)
+		go func(own chan poolJob, shared chan poolJob) {
+			defer p.wg.Done()
+
+			for own != nil || shared != nil {
+				select {
+				case j, ok := <-own:
+					if !ok {
+						own = nil
+						continue
+					}
+					handle(j.d, j.payload)
+				case j, ok := <-shared:
+					if !ok {
+						shared = nil
+						continue
+					}
+					handle(j.d, j.payload)
+				}
+			}
+		}(own, p.shared)
+	}
+
+	return p
+}
+
+// Submit waits for a goroutine to take d, messages without a key go to any goroutine
+func (p *pool) Submit(d amqp.Delivery, payload queueRequestPayload, key string) {
+	j := poolJob{d: d, payload: payload}
+
+	if len(p.partitions) == 0 || key == "" {
+		p.shared <- j
+		return
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	p.partitions[h.Sum32()%uint32(len(p.partitions))] <- j
+}
+
+// Close waits for the submitted messages to be handled
+func (p *pool) Close() {
+	close(p.shared)
+	for _, partition := range p.partitions {
+		close(partition)
+	}
+	p.wg.Wait()
+}
+
+// consume submits msgs to p until the server closes them. Cancelling ctx cancels the consumer, the server
+// then stops delivering and closes msgs, and consume returns once p handled what was delivered until then.
+// It returns errDeliveryEnded when msgs closed without ctx being cancelled, e.g. on a lost connection.
+func consume(ctx context.Context, msgs <-chan amqp.Delivery, cancelConsumer func() error, p *pool, key func(payload queueRequestPayload) string) error {
+	stop := context.AfterFunc(ctx, func() {
+		cancelConsumer()
+	})
+	defer stop()
+
+	for d := range msgs {
+		var payload queueRequestPayload
+		_ = json.Unmarshal(d.Body, &payload)
+
+		p.Submit(d, payload, key(payload))
+	}
+	p.Close()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return errDeliveryEnded
+}
+
+// partitionKey is the first of fields set in the message data, e.g. its activity_uuid
+func partitionKey(payload queueRequestPayload, fields ...string) string {
+	for _, field := range fields {
+		if value, ok := payload.Data[field].(string); ok && value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,115 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// TestOrderedPoolKeepsOrderPerKey submits interleaved messages of several keys to an ordered pool, the ones
+// sharing a key must be handled in the order they were submitted even when an earlier one is slow
+func TestOrderedPoolKeepsOrderPerKey(t *testing.T) {
+	var mu sync.Mutex
+	handled := map[string][]uint64{}
+
+	p := newPool(4, true, func(d amqp.Delivery, payload queueRequestPayload) {
+		if d.DeliveryTag%5 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := partitionKey(payload, "uuid")
+		handled[key] = append(handled[key], d.DeliveryTag)
+	})
+
+	keys := []string{"a", "b", "c", "d", "e", "f"}
+	for tag := uint64(1); tag <= 120; tag++ {
+		key := keys[tag%uint64(len(keys))]
+		p.Submit(amqp.Delivery{DeliveryTag: tag}, queueRequestPayload{Data: map[string]interface{}{"uuid": key}}, key)
+	}
+	p.Close()
+
+	for _, key := range keys {
+		tags := handled[key]
+		if len(tags) != 20 {
+			t.Fatalf("handled %d messages of %s, want 20", len(tags), key)
+		}
+		for i := 1; i < len(tags); i++ {
+			if tags[i] < tags[i-1] {
+				t.Fatalf("messages of %s handled out of order: %v", key, tags)
+			}
+		}
+	}
+}
+
+// TestTodoItemPartitionKey makes sure an update and a delete of the same todo item share a partition,
+// the delete only names the uuid of the item
+func TestTodoItemPartitionKey(t *testing.T) {
+	update := queueRequestPayload{Action: "update", Data: map[string]interface{}{"uuid": "item", "activity_uuid": "activity"}}
+	remove := queueRequestPayload{Action: "delete", Data: map[string]interface{}{"uuid": "item"}}
+	create := queueRequestPayload{Action: "create", Data: map[string]interface{}{"activity_uuid": "activity"}}
+
+	if todoItemPartitionKey(update) != todoItemPartitionKey(remove) {
+		t.Fatal("update and delete of a todo item are in different partitions")
+	}
+	if got := todoItemPartitionKey(create); got != "activity" {
+		t.Fatalf("create partitioned by %q, want its activity group", got)
+	}
+}
+
+// TestConsumeDrainsOnCancel makes sure a cancelled worker stops the consumer and waits for the messages
+// in flight, they still reply through the publisher the app closes afterwards
+func TestConsumeDrainsOnCancel(t *testing.T) {
+	msgs := make(chan amqp.Delivery)
+	handling, release := make(chan struct{}), make(chan struct{})
+	var handled []uint64
+	p := newPool(2, false, func(d amqp.Delivery, payload queueRequestPayload) {
+		close(handling)
+		<-release
+		handled = append(handled, d.DeliveryTag)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		// like the server, closing the deliveries once the consumer is cancelled
+		done <- consume(ctx, msgs, func() error {
+			close(msgs)
+			return nil
+		}, p, activityGroupPartitionKey)
+	}()
+
+	msgs <- amqp.Delivery{DeliveryTag: 1, Body: []byte(`{"action":"delete","data":{"uuid":"activity"}}`)}
+	<-handling
+	cancel()
+
+	select {
+	case err := <-done:
+		t.Fatalf("consume returned %v with a message in flight", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("consume returned %v after a cancel, want nil", err)
+	}
+	if len(handled) != 1 {
+		t.Fatalf("handled %v, want the message in flight", handled)
+	}
+}
+
+func TestConsumeReportsEndedDeliveries(t *testing.T) {
+	msgs := make(chan amqp.Delivery)
+	close(msgs)
+
+	err := consume(context.Background(), msgs, func() error { return nil }, newPool(1, false, nil), activityGroupPartitionKey)
+	if !errors.Is(err, errDeliveryEnded) {
+		t.Fatalf("consume returned %v on closed deliveries, want %v", err, errDeliveryEnded)
+	}
+}
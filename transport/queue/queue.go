@@ -1,98 +1,234 @@
 package queue
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-
+	"mime"
+	"sync"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/i18n"
+	"github.com/Adhiana46/go-restapi-template/pkg/asyncapi"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
+	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
+	"github.com/Adhiana46/go-restapi-template/pkg/rabbitmq"
+	"github.com/Adhiana46/go-restapi-template/pkg/reporter"
+	"github.com/Adhiana46/go-restapi-template/pkg/tracing"
 	amqp "github.com/rabbitmq/amqp091-go"
+	log "github.com/sirupsen/logrus"
 )
 
 type QueueWorker interface {
 	GetWorkerName() string
-	Listen() error
+	Listen(ctx context.Context) error
+	// Status is nil while the worker is consuming its request queue
+	Status() error
 	handlePayload(d amqp.Delivery, payload queueRequestPayload)
 }
 
+var (
+	errNotConsuming  = errors.New("not consuming yet")
+	errDeliveryEnded = errors.New("delivery channel closed")
+	errStopped       = errors.New("stopped")
+)
+
+// consumerState tracks whether a worker is consuming, for the readiness endpoint
+type consumerState struct {
+	mu  sync.RWMutex
+	err error
+}
+
+func newConsumerState() *consumerState {
+	return &consumerState{err: errNotConsuming}
+}
+
+func (s *consumerState) Status() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.err
+}
+
+func (s *consumerState) setStatus(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
 type queueRequestPayload struct {
-	Action string                 `json:"action"`
-	Data   map[string]interface{} `json:"data"`
+	Action string `json:"action"`
+	// Version of the data schema, 1 when omitted
+	Version int                    `json:"version"`
+	Data    map[string]interface{} `json:"data"`
+	// ExecuteAt holds the request until then when in the future, see schedulePayload
+	ExecuteAt *time.Time `json:"execute_at,omitempty"`
 }
 
-func successResponse(conn *amqp.Connection, queueName string, action string, data interface{}) error {
-	ch, err := conn.Channel()
+// upcastPayload checks the content type of d and converts payload.Data to the current version of its action
+func upcastPayload(ctx context.Context, spec *asyncapi.Document, queueName string, d amqp.Delivery, payload queueRequestPayload) (queueRequestPayload, error) {
+	// text/plain is what producers sent before the content type was checked
+	if d.ContentType != "" {
+		mediaType, _, err := mime.ParseMediaType(d.ContentType)
+		if err != nil || (mediaType != "application/json" && mediaType != "text/plain") {
+			return payload, apperror.BadRequest(apperror.CodeUnsupportedContentType, fmt.Sprintf("unsupported content type %q, should be application/json", d.ContentType))
+		}
+	}
+
+	if payload.Version == 0 {
+		payload.Version = 1
+	}
+
+	data, err := spec.Upcast(queueName, payload.Action, payload.Version, payload.Data)
 	if err != nil {
-		return err
+		var versionErr *asyncapi.VersionError
+		if errors.As(err, &versionErr) {
+			return payload, apperror.BadRequest(apperror.CodeUnsupportedVersion, err.Error())
+		}
+		return payload, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
+	}
+
+	if deprecation := spec.Deprecation(queueName, payload.Action, payload.Version); deprecation != "" {
+		logging.FromContext(ctx).Warnf("[%s] %s received in version %d, %s", queueName, payload.Action, payload.Version, deprecation)
+	}
+
+	payload.Data = data
+	return payload, nil
+}
+
+// withMessageFields tags the log lines of a message with its id and the uuids it targets
+func withMessageFields(ctx context.Context, d amqp.Delivery, queueName string, payload queueRequestPayload) context.Context {
+	requestId := d.MessageId
+	if requestId == "" {
+		requestId = d.CorrelationId
 	}
-	defer ch.Close()
 
-	q, err := ch.QueueDeclare(
-		fmt.Sprintf("%s.%s", queueName, action), // name
-		false,                                   // durable
-		false,                                   // delete when unused
-		false,                                   // exclusive
-		false,                                   // no-wait
-		nil,                                     // arguments
-	)
+	fields := log.Fields{
+		"request_id": logging.RequestId(requestId),
+		"worker":     queueName,
+		"action":     payload.Action,
+	}
+	for _, key := range []string{"uuid", "activity_uuid"} {
+		if value, ok := payload.Data[key].(string); ok {
+			fields[key] = value
+		}
+	}
 
+	return logging.WithFields(ctx, fields)
+}
+
+// Replies publishes the results of the workers on their reply, error and dead letter queues
+type Replies struct {
+	publisher rabbitmq.Publisher
+	// durable declares the reply and error queues durable, dead letter queues always are
+	durable bool
+}
+
+func NewReplies(publisher rabbitmq.Publisher, durable bool) *Replies {
+	return &Replies{
+		publisher: publisher,
+		durable:   durable,
+	}
+}
+
+// recoverPayload keeps a panic while handling d from crashing the consumer, deferred by handlePayload.
+// The panic is logged with its stack, reported and answered on the error queue, d is moved to the dead letter queue.
+func recoverPayload(ctx context.Context, replies *Replies, translator i18n.Translator, queueMetrics *metrics.QueueMetrics, errReporter reporter.Reporter, queueName string, action string, d amqp.Delivery, failure *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	report := reporter.Panic(fmt.Sprintf("panic in %s worker %s", queueName, action), r, logging.FromContext(ctx).Data)
+	logging.FromContext(ctx).Errorf("[%s] panic handling %s: %v\n%s", queueName, action, r, report.Stack)
+	errReporter.Report(report)
+	queueMetrics.Panicked(queueName)
+
+	*failure = fmt.Errorf("panic: %v", r)
+	errorResponse(ctx, replies, translator, queueName, action, messageLocale(d), *failure)
+
+	// the message would panic again if requeued, keep it aside for inspection instead
+	if err := deadLetter(ctx, replies, queueName, d, report.Message); err != nil {
+		logging.FromContext(ctx).Errorf("[%s] dead lettering message: %s", queueName, err)
+		d.Reject(false)
+		return
+	}
+	d.Ack(false)
+}
+
+// deadLetter publishes d as received to the dead letter queue of queueName
+func deadLetter(ctx context.Context, replies *Replies, queueName string, d amqp.Delivery, reason string) error {
+	headers := amqp.Table{}
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+	headers[panicHeader] = reason
+
+	return replies.publisher.Publish(ctx, rabbitmq.Queue{Name: fmt.Sprintf("%s.dead-letter", queueName), Durable: true}, amqp.Publishing{
+		Headers:       headers,
+		ContentType:   d.ContentType,
+		MessageId:     d.MessageId,
+		CorrelationId: d.CorrelationId,
+		Body:          d.Body,
+	})
+}
+
+// reply publishes data as JSON on queueName, once confirmed by the broker
+func (r *Replies) reply(ctx context.Context, queueName string, data any) (err error) {
 	dataJson, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
-	err = ch.Publish(
-		"",
-		q.Name,
-		false,
-		false,
-		amqp.Publishing{
-			ContentType: "text/plain",
-			Body:        dataJson,
-		},
-	)
+	ctx, span, headers := tracing.StartPublish(ctx, queueName)
+	defer func() { tracing.End(span, err) }()
 
+	err = r.publisher.Publish(ctx, rabbitmq.Queue{Name: queueName, Durable: r.durable}, amqp.Publishing{
+		Headers:     headers,
+		ContentType: "application/json",
+		Body:        dataJson,
+	})
 	if err != nil {
-		return err
+		logging.FromContext(ctx).Errorf("[%s] publishing reply: %s", queueName, err)
 	}
 
-	return nil
+	return err
 }
 
-func errorResponse(conn *amqp.Connection, queueName string, action string, errAct error) error {
-	ch, err := conn.Channel()
-	if err != nil {
-		return err
-	}
-	defer ch.Close()
+func successResponse(ctx context.Context, replies *Replies, queueName string, action string, data interface{}) error {
+	return replies.reply(ctx, fmt.Sprintf("%s.%s", queueName, action), data)
+}
+
+// localeHeader is the AMQP header carrying the language of error messages, e.g. en or id
+const localeHeader = "locale"
+
+// panicHeader carries the panic of a dead lettered message
+const panicHeader = "x-panic"
 
-	q, err := ch.QueueDeclare(
-		fmt.Sprintf("%s.error", queueName), // name
-		false,                              // durable
-		false,                              // delete when unused
-		false,                              // exclusive
-		false,                              // no-wait
-		nil,                                // arguments
-	)
+func messageLocale(d amqp.Delivery) string {
+	locale, _ := d.Headers[localeHeader].(string)
+	return locale
+}
+
+func errorResponse(ctx context.Context, replies *Replies, translator i18n.Translator, queueName string, action string, locale string, errAct error) error {
+	appErr := apperror.From(errAct)
+	trans := translator.Get(locale)
+	if appErr.Kind == apperror.KindInternal {
+		logging.FromContext(ctx).Errorf("[%s] %s failed: %s", queueName, action, errAct)
+	}
 
 	data := map[string]interface{}{
 		"action": action,
-		"error":  errAct.Error(),
+		"code":   appErr.Code,
+		"error":  translator.Error(trans, appErr),
 	}
-	dataJson, _ := json.Marshal(data)
-
-	err = ch.Publish(
-		"",
-		q.Name,
-		false,
-		false,
-		amqp.Publishing{
-			ContentType: "text/plain",
-			Body:        dataJson,
-		},
-	)
-
-	if err != nil {
-		return err
+	if validationErrs := appErr.ValidationErrors(); validationErrs != nil {
+		data["errors"] = parserPkg.ValidationErrors(validationErrs, &trans)
 	}
 
-	return nil
+	return replies.reply(ctx, fmt.Sprintf("%s.error", queueName), data)
 }
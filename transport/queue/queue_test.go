@@ -0,0 +1,238 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Adhiana46/go-restapi-template/config"
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/i18n"
+	"github.com/Adhiana46/go-restapi-template/pkg/asyncapi"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
+	"github.com/Adhiana46/go-restapi-template/pkg/rabbitmq"
+	"github.com/Adhiana46/go-restapi-template/pkg/reporter"
+	"github.com/go-playground/validator/v10"
+	"github.com/prometheus/client_golang/prometheus"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func testSpec() (*asyncapi.Document, string) {
+	return NewAsyncAPIDocument("activity-group", "todo-item", "notifications"), "todo-item"
+}
+
+func updatePayload(version int, data map[string]interface{}) queueRequestPayload {
+	data["uuid"] = "item"
+	data["activity_uuid"] = "activity"
+	data["name"] = "todo item"
+
+	return queueRequestPayload{Action: "update", Version: version, Data: data}
+}
+
+func appErrorCode(err error) apperror.Code {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		return ""
+	}
+
+	return appErr.Code
+}
+
+func TestUpcastTodoItemUpdateV1(t *testing.T) {
+	spec, queueName := testSpec()
+
+	// producers without a version send version 1, whose updates never touched the completion or the due date
+	for _, version := range []int{0, 1} {
+		payload, err := upcastPayload(context.Background(), spec, queueName, amqp.Delivery{ContentType: "application/json"}, updatePayload(version, map[string]interface{}{}))
+		if err != nil {
+			t.Fatalf("upcasting version %d: %s", version, err)
+		}
+		for _, field := range []string{"is_completed", "due_at", "clear_due_at"} {
+			if _, ok := payload.Data[field]; ok {
+				t.Fatalf("version %d set %s %v, want the item kept as is", version, field, payload.Data[field])
+			}
+		}
+		if err := spec.Validate(queueName, payload.Action, payload.Data); err != nil {
+			t.Fatalf("upcast payload rejected: %s", err)
+		}
+	}
+
+	// is_completed and clear_due_at aren't part of version 1
+	payload, err := upcastPayload(context.Background(), spec, queueName, amqp.Delivery{}, updatePayload(1, map[string]interface{}{"is_completed": false, "clear_due_at": true}))
+	if err != nil {
+		t.Fatalf("upcasting: %s", err)
+	}
+	if _, ok := payload.Data["is_completed"]; ok {
+		t.Fatal("version 1 reopened the item")
+	}
+	if _, ok := payload.Data["clear_due_at"]; ok {
+		t.Fatal("version 1 removed the due date")
+	}
+}
+
+func TestUpcastTodoItemUpdateCurrentVersion(t *testing.T) {
+	spec, queueName := testSpec()
+
+	payload, err := upcastPayload(context.Background(), spec, queueName, amqp.Delivery{}, updatePayload(2, map[string]interface{}{}))
+	if err != nil {
+		t.Fatalf("upcasting: %s", err)
+	}
+	for _, field := range []string{"is_completed", "due_at", "clear_due_at"} {
+		if _, ok := payload.Data[field]; ok {
+			t.Fatalf("version 2 without %s should keep the item as is, not set it", field)
+		}
+	}
+}
+
+func TestUpcastRejectsUnsupportedVersion(t *testing.T) {
+	spec, queueName := testSpec()
+
+	_, err := upcastPayload(context.Background(), spec, queueName, amqp.Delivery{}, updatePayload(99, map[string]interface{}{}))
+	if code := appErrorCode(err); code != apperror.CodeUnsupportedVersion {
+		t.Fatalf("got %v (%s), want %s", err, code, apperror.CodeUnsupportedVersion)
+	}
+}
+
+func TestUpcastRejectsContentType(t *testing.T) {
+	spec, queueName := testSpec()
+
+	for _, contentType := range []string{"application/xml", "not a media type"} {
+		_, err := upcastPayload(context.Background(), spec, queueName, amqp.Delivery{ContentType: contentType}, updatePayload(2, map[string]interface{}{}))
+		if code := appErrorCode(err); code != apperror.CodeUnsupportedContentType {
+			t.Fatalf("%q: got %v (%s), want %s", contentType, err, code, apperror.CodeUnsupportedContentType)
+		}
+	}
+
+	for _, contentType := range []string{"", "application/json; charset=utf-8", "text/plain"} {
+		if _, err := upcastPayload(context.Background(), spec, queueName, amqp.Delivery{ContentType: contentType}, updatePayload(2, map[string]interface{}{})); err != nil {
+			t.Fatalf("%q rejected: %s", contentType, err)
+		}
+	}
+}
+
+func TestRepliesDurability(t *testing.T) {
+	translator, err := i18n.NewTranslator(validator.New(), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, durable := range []bool{true, false} {
+		publisher := &fakePublisher{}
+		replies := NewReplies(publisher, durable)
+
+		successResponse(context.Background(), replies, "todo-item", "updated", map[string]string{"uuid": "item"})
+		errorResponse(context.Background(), replies, translator, "todo-item", "update", "en", errors.New("failed"))
+		deadLetter(context.Background(), replies, "todo-item", amqp.Delivery{MessageId: "message", Body: []byte("{")}, "panic")
+
+		if len(publisher.published) != 3 {
+			t.Fatalf("published %d messages, want 3", len(publisher.published))
+		}
+		for i, name := range []string{"todo-item.updated", "todo-item.error"} {
+			if q := publisher.published[i].queue; q.Name != name || q.Durable != durable {
+				t.Errorf("replied on %+v, want %s durable %t", q, name, durable)
+			}
+		}
+
+		// dead letter queues are durable whatever the replies are
+		p := publisher.published[2]
+		if p.queue.Name != "todo-item.dead-letter" || !p.queue.Durable {
+			t.Errorf("dead lettered on %+v, want the durable todo-item.dead-letter", p.queue)
+		}
+		if p.msg.MessageId != "message" || string(p.msg.Body) != "{" || p.msg.Headers[panicHeader] != "panic" {
+			t.Errorf("dead lettered %+v, want the message as received with its panic", p.msg)
+		}
+	}
+}
+
+func TestReplyReturnsUnconfirmedPublishing(t *testing.T) {
+	replies := NewReplies(&fakePublisher{err: rabbitmq.ErrUnconfirmed}, true)
+
+	err := successResponse(context.Background(), replies, "todo-item", "updated", map[string]string{"uuid": "item"})
+	if !errors.Is(err, rabbitmq.ErrUnconfirmed) {
+		t.Fatalf("reply returned %v, want the unconfirmed publishing", err)
+	}
+}
+
+// panicking handles the payload of d with a handler that panics, the way handlePayload recovers it
+func panicking(replies *Replies, d amqp.Delivery) {
+	translator, _ := i18n.NewTranslator(validator.New(), "en")
+
+	var failure error
+	defer recoverPayload(context.Background(), replies, translator, metrics.NewQueueMetrics(prometheus.NewRegistry()), reporter.Nop(), "todo-item", "update", d, &failure)
+
+	panic("handler bug")
+}
+
+func TestRecoverPayloadSettlesOnDeadLetterConfirm(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	panicking(NewReplies(&fakePublisher{}, true), amqp.Delivery{Acknowledger: ack})
+	if !ack.acked || ack.rejected {
+		t.Fatalf("confirmed dead letter: acked %t, rejected %t, want acked", ack.acked, ack.rejected)
+	}
+
+	// the message is only acked once its dead letter copy is confirmed
+	ack = &fakeAcknowledger{}
+	panicking(NewReplies(&fakePublisher{err: rabbitmq.ErrNacked}, true), amqp.Delivery{Acknowledger: ack})
+	if ack.acked || !ack.rejected {
+		t.Fatalf("nacked dead letter: acked %t, rejected %t, want rejected", ack.acked, ack.rejected)
+	}
+}
+
+// TestHandlerPanicIsDeadLettered drives a panicking service call through the worker the way a delivery is handled
+func TestHandlerPanicIsDeadLettered(t *testing.T) {
+	translator, err := i18n.NewTranslator(validator.New(), "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	publisher := &fakePublisher{}
+	registry := prometheus.NewRegistry()
+	// fakeTodoItemService panics on every call
+	w := NewTodoItemWorker(nil, NewReplies(publisher, true), "todo-item", config.WorkerConfig{Concurrency: 1, Prefetch: 1}, translator,
+		metrics.NewQueueMetrics(registry), reporter.Nop(), &fakeTodoItemService{}, &fakeScheduledActionService{}).(*todoItemWorker)
+
+	ack := &fakeAcknowledger{}
+	body := []byte(`{"action":"create","data":{"activity_uuid":"activity","name":"todo item"}}`)
+	w.handlePayload(amqp.Delivery{
+		Acknowledger:  ack,
+		ContentType:   "application/json",
+		MessageId:     "message",
+		CorrelationId: "correlation",
+		Headers:       amqp.Table{localeHeader: "en", "x-tenant": "acme"},
+		Body:          body,
+	}, queueRequestPayload{Action: "create", Data: map[string]interface{}{"activity_uuid": "activity", "name": "todo item"}})
+
+	if !ack.acked || ack.rejected {
+		t.Fatalf("delivery acked %t, rejected %t, want acked once dead lettered", ack.acked, ack.rejected)
+	}
+	if len(publisher.published) != 2 {
+		t.Fatalf("published %+v, want an error reply and a dead letter", publisher.published)
+	}
+	if reply := publisher.published[0]; reply.queue.Name != "todo-item.error" {
+		t.Fatalf("replied on %s, want todo-item.error", reply.queue.Name)
+	}
+
+	dead := publisher.published[1]
+	if dead.queue.Name != "todo-item.dead-letter" || string(dead.msg.Body) != string(body) ||
+		dead.msg.MessageId != "message" || dead.msg.CorrelationId != "correlation" || dead.msg.ContentType != "application/json" {
+		t.Fatalf("dead lettered %+v on %s, want the message as received", dead.msg, dead.queue.Name)
+	}
+	if dead.msg.Headers[localeHeader] != "en" || dead.msg.Headers["x-tenant"] != "acme" || dead.msg.Headers[panicHeader] == nil {
+		t.Fatalf("dead letter headers %v, want the original headers and the panic", dead.msg.Headers)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	panics := 0.0
+	for _, family := range families {
+		if family.GetName() == "todoapp_queue_message_panics_total" {
+			for _, metric := range family.GetMetric() {
+				panics += metric.GetCounter().GetValue()
+			}
+		}
+	}
+	if panics != 1 {
+		t.Fatalf("counted %v panics, want 1", panics)
+	}
+}
@@ -0,0 +1,80 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/asyncapi"
+	"github.com/Adhiana46/go-restapi-template/pkg/rabbitmq"
+	"github.com/Adhiana46/go-restapi-template/pkg/tracing"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// scheduledActionHeader carries the uuid of the scheduled action a request was dispatched from
+const scheduledActionHeader = "x-scheduled-action"
+
+// delayed tells whether payload asks to be handled later than now
+func delayed(payload queueRequestPayload) bool {
+	return payload.ExecuteAt != nil && payload.ExecuteAt.After(time.Now())
+}
+
+// schedulePayload stores a validated, upcast payload until its execute_at, activityField names the data field
+// holding the activity group uuid the action is listed under
+func schedulePayload(ctx context.Context, svc service.ScheduledActionService, spec *asyncapi.Document, queueName string, d amqp.Delivery, payload queueRequestPayload, activityField string) (*dto.ScheduledActionResponse, error) {
+	ent, err := svc.Schedule(ctx, dto.ScheduledActionCreateRequest{
+		Queue:        queueName,
+		Action:       payload.Action,
+		Version:      spec.Version(queueName, payload.Action),
+		Data:         payload.Data,
+		Locale:       messageLocale(d),
+		ActivityUuid: partitionKey(payload, activityField),
+		ExecuteAt:    *payload.ExecuteAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ScheduledActionToResponse(ent), nil
+}
+
+// DispatchScheduled publishes due actions back on the request queue of their worker, which handles them
+// as any other request. The message id and the x-scheduled-action header are the uuid of the action.
+// Delivery is at least once, an action is published again when marking it dispatched fails.
+func DispatchScheduled(publisher rabbitmq.Publisher) service.ScheduledActionDispatch {
+	return func(ctx context.Context, e *entity.ScheduledAction) (err error) {
+		data := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(e.Data), &data); err != nil {
+			return err
+		}
+
+		body, err := json.Marshal(queueRequestPayload{
+			Action:  e.Action,
+			Version: e.Version,
+			Data:    data,
+		})
+		if err != nil {
+			return err
+		}
+
+		queueName := fmt.Sprintf("%s.request", e.Queue)
+		ctx, span, headers := tracing.StartPublish(ctx, queueName)
+		defer func() { tracing.End(span, err) }()
+
+		headers[scheduledActionHeader] = e.Uuid
+		if e.Locale != "" {
+			headers[localeHeader] = e.Locale
+		}
+
+		return publisher.Publish(ctx, rabbitmq.Queue{Name: queueName, Durable: true}, amqp.Publishing{
+			Headers:     headers,
+			ContentType: "application/json",
+			MessageId:   e.Uuid,
+			Body:        body,
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/config"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
+	"github.com/Adhiana46/go-restapi-template/pkg/rabbitmq"
+	"github.com/Adhiana46/go-restapi-template/pkg/reporter"
+	"github.com/prometheus/client_golang/prometheus"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type published struct {
+	queue rabbitmq.Queue
+	msg   amqp.Publishing
+}
+
+// fakePublisher records every publishing, err stands in for a publishing the broker nacked or never confirmed
+type fakePublisher struct {
+	mu        sync.Mutex
+	published []published
+	err       error
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, q rabbitmq.Queue, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published = append(p.published, published{queue: q, msg: msg})
+	return p.err
+}
+
+func (p *fakePublisher) Close() error {
+	return nil
+}
+
+// fakeAcknowledger records how a delivery was settled
+type fakeAcknowledger struct {
+	acked, rejected bool
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.acked = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple bool, requeue bool) error {
+	a.rejected = true
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	a.rejected = true
+	return nil
+}
+
+type fakeScheduledActionService struct {
+	service.ScheduledActionService
+
+	scheduled []dto.ScheduledActionCreateRequest
+}
+
+func (s *fakeScheduledActionService) Schedule(ctx context.Context, req dto.ScheduledActionCreateRequest) (*entity.ScheduledAction, error) {
+	s.scheduled = append(s.scheduled, req)
+
+	data, _ := json.Marshal(req.Data)
+	return &entity.ScheduledAction{
+		Uuid:         "scheduled",
+		Queue:        req.Queue,
+		Action:       req.Action,
+		Version:      req.Version,
+		Data:         string(data),
+		ActivityUuid: &req.ActivityUuid,
+		Status:       entity.ScheduledActionPending,
+		ExecuteAt:    req.ExecuteAt,
+	}, nil
+}
+
+// fakeTodoItemService panics on every call, a delayed request must not reach it
+type fakeTodoItemService struct {
+	service.TodoItemService
+}
+
+func TestTodoItemWorkerSchedulesDelayedRequest(t *testing.T) {
+	publisher := &fakePublisher{}
+	scheduler := &fakeScheduledActionService{}
+	w := NewTodoItemWorker(nil, NewReplies(publisher, true), "todo-item", config.WorkerConfig{Concurrency: 1, Prefetch: 1}, nil,
+		metrics.NewQueueMetrics(prometheus.NewRegistry()), reporter.Nop(), &fakeTodoItemService{}, scheduler).(*todoItemWorker)
+
+	executeAt := time.Now().Add(time.Hour)
+	ack := &fakeAcknowledger{}
+	w.handlePayload(amqp.Delivery{Acknowledger: ack, ContentType: "application/json", Headers: amqp.Table{localeHeader: "en"}}, queueRequestPayload{
+		Action:    "create",
+		Data:      map[string]interface{}{"activity_uuid": "activity", "name": "todo item"},
+		ExecuteAt: &executeAt,
+	})
+
+	if !ack.acked || ack.rejected {
+		t.Fatalf("delivery acked %t, rejected %t", ack.acked, ack.rejected)
+	}
+	if len(scheduler.scheduled) != 1 {
+		t.Fatalf("scheduled %d actions, want 1", len(scheduler.scheduled))
+	}
+
+	req := scheduler.scheduled[0]
+	if req.Queue != "todo-item" || req.Action != "create" || req.ActivityUuid != "activity" || req.Locale != "en" || !req.ExecuteAt.Equal(executeAt) {
+		t.Fatalf("scheduled %+v", req)
+	}
+	if req.Version != 1 {
+		t.Fatalf("scheduled version %d, want the current version of create", req.Version)
+	}
+
+	if len(publisher.published) != 1 || publisher.published[0].queue.Name != "todo-item.scheduled" {
+		t.Fatalf("published %+v, want a reply on todo-item.scheduled", publisher.published)
+	}
+}
+
+func TestDispatchScheduledPublishesOnRequestQueue(t *testing.T) {
+	publisher := &fakePublisher{}
+	activityUuid := "activity"
+
+	err := DispatchScheduled(publisher)(context.Background(), &entity.ScheduledAction{
+		Uuid:         "scheduled",
+		Queue:        "todo-item",
+		Action:       "update",
+		Version:      2,
+		Data:         `{"uuid":"item","name":"todo item"}`,
+		Locale:       "id",
+		ActivityUuid: &activityUuid,
+	})
+	if err != nil {
+		t.Fatalf("dispatching: %s", err)
+	}
+
+	if len(publisher.published) != 1 {
+		t.Fatalf("published %d messages, want 1", len(publisher.published))
+	}
+	p := publisher.published[0]
+	if p.queue.Name != "todo-item.request" || !p.queue.Durable {
+		t.Fatalf("published on %+v, want the durable todo-item.request", p.queue)
+	}
+	if p.msg.MessageId != "scheduled" || p.msg.Headers[scheduledActionHeader] != "scheduled" || p.msg.Headers[localeHeader] != "id" {
+		t.Fatalf("message id %q, headers %v", p.msg.MessageId, p.msg.Headers)
+	}
+
+	var payload queueRequestPayload
+	if err := json.Unmarshal(p.msg.Body, &payload); err != nil {
+		t.Fatalf("decoding body: %s", err)
+	}
+	if payload.Action != "update" || payload.Version != 2 || payload.Data["uuid"] != "item" || payload.ExecuteAt != nil {
+		t.Fatalf("payload %+v", payload)
+	}
+}
@@ -1,29 +1,55 @@
 package queue
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/Adhiana46/go-restapi-template/config"
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
 	"github.com/Adhiana46/go-restapi-template/internal/dto"
 	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/i18n"
 	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/pkg/asyncapi"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	"github.com/Adhiana46/go-restapi-template/pkg/metrics"
+	"github.com/Adhiana46/go-restapi-template/pkg/reporter"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	"github.com/Adhiana46/go-restapi-template/pkg/tracing"
 	amqp "github.com/rabbitmq/amqp091-go"
-	log "github.com/sirupsen/logrus"
 )
 
 type todoItemWorker struct {
-	conn      *amqp.Connection
-	queueName string
-
-	svcTodoItem service.TodoItemService
+	conn       *amqp.Connection
+	replies    *Replies
+	queueName  string
+	cfg        config.WorkerConfig
+	spec       *asyncapi.Document
+	translator i18n.Translator
+	metrics    *metrics.QueueMetrics
+	reporter   reporter.Reporter
+	*consumerState
+
+	svcTodoItem        service.TodoItemService
+	svcScheduledAction service.ScheduledActionService
 }
 
-func NewTodoItemWorker(conn *amqp.Connection, queueName string, svcTodoItem service.TodoItemService) QueueWorker {
+func NewTodoItemWorker(conn *amqp.Connection, replies *Replies, queueName string, cfg config.WorkerConfig, translator i18n.Translator, queueMetrics *metrics.QueueMetrics, errReporter reporter.Reporter, svcTodoItem service.TodoItemService, svcScheduledAction service.ScheduledActionService) QueueWorker {
 	return &todoItemWorker{
-		conn:      conn,
-		queueName: queueName,
-
-		svcTodoItem: svcTodoItem,
+		conn:       conn,
+		replies:    replies,
+		queueName:  queueName,
+		cfg:        cfg,
+		spec:       asyncapi.NewDocument(queueName, "").AddQueue(queueName, todoItemActions()...),
+		translator: translator,
+		metrics:    queueMetrics,
+		reporter:   errReporter,
+
+		consumerState:      newConsumerState(),
+		svcTodoItem:        svcTodoItem,
+		svcScheduledAction: svcScheduledAction,
 	}
 }
 
@@ -31,7 +57,20 @@ func (w *todoItemWorker) GetWorkerName() string {
 	return w.queueName
 }
 
-func (w *todoItemWorker) Listen() error {
+// Listen consumes the request queue until ctx is cancelled, the messages delivered until then are handled
+// before it returns, so the publisher and the connection must outlive it
+func (w *todoItemWorker) Listen(ctx context.Context) error {
+	err := w.listen(ctx)
+	if err == nil {
+		w.setStatus(errStopped)
+	} else {
+		w.setStatus(err)
+	}
+
+	return err
+}
+
+func (w *todoItemWorker) listen(ctx context.Context) error {
 	ch, err := w.conn.Channel()
 	if err != nil {
 		return err
@@ -52,88 +91,147 @@ func (w *todoItemWorker) Listen() error {
 
 	// set Qos
 	err = ch.Qos(
-		1,     // prefetch count
-		0,     // prefetch size
-		false, // global
+		w.cfg.Prefetch, // prefetch count
+		0,              // prefetch size
+		false,          // global
 	)
 	if err != nil {
 		return err
 	}
 
 	msgs, err := ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		false,  // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
+		q.Name,      // queue
+		w.queueName, // consumer
+		false,       // auto-ack
+		false,       // exclusive
+		false,       // no-local
+		false,       // no-wait
+		nil,         // args
 	)
 	if err != nil {
 		return err
 	}
 
-	var forever chan struct{}
-	go func() {
-		for d := range msgs {
-			var payload queueRequestPayload
-			_ = json.Unmarshal(d.Body, &payload)
+	w.setStatus(nil)
 
-			go w.handlePayload(d, payload)
-		}
-	}()
-
-	<-forever
+	p := newPool(w.cfg.Concurrency, w.cfg.Ordered, w.handlePayload)
+	return consume(ctx, msgs, func() error {
+		return ch.Cancel(w.queueName, false)
+	}, p, todoItemPartitionKey)
+}
 
-	return nil
+// todoItemPartitionKey orders update and delete per todo item, delete only names its uuid,
+// and create per activity group
+func todoItemPartitionKey(payload queueRequestPayload) string {
+	return partitionKey(payload, "uuid", "activity_uuid")
 }
 
 func (w *todoItemWorker) handlePayload(d amqp.Delivery, payload queueRequestPayload) {
+	start := time.Now()
+	action := "invalid"
+	var failure error
+	defer func() {
+		w.metrics.Observe(w.queueName, action, start, failure)
+	}()
+
+	ctx, span := tracing.StartConsume(d, fmt.Sprintf("%s.request", w.queueName))
+	defer func() {
+		tracing.End(span, failure)
+	}()
+
+	ctx = withMessageFields(ctx, d, w.queueName, payload)
+	ctx = sqldb.WithSession(ctx)
+	defer func() {
+		entry := logging.FromContext(ctx).WithField("duration_ms", time.Since(start).Milliseconds())
+		if failure != nil {
+			entry.WithError(failure).Warnf("[%s] Handled message: %s", w.queueName, payload.Action)
+		} else {
+			entry.Infof("[%s] Handled message: %s", w.queueName, payload.Action)
+		}
+	}()
+	defer recoverPayload(ctx, w.replies, w.translator, w.metrics, w.reporter, w.queueName, payload.Action, d, &failure)
+
+	payload, err := upcastPayload(ctx, w.spec, w.queueName, d, payload)
+	if err != nil {
+		failure = err
+		errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
+		d.Ack(false)
+		return
+	}
+
 	dataJson, err := json.Marshal(payload.Data)
 	if err != nil {
-		errorResponse(w.conn, w.queueName, payload.Action, err)
+		failure = err
+		errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
+		d.Ack(false)
+		return
+	}
+
+	logging.FromContext(ctx).Infof("[%s] Receiving message: %s -> %s", w.queueName, payload.Action, string(dataJson))
+
+	// reject messages that don't match the published contract before dispatching them
+	if err := w.spec.Validate(w.queueName, payload.Action, payload.Data); err != nil {
+		failure = err
+		errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), apperror.BadRequest(apperror.CodeInvalidMessage, err.Error()))
 		d.Ack(false)
 		return
 	}
 
-	log.Infof("[%s] Receiving message: %s -> %s", w.queueName, payload.Action, string(dataJson))
+	// the action is one of the contract once validated, safe to use as a label
+	action = payload.Action
+
+	// requests due later wait in the scheduler, which publishes them back on this queue at execute_at
+	if delayed(payload) {
+		scheduled, err := schedulePayload(ctx, w.svcScheduledAction, w.spec, w.queueName, d, payload, "activity_uuid")
+		if err != nil {
+			failure = err
+			errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
+		} else {
+			successResponse(ctx, w.replies, w.queueName, "scheduled", scheduled)
+		}
+		d.Ack(false)
+		return
+	}
 
 	switch payload.Action {
 	case "create":
-		todoItem, err := w.handleCreate(dataJson)
+		todoItem, err := w.handleCreate(ctx, dataJson)
 		if err != nil {
-			errorResponse(w.conn, w.queueName, payload.Action, err)
+			failure = err
+			errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
 		} else {
-			successResponse(w.conn, w.queueName, "created", todoItem)
+			successResponse(ctx, w.replies, w.queueName, "created", todoItem)
 		}
 	case "update":
-		todoItem, err := w.handleUpdate(dataJson)
+		todoItem, err := w.handleUpdate(ctx, dataJson)
 		if err != nil {
-			errorResponse(w.conn, w.queueName, payload.Action, err)
+			failure = err
+			errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
 		} else {
-			successResponse(w.conn, w.queueName, "updated", todoItem)
+			successResponse(ctx, w.replies, w.queueName, "updated", todoItem)
 		}
 	case "delete":
-		todoItem, err := w.handleDelete(dataJson)
+		todoItem, err := w.handleDelete(ctx, dataJson)
 		if err != nil {
-			errorResponse(w.conn, w.queueName, payload.Action, err)
+			failure = err
+			errorResponse(ctx, w.replies, w.translator, w.queueName, payload.Action, messageLocale(d), err)
 		} else {
-			successResponse(w.conn, w.queueName, "deleted", todoItem)
+			successResponse(ctx, w.replies, w.queueName, "deleted", todoItem)
 		}
 	}
 
 	d.Ack(false)
 }
 
-func (w *todoItemWorker) handleCreate(data []byte) (*entity.TodoItem, error) {
+func (w *todoItemWorker) handleCreate(ctx context.Context, data []byte) (*entity.TodoItem, error) {
 	reqDto := dto.TodoItemCreateRequest{}
 
 	err := json.Unmarshal(data, &reqDto)
 	if err != nil {
-		return nil, err
+		return nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
 	}
 
-	todoItem, err := w.svcTodoItem.Create(reqDto)
+	todoItem, err := w.svcTodoItem.Create(ctx, reqDto)
 	if err != nil {
 		return nil, err
 	}
@@ -141,15 +239,15 @@ func (w *todoItemWorker) handleCreate(data []byte) (*entity.TodoItem, error) {
 	return todoItem, nil
 }
 
-func (w *todoItemWorker) handleUpdate(data []byte) (*entity.TodoItem, error) {
+func (w *todoItemWorker) handleUpdate(ctx context.Context, data []byte) (*entity.TodoItem, error) {
 	reqDto := dto.TodoItemUpdateRequest{}
 
 	err := json.Unmarshal(data, &reqDto)
 	if err != nil {
-		return nil, err
+		return nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
 	}
 
-	todoItem, err := w.svcTodoItem.Update(reqDto)
+	todoItem, err := w.svcTodoItem.Update(ctx, reqDto)
 	if err != nil {
 		return nil, err
 	}
@@ -157,20 +255,20 @@ func (w *todoItemWorker) handleUpdate(data []byte) (*entity.TodoItem, error) {
 	return todoItem, nil
 }
 
-func (w *todoItemWorker) handleDelete(data []byte) (*entity.TodoItem, error) {
+func (w *todoItemWorker) handleDelete(ctx context.Context, data []byte) (*entity.TodoItem, error) {
 	reqDto := dto.TodoItemUuidRequest{}
 
 	err := json.Unmarshal(data, &reqDto)
 	if err != nil {
-		return nil, err
+		return nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
 	}
 
-	todoItem, err := w.svcTodoItem.FindByUuid(reqDto)
+	todoItem, err := w.svcTodoItem.FindByUuid(ctx, reqDto)
 	if err != nil {
 		return nil, err
 	}
 
-	err = w.svcTodoItem.Delete(reqDto)
+	err = w.svcTodoItem.Delete(ctx, reqDto)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,108 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+)
+
+var errUnknownMutation = apperror.BadRequest(apperror.CodeInvalidMessage, "unknown mutation, resource should be activity-group or todo-item and action create, update or delete")
+
+// dispatch runs a mutation against the services and returns the past tense action with the affected resource
+func (h *handler) dispatch(ctx context.Context, msg clientMessage) (string, any, error) {
+	dataJson, err := json.Marshal(msg.Data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch msg.Resource {
+	case resourceActivityGroup:
+		return h.dispatchActivityGroup(ctx, msg.Action, dataJson)
+	case resourceTodoItem:
+		return h.dispatchTodoItem(ctx, msg.Action, dataJson)
+	}
+
+	return "", nil, errUnknownMutation
+}
+
+func (h *handler) dispatchActivityGroup(ctx context.Context, action string, data []byte) (string, any, error) {
+	switch action {
+	case "create":
+		req := dto.ActivityGroupCreateRequest{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return "", nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
+		}
+		activityGroup, err := h.svcActivityGroup.Create(ctx, req)
+		if err != nil {
+			return "", nil, err
+		}
+		return "created", dto.ActivityGroupToResponse(activityGroup), nil
+	case "update":
+		req := dto.ActivityGroupUpdateRequest{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return "", nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
+		}
+		activityGroup, err := h.svcActivityGroup.Update(ctx, req)
+		if err != nil {
+			return "", nil, err
+		}
+		return "updated", dto.ActivityGroupToResponse(activityGroup), nil
+	case "delete":
+		req := dto.ActivityGroupUuidRequest{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return "", nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
+		}
+		activityGroup, err := h.svcActivityGroup.FindByUuid(ctx, req)
+		if err != nil {
+			return "", nil, err
+		}
+		if err := h.svcActivityGroup.Delete(ctx, req); err != nil {
+			return "", nil, err
+		}
+		return "deleted", dto.ActivityGroupToResponse(activityGroup), nil
+	}
+
+	return "", nil, errUnknownMutation
+}
+
+func (h *handler) dispatchTodoItem(ctx context.Context, action string, data []byte) (string, any, error) {
+	switch action {
+	case "create":
+		req := dto.TodoItemCreateRequest{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return "", nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
+		}
+		todoItem, err := h.svcTodoItem.Create(ctx, req)
+		if err != nil {
+			return "", nil, err
+		}
+		return "created", dto.TodoItemToResponse(todoItem), nil
+	case "update":
+		req := dto.TodoItemUpdateRequest{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return "", nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
+		}
+		todoItem, err := h.svcTodoItem.Update(ctx, req)
+		if err != nil {
+			return "", nil, err
+		}
+		return "updated", dto.TodoItemToResponse(todoItem), nil
+	case "delete":
+		req := dto.TodoItemUuidRequest{}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return "", nil, apperror.BadRequest(apperror.CodeInvalidMessage, err.Error())
+		}
+		todoItem, err := h.svcTodoItem.FindByUuid(ctx, req)
+		if err != nil {
+			return "", nil, err
+		}
+		if err := h.svcTodoItem.Delete(ctx, req); err != nil {
+			return "", nil, err
+		}
+		return "deleted", dto.TodoItemToResponse(todoItem), nil
+	}
+
+	return "", nil, errUnknownMutation
+}
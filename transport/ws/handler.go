@@ -0,0 +1,212 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/auth"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/Adhiana46/go-restapi-template/pkg/logging"
+	"github.com/Adhiana46/go-restapi-template/pkg/openapi"
+	parserPkg "github.com/Adhiana46/go-restapi-template/pkg/parser"
+	"github.com/Adhiana46/go-restapi-template/pkg/sqldb"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	log "github.com/sirupsen/logrus"
+)
+
+type Handler interface {
+	RegisterRoutes(r fiber.Router) Handler
+	Docs() []openapi.Operation
+}
+
+type handler struct {
+	authenticator auth.Authenticator
+	hub           stream.Hub
+
+	svcActivityGroup service.ActivityGroupService
+	svcTodoItem      service.TodoItemService
+}
+
+func NewHandler(authenticator auth.Authenticator, hub stream.Hub, svcActivityGroup service.ActivityGroupService, svcTodoItem service.TodoItemService) Handler {
+	return &handler{
+		authenticator:    authenticator,
+		hub:              hub,
+		svcActivityGroup: svcActivityGroup,
+		svcTodoItem:      svcTodoItem,
+	}
+}
+
+func (h *handler) RegisterRoutes(r fiber.Router) Handler {
+	r.Use("/", func(c *fiber.Ctx) error {
+		if !websocket.IsWebSocketUpgrade(c) {
+			return fiber.ErrUpgradeRequired
+		}
+		return c.Next()
+	})
+	r.Use("/", auth.FiberMiddleware(h.authenticator))
+	r.Get("/", websocket.New(h.serve))
+
+	return h
+}
+
+func (h *handler) Docs() []openapi.Operation {
+	return []openapi.Operation{
+		{Method: "GET", Path: "/", Summary: "Open a WebSocket collaboration channel, authenticated with a bearer token or the token query parameter", Tags: []string{"Live"}, Status: http.StatusSwitchingProtocols},
+	}
+}
+
+type session struct {
+	h    *handler
+	conn *websocket.Conn
+	user string
+	sub  *stream.Subscription
+	// ctx carries the log fields of the upgrade request into every message
+	ctx context.Context
+
+	writeMu sync.Mutex
+}
+
+func (h *handler) serve(conn *websocket.Conn) {
+	s := &session{
+		h:    h,
+		conn: conn,
+		user: conn.Locals(auth.UserLocalsKey).(string),
+		sub:  h.hub.Subscribe(),
+	}
+	defer s.sub.Close()
+
+	requestId, _ := conn.Locals(logging.RequestIdLocalsKey).(string)
+	s.ctx = logging.WithFields(context.Background(), log.Fields{"request_id": requestId, "user": s.user})
+
+	logging.FromContext(s.ctx).Infof("[ws] %s connected", s.user)
+
+	conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.writeLoop(done)
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			logging.FromContext(s.ctx).Infof("[ws] %s disconnected: %s", s.user, err)
+			return
+		}
+
+		var msg clientMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			s.send(serverMessage{Type: messageError, Code: string(apperror.CodeInvalidMessage), Error: "malformed message, should be a JSON object"})
+			continue
+		}
+
+		s.handleMessage(sqldb.WithSession(s.ctx), msg)
+	}
+}
+
+// writeLoop forwards live events of subscribed groups and keeps the connection alive with pings
+func (s *session) writeLoop(done <-chan struct{}) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	var lastId int64
+	for {
+		select {
+		case <-done:
+			return
+		case e, ok := <-s.sub.C:
+			if !ok {
+				if err := s.sub.Err(); err != nil {
+					s.send(serverMessage{Type: messageError, EventId: lastId, Code: string(apperror.CodeStreamReset), Error: err.Error()})
+				}
+				s.close()
+				return
+			}
+			lastId = e.ID
+			s.send(serverMessage{
+				Type:         messageEvent,
+				ActivityUuid: e.ActivityUuid,
+				EventId:      e.ID,
+				Event:        e.Event,
+				Payload:      json.RawMessage(e.Payload),
+			})
+		case <-ticker.C:
+			s.writeMu.Lock()
+			s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			err := s.conn.WriteMessage(websocket.PingMessage, nil)
+			s.writeMu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *session) send(msg serverMessage) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := s.conn.WriteJSON(msg); err != nil {
+		logging.FromContext(s.ctx).Errorf("[ws] writing to %s: %s", s.user, err)
+	}
+}
+
+// close says goodbye and ends the read loop of serve. Closing the hijacked connection itself does nothing, the
+// server only closes it once serve returns.
+func (s *session) close() {
+	s.writeMu.Lock()
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+	s.writeMu.Unlock()
+
+	s.conn.SetReadDeadline(time.Now())
+}
+
+// handleMessage runs every message as its own unit of work on ctx, in its own database session
+func (s *session) handleMessage(ctx context.Context, msg clientMessage) {
+	switch msg.Type {
+	case messageSubscribe:
+		activityGroup, err := s.h.svcActivityGroup.FindByUuid(ctx, dto.ActivityGroupUuidRequest{Uuid: msg.ActivityUuid})
+		if err != nil {
+			s.sendError(msg, err)
+			return
+		}
+		s.sub.Add(activityGroup.Uuid)
+		s.send(serverMessage{Type: messageSubscribed, Id: msg.Id, ActivityUuid: activityGroup.Uuid})
+	case messageUnsubscribe:
+		s.sub.Remove(msg.ActivityUuid)
+		s.send(serverMessage{Type: messageUnsubscribed, Id: msg.Id, ActivityUuid: msg.ActivityUuid})
+	case messageMutation:
+		logging.FromContext(ctx).Infof("[ws] %s mutation: %s %s", s.user, msg.Resource, msg.Action)
+
+		action, data, err := s.h.dispatch(ctx, msg)
+		if err != nil {
+			s.sendError(msg, err)
+			return
+		}
+		s.send(serverMessage{Type: messageResult, Id: msg.Id, Action: action, Data: data})
+	default:
+		s.send(serverMessage{Type: messageError, Id: msg.Id, Code: string(apperror.CodeInvalidMessage), Error: "unknown message type, should be subscribe, unsubscribe or mutation"})
+	}
+}
+
+func (s *session) sendError(msg clientMessage, err error) {
+	appErr := apperror.From(err)
+
+	var errorsData any = nil
+	if validationErrs := appErr.ValidationErrors(); validationErrs != nil {
+		errorsData = parserPkg.ValidationErrors(validationErrs, nil)
+	}
+
+	s.send(serverMessage{Type: messageError, Id: msg.Id, Action: msg.Action, Code: string(appErr.Code), Error: appErr.Message, Errors: errorsData})
+}
@@ -0,0 +1,203 @@
+package ws
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Adhiana46/go-restapi-template/internal/apperror"
+	"github.com/Adhiana46/go-restapi-template/internal/auth"
+	"github.com/Adhiana46/go-restapi-template/internal/dto"
+	"github.com/Adhiana46/go-restapi-template/internal/entity"
+	"github.com/Adhiana46/go-restapi-template/internal/service"
+	"github.com/Adhiana46/go-restapi-template/internal/stream"
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+// fakeActivityGroupService knows the activity group "activity" only
+type fakeActivityGroupService struct {
+	service.ActivityGroupService
+}
+
+func (s *fakeActivityGroupService) FindByUuid(ctx context.Context, req dto.ActivityGroupUuidRequest) (*entity.ActivityGroup, error) {
+	if req.Uuid != "activity" {
+		return nil, apperror.NotFound(apperror.CodeActivityGroupNotFound, "activity group not found")
+	}
+
+	return &entity.ActivityGroup{ID: 1, Uuid: req.Uuid}, nil
+}
+
+type fakeTodoItemService struct {
+	service.TodoItemService
+}
+
+func (s *fakeTodoItemService) Update(ctx context.Context, req dto.TodoItemUpdateRequest) (*entity.TodoItem, error) {
+	return &entity.TodoItem{Uuid: req.Uuid, Name: req.Name}, nil
+}
+
+// fakeActivityEventService serves whatever events a test commits, in id order like the event log
+type fakeActivityEventService struct {
+	service.ActivityEventService
+
+	mu     sync.Mutex
+	events []*entity.ActivityEvent
+}
+
+func (s *fakeActivityEventService) commit(e *entity.ActivityEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, e)
+	sort.Slice(s.events, func(i, j int) bool { return s.events[i].ID < s.events[j].ID })
+}
+
+func (s *fakeActivityEventService) LastId(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (s *fakeActivityEventService) FetchSince(ctx context.Context, afterId int64) ([]*entity.ActivityEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := []*entity.ActivityEvent{}
+	for _, e := range s.events {
+		if e.ID > afterId {
+			events = append(events, e)
+		}
+	}
+
+	return events, nil
+}
+
+// serveWs listens on a random local port and returns the url of the channel
+func serveWs(t *testing.T, hub stream.Hub) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %s", err)
+	}
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	NewHandler(auth.NewTokenAuthenticator(map[string]string{"alice": "secret"}), hub, &fakeActivityGroupService{}, &fakeTodoItemService{}).RegisterRoutes(app.Group("/ws"))
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	return "ws://" + ln.Addr().String() + "/ws"
+}
+
+func request(t *testing.T, conn *websocket.Conn, msg clientMessage) serverMessage {
+	t.Helper()
+
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatalf("writing %s: %s", msg.Type, err)
+	}
+
+	return receive(t, conn)
+}
+
+func receive(t *testing.T, conn *websocket.Conn) serverMessage {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var reply serverMessage
+	if err := conn.ReadJSON(&reply); err != nil {
+		t.Fatalf("reading: %s", err)
+	}
+
+	return reply
+}
+
+func TestChannelRejectsUnauthenticated(t *testing.T) {
+	url := serveWs(t, stream.NewHub(time.Second, time.Second, &fakeActivityEventService{}))
+
+	if conn, _, err := websocket.DefaultDialer.Dial(url+"?token=wrong", nil); err == nil {
+		conn.Close()
+		t.Fatal("connected with a wrong token")
+	}
+}
+
+func TestChannelSubscribesAndMutates(t *testing.T) {
+	events := &fakeActivityEventService{}
+	hub := stream.NewHub(10*time.Millisecond, time.Second, events)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	conn, _, err := websocket.DefaultDialer.Dial(serveWs(t, hub)+"?token=secret", nil)
+	if err != nil {
+		t.Fatalf("connecting: %s", err)
+	}
+	defer conn.Close()
+
+	reply := request(t, conn, clientMessage{Type: messageSubscribe, Id: "1", ActivityUuid: "missing"})
+	if reply.Type != messageError || reply.Id != "1" || reply.Code != string(apperror.CodeActivityGroupNotFound) {
+		t.Fatalf("subscribing to a missing group replied %+v", reply)
+	}
+
+	reply = request(t, conn, clientMessage{Type: messageSubscribe, Id: "2", ActivityUuid: "activity"})
+	if reply.Type != messageSubscribed || reply.Id != "2" || reply.ActivityUuid != "activity" {
+		t.Fatalf("subscribing replied %+v", reply)
+	}
+
+	// only events of subscribed groups are forwarded
+	events.commit(&entity.ActivityEvent{ID: 1, ActivityUuid: "other", Event: "todo-item.created", Payload: `{}`})
+	events.commit(&entity.ActivityEvent{ID: 2, ActivityUuid: "activity", Event: "todo-item.deleted", Payload: `{"uuid":"item"}`})
+	if e := receive(t, conn); e.Type != messageEvent || e.EventId != 2 || e.Event != "todo-item.deleted" || string(e.Payload) != `{"uuid":"item"}` {
+		t.Fatalf("forwarded %+v, want event 2", e)
+	}
+
+	reply = request(t, conn, clientMessage{Type: messageMutation, Id: "3", Resource: resourceTodoItem, Action: "update", Data: map[string]interface{}{"uuid": "item", "name": "stand-up"}})
+	if reply.Type != messageResult || reply.Id != "3" || reply.Action != "updated" {
+		t.Fatalf("mutation replied %+v", reply)
+	}
+	if data, _ := reply.Data.(map[string]interface{}); data["name"] != "stand-up" {
+		t.Fatalf("mutation replied data %v", reply.Data)
+	}
+
+	reply = request(t, conn, clientMessage{Type: messageMutation, Id: "4", Resource: "webhook", Action: "delete"})
+	if reply.Type != messageError || reply.Id != "4" || reply.Code != string(apperror.CodeInvalidMessage) {
+		t.Fatalf("unknown mutation replied %+v", reply)
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("subscribe")); err != nil {
+		t.Fatalf("writing: %s", err)
+	}
+	if reply := receive(t, conn); reply.Type != messageError || reply.Code != string(apperror.CodeInvalidMessage) {
+		t.Fatalf("malformed message replied %+v", reply)
+	}
+}
+
+// TestChannelResetOnShutdown makes sure a shut down hub tells the client where to resume and closes the channel
+func TestChannelResetOnShutdown(t *testing.T) {
+	events := &fakeActivityEventService{}
+	hub := stream.NewHub(10*time.Millisecond, time.Second, events)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+
+	conn, _, err := websocket.DefaultDialer.Dial(serveWs(t, hub)+"?token=secret", nil)
+	if err != nil {
+		t.Fatalf("connecting: %s", err)
+	}
+	defer conn.Close()
+
+	request(t, conn, clientMessage{Type: messageSubscribe, ActivityUuid: "activity"})
+	events.commit(&entity.ActivityEvent{ID: 1, ActivityUuid: "activity", Event: "todo-item.created", Payload: `{}`})
+	receive(t, conn)
+
+	hub.Shutdown()
+
+	if reply := receive(t, conn); reply.Type != messageError || reply.Code != string(apperror.CodeStreamReset) || reply.EventId != 1 {
+		t.Fatalf("shutdown sent %+v, want a reset after event 1", reply)
+	}
+	// well before the pong timeout would end it
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("reading after the reset: %v, want the channel closed", err)
+	}
+}
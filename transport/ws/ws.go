@@ -0,0 +1,52 @@
+package ws
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	messageSubscribe   = "subscribe"
+	messageUnsubscribe = "unsubscribe"
+	messageMutation    = "mutation"
+
+	messageSubscribed   = "subscribed"
+	messageUnsubscribed = "unsubscribed"
+	messageEvent        = "event"
+	messageResult       = "result"
+	messageError        = "error"
+
+	resourceActivityGroup = "activity-group"
+	resourceTodoItem      = "todo-item"
+)
+
+const (
+	writeTimeout = 10 * time.Second
+	pingInterval = 30 * time.Second
+	pongTimeout  = 60 * time.Second
+)
+
+// clientMessage carries the same action/data envelope as the queue request payload,
+// plus the resource it targets and a client chosen id echoed back in the reply
+type clientMessage struct {
+	Type         string                 `json:"type"`
+	Id           string                 `json:"id"`
+	ActivityUuid string                 `json:"activity_uuid"`
+	Resource     string                 `json:"resource"`
+	Action       string                 `json:"action"`
+	Data         map[string]interface{} `json:"data"`
+}
+
+type serverMessage struct {
+	Type         string          `json:"type"`
+	Id           string          `json:"id,omitempty"`
+	ActivityUuid string          `json:"activity_uuid,omitempty"`
+	EventId      int64           `json:"event_id,omitempty"`
+	Event        string          `json:"event,omitempty"`
+	Action       string          `json:"action,omitempty"`
+	Data         any             `json:"data,omitempty"`
+	Payload      json.RawMessage `json:"payload,omitempty"`
+	Code         string          `json:"code,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	Errors       any             `json:"errors,omitempty"`
+}